@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"starwars-api/config"
+	"starwars-api/database"
+	"starwars-api/services"
+	"strconv"
+)
+
+const usage = `Usage:
+  starwars-api                    Start the API server
+  starwars-api import swapi <dir> Import a SWAPI JSON dump into the catalog
+  starwars-api migrate up         Apply every pending migration
+  starwars-api migrate down [n]   Revert the last n migrations (default 1)
+  starwars-api migrate status     List migrations and when they were applied
+  starwars-api role <user> <role> Give a player the player, moderator or admin role`
+
+// Run runs a command-line subcommand and returns the process exit code
+func Run(args []string) int {
+	switch {
+	case len(args) == 3 && args[0] == "import" && args[1] == "swapi":
+		return importSWAPI(args[2])
+	case len(args) == 2 && args[0] == "migrate" && args[1] == "up":
+		return migrateUp()
+	case (len(args) == 2 || len(args) == 3) && args[0] == "migrate" && args[1] == "down":
+		steps := 1
+		if len(args) == 3 {
+			n, err := strconv.Atoi(args[2])
+			if err != nil || n < 1 {
+				fmt.Fprintln(os.Stderr, usage)
+				return 2
+			}
+			steps = n
+		}
+		return migrateDown(steps)
+	case len(args) == 2 && args[0] == "migrate" && args[1] == "status":
+		return migrateStatus()
+	case len(args) == 3 && args[0] == "role":
+		return setRole(args[1], args[2])
+	case args[0] == "help" || args[0] == "-h" || args[0] == "--help":
+		fmt.Println(usage)
+		return 0
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+}
+
+// loadConfig loads the server configuration, reporting why it is invalid
+func loadConfig() (*config.Config, bool) {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌", err)
+		return nil, false
+	}
+	return cfg, true
+}
+
+// importSWAPI upserts a SWAPI dump and prints what changed per resource
+func importSWAPI(dir string) int {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "❌ %s is not a directory\n", dir)
+		return 1
+	}
+
+	cfg, ok := loadConfig()
+	if !ok {
+		return 1
+	}
+	database.Initialize(cfg.Database)
+
+	report, err := database.ImportSWAPI(database.DB, dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ Import failed:", err)
+		return 1
+	}
+
+	names := make([]string, 0, len(report.Resources))
+	for name := range report.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("%-10s %8s %8s %8s\n", "resource", "created", "updated", "skipped")
+	for _, name := range names {
+		counts := report.Resources[name]
+		fmt.Printf("%-10s %8d %8d %8d\n", name, counts.Created, counts.Updated, counts.Skipped)
+	}
+	total := report.Total()
+	fmt.Printf("%-10s %8d %8d %8d\n", "total", total.Created, total.Updated, total.Skipped)
+	if report.Unresolved > 0 {
+		fmt.Printf("⚠️  %d relation URLs did not match any record\n", report.Unresolved)
+	}
+	return 0
+}
+
+// migrateUp applies the pending migrations
+func migrateUp() int {
+	cfg, ok := loadConfig()
+	if !ok {
+		return 1
+	}
+	if err := database.Connect(cfg.Database); err != nil {
+		fmt.Fprintln(os.Stderr, "❌ Failed to connect to database:", err)
+		return 1
+	}
+
+	applied, err := database.MigrateUp(database.DB)
+	for _, migration := range applied {
+		fmt.Printf("✅ Applied %04d %s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌", err)
+		return 1
+	}
+	if len(applied) == 0 {
+		fmt.Println("Database is up to date")
+	}
+	return 0
+}
+
+// migrateDown reverts the last migrations
+func migrateDown(steps int) int {
+	cfg, ok := loadConfig()
+	if !ok {
+		return 1
+	}
+	if err := database.Connect(cfg.Database); err != nil {
+		fmt.Fprintln(os.Stderr, "❌ Failed to connect to database:", err)
+		return 1
+	}
+
+	reverted, err := database.MigrateDown(database.DB, steps)
+	for _, migration := range reverted {
+		fmt.Printf("↩️  Reverted %04d %s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌", err)
+		return 1
+	}
+	if len(reverted) == 0 {
+		fmt.Println("No migrations to revert")
+	}
+	return 0
+}
+
+// migrateStatus prints every migration and when it was applied
+func migrateStatus() int {
+	cfg, ok := loadConfig()
+	if !ok {
+		return 1
+	}
+	if err := database.Connect(cfg.Database); err != nil {
+		fmt.Fprintln(os.Stderr, "❌ Failed to connect to database:", err)
+		return 1
+	}
+
+	statuses, err := database.MigrationStatuses(database.DB)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌", err)
+		return 1
+	}
+
+	fmt.Printf("%-8s %-30s %s\n", "version", "name", "applied")
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d     %-30s %s\n", status.Version, status.Name, applied)
+	}
+
+	if err := database.CheckMigrations(database.DB); err != nil {
+		fmt.Println("⚠️ ", err)
+	}
+	return 0
+}
+
+// setRole gives a player a role; this is how the first admin is made
+func setRole(username, role string) int {
+	cfg, ok := loadConfig()
+	if !ok {
+		return 1
+	}
+	if err := database.Connect(cfg.Database); err != nil {
+		fmt.Fprintln(os.Stderr, "❌ Failed to connect to database:", err)
+		return 1
+	}
+
+	accessService := services.NewAccessService(database.DB)
+	playerID, err := accessService.FindPlayerID(username)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌", err)
+		return 1
+	}
+	if _, err := accessService.SetPlayerRole(playerID, role); err != nil {
+		fmt.Fprintln(os.Stderr, "❌", err)
+		return 1
+	}
+
+	fmt.Printf("✅ %s is now %s\n", username, role)
+	return 0
+}
@@ -0,0 +1,477 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets in the redacted view of the configuration
+const redacted = "[redacted]"
+
+// Config is the configuration of the server and of every subsystem. It is
+// loaded from the defaults, then the file named by CONFIG_FILE, then the
+// environment, and validated as a whole.
+type Config struct {
+	File      string          `json:"file,omitempty" yaml:"-" toml:"-"`
+	Server    ServerConfig    `json:"server" yaml:"server" toml:"server"`
+	Database  DatabaseConfig  `json:"database" yaml:"database" toml:"database"`
+	CORS      CORSConfig      `json:"cors" yaml:"cors" toml:"cors"`
+	RateLimit RateLimitConfig `json:"rate_limit" yaml:"rate_limit" toml:"rate_limit"`
+	Cache     CacheConfig     `json:"cache" yaml:"cache" toml:"cache"`
+	API       APIConfig       `json:"api" yaml:"api" toml:"api"`
+	Auth      AuthConfig      `json:"auth" yaml:"auth" toml:"auth"`
+	Mail      MailConfig      `json:"mail" yaml:"mail" toml:"mail"`
+	Game      GameConfig      `json:"game" yaml:"game" toml:"game"`
+}
+
+type ServerConfig struct {
+	Port      int    `json:"port" yaml:"port" toml:"port"`
+	Mode      string `json:"mode" yaml:"mode" toml:"mode"`                   // Gin mode: debug, release or test
+	PublicURL string `json:"public_url" yaml:"public_url" toml:"public_url"` // base URL of pagination and email links; required by the smtp mail driver
+}
+
+type DatabaseConfig struct {
+	Driver          string   `json:"driver" yaml:"driver" toml:"driver"` // sqlite or postgres
+	URL             string   `json:"url" yaml:"url" toml:"url"`
+	MaxOpenConns    int      `json:"max_open_conns" yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int      `json:"max_idle_conns" yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+	LogLevel        string   `json:"log_level" yaml:"log_level" toml:"log_level"` // silent, error, warn or info
+	MigrateOnStart  bool     `json:"migrate_on_start" yaml:"migrate_on_start" toml:"migrate_on_start"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string `json:"allowed_origins" yaml:"allowed_origins" toml:"allowed_origins"`
+}
+
+type RateLimitConfig struct {
+	Requests int      `json:"requests" yaml:"requests" toml:"requests"` // per client and window; 0 disables rate limiting
+	Window   Duration `json:"window" yaml:"window" toml:"window"`
+}
+
+type CacheConfig struct {
+	CatalogMaxAge Duration `json:"catalog_max_age" yaml:"catalog_max_age" toml:"catalog_max_age"`
+}
+
+type APIConfig struct {
+	ExpandMaxDepth int `json:"expand_max_depth" yaml:"expand_max_depth" toml:"expand_max_depth"`
+}
+
+// AuthConfig controls player passwords and session tokens
+type AuthConfig struct {
+	TokenSecret     string   `json:"token_secret" yaml:"token_secret" toml:"token_secret"` // signs access tokens; empty uses a random secret per process
+	AccessTokenTTL  Duration `json:"access_token_ttl" yaml:"access_token_ttl" toml:"access_token_ttl"`
+	RefreshTokenTTL Duration `json:"refresh_token_ttl" yaml:"refresh_token_ttl" toml:"refresh_token_ttl"`
+	BcryptCost      int      `json:"bcrypt_cost" yaml:"bcrypt_cost" toml:"bcrypt_cost"`
+
+	EmailVerificationTTL Duration `json:"email_verification_ttl" yaml:"email_verification_ttl" toml:"email_verification_ttl"`
+	PasswordResetTTL     Duration `json:"password_reset_ttl" yaml:"password_reset_ttl" toml:"password_reset_ttl"`
+	EmailResendInterval  Duration `json:"email_resend_interval" yaml:"email_resend_interval" toml:"email_resend_interval"` // minimum time between two emails of a kind to a player
+}
+
+// MailConfig controls how account emails are sent
+type MailConfig struct {
+	Driver           string     `json:"driver" yaml:"driver" toml:"driver"` // log, file or smtp
+	From             string     `json:"from" yaml:"from" toml:"from"`
+	Dir              string     `json:"dir" yaml:"dir" toml:"dir"` // where the file driver writes messages
+	SMTP             SMTPConfig `json:"smtp" yaml:"smtp" toml:"smtp"`
+	ResetPasswordURL string     `json:"reset_password_url" yaml:"reset_password_url" toml:"reset_password_url"` // page where players choose a new password; the token is added as ?token=
+}
+
+type SMTPConfig struct {
+	Host     string `json:"host" yaml:"host" toml:"host"`
+	Port     int    `json:"port" yaml:"port" toml:"port"`
+	Username string `json:"username" yaml:"username" toml:"username"`
+	Password string `json:"password" yaml:"password" toml:"password"`
+}
+
+// GameConfig holds the balance of the player game
+type GameConfig struct {
+	StartingCredits         int           `json:"starting_credits" yaml:"starting_credits" toml:"starting_credits"`
+	Levels                  []LevelConfig `json:"levels" yaml:"levels" toml:"levels"`                                                       // the leveling curve, from level 1 to the highest level
+	QuizSpeedBonusPoints    int           `json:"quiz_speed_bonus_points" yaml:"quiz_speed_bonus_points" toml:"quiz_speed_bonus_points"`    // extra points for a quick correct answer
+	QuizSpeedBonusSeconds   int           `json:"quiz_speed_bonus_seconds" yaml:"quiz_speed_bonus_seconds" toml:"quiz_speed_bonus_seconds"` // answers faster than this are quick
+	QuizPointsPerExperience int           `json:"quiz_points_per_experience" yaml:"quiz_points_per_experience" toml:"quiz_points_per_experience"`
+}
+
+// LevelConfig is a level of the leveling curve and the rewards for reaching
+// it. Ships whose template requires the level are unlocked with it.
+type LevelConfig struct {
+	Level       int             `json:"level" yaml:"level" toml:"level"`
+	Experience  int             `json:"experience" yaml:"experience" toml:"experience"` // total experience needed to reach the level
+	Credits     int             `json:"credits" yaml:"credits" toml:"credits"`
+	Materials   MaterialsConfig `json:"materials" yaml:"materials" toml:"materials"`
+	HangarSlots int             `json:"hangar_slots" yaml:"hangar_slots" toml:"hangar_slots"`
+}
+
+type MaterialsConfig struct {
+	Durasteel      int `json:"durasteel" yaml:"durasteel" toml:"durasteel"`
+	Transparisteel int `json:"transparisteel" yaml:"transparisteel" toml:"transparisteel"`
+	Tibanna        int `json:"tibanna" yaml:"tibanna" toml:"tibanna"`
+	Kyber          int `json:"kyber" yaml:"kyber" toml:"kyber"`
+}
+
+// Duration is a time.Duration written as a string such as "30s" or "5m"
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", text)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// InvalidConfigError lists every problem found in a configuration
+type InvalidConfigError struct {
+	Problems []string
+}
+
+func (e *InvalidConfigError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Default returns the configuration used for anything a file or the
+// environment does not set, suited to local development
+func Default() Config {
+	return Config{
+		Server: ServerConfig{Port: 8080, Mode: "debug"},
+		Database: DatabaseConfig{
+			Driver:       "sqlite",
+			URL:          "starwars.db",
+			MaxIdleConns: 2,
+			LogLevel:     "info",
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"http://localhost:4200", "http://127.0.0.1:4200", "https://localhost:4200"},
+		},
+		RateLimit: RateLimitConfig{Requests: 100, Window: Duration(time.Minute)},
+		Cache:     CacheConfig{CatalogMaxAge: Duration(5 * time.Minute)},
+		API:       APIConfig{ExpandMaxDepth: 3},
+		Auth: AuthConfig{
+			AccessTokenTTL:  Duration(15 * time.Minute),
+			RefreshTokenTTL: Duration(30 * 24 * time.Hour),
+			BcryptCost:      10,
+
+			EmailVerificationTTL: Duration(48 * time.Hour),
+			PasswordResetTTL:     Duration(time.Hour),
+			EmailResendInterval:  Duration(time.Minute),
+		},
+		Mail: MailConfig{
+			Driver:           "log",
+			From:             "Star Wars API <no-reply@localhost>",
+			Dir:              "mail",
+			SMTP:             SMTPConfig{Port: 587},
+			ResetPasswordURL: "http://localhost:4200/reset-password",
+		},
+		Game: GameConfig{
+			StartingCredits:         100,
+			Levels:                  defaultLevels(),
+			QuizSpeedBonusPoints:    5,
+			QuizSpeedBonusSeconds:   10,
+			QuizPointsPerExperience: 10,
+		},
+	}
+}
+
+// defaultLevels returns 50 levels where level n+1 needs n² × 100 experience.
+// Every level awards 50 credits and 10 durasteel, every fifth level also 5
+// transparisteel, 5 tibanna and a hangar slot, and every tenth a kyber crystal.
+func defaultLevels() []LevelConfig {
+	levels := make([]LevelConfig, 50)
+	for i := range levels {
+		level := LevelConfig{Level: i + 1, Experience: i * i * 100}
+		if level.Level > 1 {
+			level.Credits = 50
+			level.Materials.Durasteel = 10
+		}
+		if level.Level%5 == 0 {
+			level.Materials.Transparisteel = 5
+			level.Materials.Tibanna = 5
+			level.HangarSlots = 1
+		}
+		if level.Level%10 == 0 {
+			level.Materials.Kyber = 1
+		}
+		levels[i] = level
+	}
+	return levels
+}
+
+// Load reads the configuration file named by CONFIG_FILE, if any, applies
+// the environment overrides and validates the result
+func Load() (*Config, error) {
+	config := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := config.readFile(path); err != nil {
+			return nil, err
+		}
+		config.File = path
+	}
+
+	var problems []string
+	problems = append(problems, config.applyEnv()...)
+	problems = append(problems, config.validate()...)
+	if len(problems) > 0 {
+		return nil, &InvalidConfigError{Problems: problems}
+	}
+	return &config, nil
+}
+
+// readFile merges a YAML or TOML file, chosen by extension, into the
+// configuration; unknown keys are rejected so typos do not go unnoticed
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	case ".toml":
+		metadata, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("failed to parse %s: unknown key %s", path, undecoded[0])
+		}
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+	return nil
+}
+
+// applyEnv overrides the configuration from the environment and returns the
+// variables that could not be parsed
+func (c *Config) applyEnv() []string {
+	var problems []string
+
+	envString := func(name string, target *string) {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			*target = value
+		}
+	}
+	envInt := func(name string, target *int) {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s must be an integer, got %q", name, value))
+				return
+			}
+			*target = n
+		}
+	}
+	envBool := func(name string, target *bool) {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s must be true or false, got %q", name, value))
+				return
+			}
+			*target = b
+		}
+	}
+	envDuration := func(name string, target *Duration) {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			if err := target.UnmarshalText([]byte(value)); err != nil {
+				problems = append(problems, fmt.Sprintf("%s must be a duration such as 30m, got %q", name, value))
+			}
+		}
+	}
+
+	envInt("PORT", &c.Server.Port)
+	envString("GIN_MODE", &c.Server.Mode)
+	envString("PUBLIC_URL", &c.Server.PublicURL)
+
+	envString("DATABASE_DRIVER", &c.Database.Driver)
+	envString("DATABASE_URL", &c.Database.URL)
+	envInt("DATABASE_MAX_OPEN_CONNS", &c.Database.MaxOpenConns)
+	envInt("DATABASE_MAX_IDLE_CONNS", &c.Database.MaxIdleConns)
+	envDuration("DATABASE_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime)
+	envString("DATABASE_LOG_LEVEL", &c.Database.LogLevel)
+	envBool("MIGRATE_ON_START", &c.Database.MigrateOnStart)
+
+	if value := os.Getenv("CORS_ORIGINS"); value != "" {
+		c.CORS.AllowedOrigins = nil
+		for _, origin := range strings.Split(value, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				c.CORS.AllowedOrigins = append(c.CORS.AllowedOrigins, origin)
+			}
+		}
+	}
+
+	envInt("RATE_LIMIT_REQUESTS", &c.RateLimit.Requests)
+	envDuration("RATE_LIMIT_WINDOW", &c.RateLimit.Window)
+	envDuration("CACHE_MAX_AGE", &c.Cache.CatalogMaxAge)
+	envInt("EXPAND_MAX_DEPTH", &c.API.ExpandMaxDepth)
+	envString("AUTH_TOKEN_SECRET", &c.Auth.TokenSecret)
+	envDuration("AUTH_ACCESS_TOKEN_TTL", &c.Auth.AccessTokenTTL)
+	envDuration("AUTH_REFRESH_TOKEN_TTL", &c.Auth.RefreshTokenTTL)
+
+	envString("MAIL_DRIVER", &c.Mail.Driver)
+	envString("MAIL_FROM", &c.Mail.From)
+	envString("MAIL_DIR", &c.Mail.Dir)
+	envString("MAIL_RESET_PASSWORD_URL", &c.Mail.ResetPasswordURL)
+	envString("SMTP_HOST", &c.Mail.SMTP.Host)
+	envInt("SMTP_PORT", &c.Mail.SMTP.Port)
+	envString("SMTP_USERNAME", &c.Mail.SMTP.Username)
+	envString("SMTP_PASSWORD", &c.Mail.SMTP.Password)
+
+	return problems
+}
+
+// validate returns every problem with the configuration
+func (c *Config) validate() []string {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port < 65536, "server.port must be between 1 and 65535")
+	check(oneOf(c.Server.Mode, "debug", "release", "test"), "server.mode must be debug, release or test")
+	if c.Server.PublicURL != "" {
+		public, err := url.Parse(c.Server.PublicURL)
+		check(err == nil && (public.Scheme == "http" || public.Scheme == "https") && public.Host != "",
+			"server.public_url must be an absolute http or https URL")
+	}
+
+	c.Database.Driver = strings.ToLower(c.Database.Driver)
+	check(oneOf(c.Database.Driver, "sqlite", "postgres"), "database.driver must be sqlite or postgres")
+	check(c.Database.URL != "", "database.url is required")
+	check(c.Database.MaxOpenConns >= 0, "database.max_open_conns must not be negative")
+	check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns must not be negative")
+	check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime must not be negative")
+	c.Database.LogLevel = strings.ToLower(c.Database.LogLevel)
+	check(oneOf(c.Database.LogLevel, "silent", "error", "warn", "info"), "database.log_level must be silent, error, warn or info")
+
+	check(len(c.CORS.AllowedOrigins) > 0, "cors.allowed_origins must list at least one origin")
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			check(len(c.CORS.AllowedOrigins) == 1, "cors.allowed_origins: \"*\" cannot be combined with other origins")
+			continue
+		}
+		parsed, err := url.Parse(origin)
+		check(err == nil && parsed.Scheme != "" && parsed.Host != "" && parsed.Path == "",
+			"cors.allowed_origins: %q is not an origin such as https://example.com", origin)
+	}
+
+	check(c.RateLimit.Requests >= 0, "rate_limit.requests must not be negative")
+	check(c.RateLimit.Requests == 0 || c.RateLimit.Window > 0, "rate_limit.window must be positive")
+	check(c.Cache.CatalogMaxAge >= 0, "cache.catalog_max_age must not be negative")
+	check(c.API.ExpandMaxDepth > 0, "api.expand_max_depth must be at least 1")
+
+	if c.Auth.TokenSecret == "" {
+		check(c.Server.Mode != "release", "auth.token_secret is required in release mode")
+	} else {
+		check(len(c.Auth.TokenSecret) >= 32, "auth.token_secret must be at least 32 characters")
+	}
+	check(c.Auth.AccessTokenTTL > 0, "auth.access_token_ttl must be positive")
+	check(c.Auth.RefreshTokenTTL >= c.Auth.AccessTokenTTL, "auth.refresh_token_ttl must be at least auth.access_token_ttl")
+	check(c.Auth.BcryptCost >= 4 && c.Auth.BcryptCost <= 31, "auth.bcrypt_cost must be between 4 and 31")
+	check(c.Auth.EmailVerificationTTL > 0, "auth.email_verification_ttl must be positive")
+	check(c.Auth.PasswordResetTTL > 0, "auth.password_reset_ttl must be positive")
+	check(c.Auth.EmailResendInterval >= 0, "auth.email_resend_interval must not be negative")
+
+	c.Mail.Driver = strings.ToLower(c.Mail.Driver)
+	check(oneOf(c.Mail.Driver, "log", "file", "smtp"), "mail.driver must be log, file or smtp")
+	_, err := mail.ParseAddress(c.Mail.From)
+	check(err == nil, "mail.from must be an address such as Name <name@example.com>")
+	check(c.Mail.Driver != "file" || c.Mail.Dir != "", "mail.dir is required by the file driver")
+	if c.Mail.Driver == "smtp" {
+		check(c.Server.PublicURL != "", "server.public_url is required by the smtp driver, for the links in emails")
+		check(c.Mail.SMTP.Host != "", "mail.smtp.host is required by the smtp driver")
+		check(c.Mail.SMTP.Port > 0 && c.Mail.SMTP.Port < 65536, "mail.smtp.port must be between 1 and 65535")
+	}
+	resetURL, err := url.Parse(c.Mail.ResetPasswordURL)
+	check(err == nil && (resetURL.Scheme == "http" || resetURL.Scheme == "https") && resetURL.Host != "",
+		"mail.reset_password_url must be an absolute http or https URL")
+
+	check(c.Game.StartingCredits >= 0, "game.starting_credits must not be negative")
+	check(len(c.Game.Levels) > 0, "game.levels must list at least level 1")
+	for i, level := range c.Game.Levels {
+		check(level.Level == i+1, "game.levels[%d]: levels must be listed in order from 1, got level %d", i, level.Level)
+		if i == 0 {
+			check(level.Experience == 0, "game.levels[0]: level 1 must need 0 experience")
+		} else {
+			check(level.Experience > c.Game.Levels[i-1].Experience, "game.levels[%d]: level %d must need more experience than level %d", i, level.Level, c.Game.Levels[i-1].Level)
+		}
+		check(level.Credits >= 0 && level.HangarSlots >= 0 && level.Materials.Durasteel >= 0 && level.Materials.Transparisteel >= 0 &&
+			level.Materials.Tibanna >= 0 && level.Materials.Kyber >= 0, "game.levels[%d]: rewards must not be negative", i)
+	}
+	check(c.Game.QuizSpeedBonusPoints >= 0, "game.quiz_speed_bonus_points must not be negative")
+	check(c.Game.QuizSpeedBonusSeconds >= 0, "game.quiz_speed_bonus_seconds must not be negative")
+	check(c.Game.QuizPointsPerExperience > 0, "game.quiz_points_per_experience must be positive")
+
+	return problems
+}
+
+// BaseURL returns the URL clients reach the server at, for links in emails:
+// server.public_url, or the local port in development
+func (s ServerConfig) BaseURL() string {
+	if s.PublicURL != "" {
+		return strings.TrimSuffix(s.PublicURL, "/")
+	}
+	return fmt.Sprintf("http://localhost:%d", s.Port)
+}
+
+// Redacted returns a copy of the configuration that is safe to show, with
+// the token secret and database and SMTP passwords hidden
+func (c *Config) Redacted() Config {
+	view := *c
+	view.CORS.AllowedOrigins = append([]string(nil), c.CORS.AllowedOrigins...)
+	if view.Auth.TokenSecret != "" {
+		view.Auth.TokenSecret = redacted
+	}
+	if view.Mail.SMTP.Password != "" {
+		view.Mail.SMTP.Password = redacted
+	}
+	view.Database.URL = redactDatabaseURL(view.Database.URL)
+	return view
+}
+
+// dsnPassword matches the password of a key=value PostgreSQL DSN
+var dsnPassword = regexp.MustCompile(`(password=)('[^']*'|\S+)`)
+
+// redactDatabaseURL hides the password of a PostgreSQL URL or DSN
+func redactDatabaseURL(raw string) string {
+	if parsed, err := url.Parse(raw); err == nil && parsed.User != nil {
+		return parsed.Redacted()
+	}
+	return dsnPassword.ReplaceAllString(raw, "${1}"+redacted)
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return true
+		}
+	}
+	return false
+}
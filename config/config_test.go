@@ -0,0 +1,249 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// configEnv are the environment variables Load reads
+var configEnv = []string{
+	"CONFIG_FILE", "PORT", "GIN_MODE", "PUBLIC_URL",
+	"DATABASE_DRIVER", "DATABASE_URL", "DATABASE_MAX_OPEN_CONNS", "DATABASE_MAX_IDLE_CONNS",
+	"DATABASE_CONN_MAX_LIFETIME", "DATABASE_LOG_LEVEL", "MIGRATE_ON_START", "CORS_ORIGINS",
+	"RATE_LIMIT_REQUESTS", "RATE_LIMIT_WINDOW", "CACHE_MAX_AGE", "EXPAND_MAX_DEPTH",
+	"AUTH_TOKEN_SECRET", "AUTH_ACCESS_TOKEN_TTL", "AUTH_REFRESH_TOKEN_TTL",
+	"MAIL_DRIVER", "MAIL_FROM", "MAIL_DIR", "MAIL_RESET_PASSWORD_URL",
+	"SMTP_HOST", "SMTP_PORT", "SMTP_USERNAME", "SMTP_PASSWORD",
+}
+
+// clearEnv unsets the configuration environment for the rest of a test
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range configEnv {
+		t.Setenv(name, "")
+	}
+}
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(c *Config)
+		problems []string
+	}{
+		{
+			name:   "defaults",
+			change: func(c *Config) {},
+		},
+		{
+			name:     "release mode without token secret",
+			change:   func(c *Config) { c.Server.Mode = "release" },
+			problems: []string{"auth.token_secret is required in release mode"},
+		},
+		{
+			name: "release mode with token secret",
+			change: func(c *Config) {
+				c.Server.Mode = "release"
+				c.Auth.TokenSecret = testSecret
+			},
+		},
+		{
+			name:     "short token secret",
+			change:   func(c *Config) { c.Auth.TokenSecret = "short" },
+			problems: []string{"auth.token_secret must be at least 32 characters"},
+		},
+		{
+			name: "unknown mode and port out of range",
+			change: func(c *Config) {
+				c.Server.Mode = "production"
+				c.Server.Port = 70000
+			},
+			problems: []string{
+				"server.port must be between 1 and 65535",
+				"server.mode must be debug, release or test",
+			},
+		},
+		{
+			name:     "relative public URL",
+			change:   func(c *Config) { c.Server.PublicURL = "/api" },
+			problems: []string{"server.public_url must be an absolute http or https URL"},
+		},
+		{
+			name:   "database driver is case-insensitive",
+			change: func(c *Config) { c.Database.Driver = "Postgres" },
+		},
+		{
+			name:     "unknown database driver",
+			change:   func(c *Config) { c.Database.Driver = "mysql" },
+			problems: []string{"database.driver must be sqlite or postgres"},
+		},
+		{
+			name:     "wildcard origin with other origins",
+			change:   func(c *Config) { c.CORS.AllowedOrigins = []string{"*", "https://example.com"} },
+			problems: []string{`cors.allowed_origins: "*" cannot be combined with other origins`},
+		},
+		{
+			name: "refresh tokens shorter-lived than access tokens",
+			change: func(c *Config) {
+				c.Auth.RefreshTokenTTL = c.Auth.AccessTokenTTL - 1
+			},
+			problems: []string{"auth.refresh_token_ttl must be at least auth.access_token_ttl"},
+		},
+		{
+			name:     "smtp without public URL or host",
+			change:   func(c *Config) { c.Mail.Driver = "smtp" },
+			problems: []string{"server.public_url is required by the smtp driver, for the links in emails", "mail.smtp.host is required by the smtp driver"},
+		},
+		{
+			name: "smtp with public URL and host",
+			change: func(c *Config) {
+				c.Mail.Driver = "SMTP"
+				c.Mail.SMTP.Host = "smtp.example.com"
+				c.Server.PublicURL = "https://api.example.com"
+			},
+		},
+		{
+			name: "file mail driver without directory",
+			change: func(c *Config) {
+				c.Mail.Driver = "file"
+				c.Mail.Dir = ""
+			},
+			problems: []string{"mail.dir is required by the file driver"},
+		},
+		{
+			name:     "no levels",
+			change:   func(c *Config) { c.Game.Levels = nil },
+			problems: []string{"game.levels must list at least level 1"},
+		},
+		{
+			name: "levels out of order",
+			change: func(c *Config) {
+				c.Game.Levels = []LevelConfig{{Level: 1}, {Level: 3, Experience: 100}}
+			},
+			problems: []string{"game.levels[1]: levels must be listed in order from 1, got level 3"},
+		},
+		{
+			name: "level 1 needs experience",
+			change: func(c *Config) {
+				c.Game.Levels = []LevelConfig{{Level: 1, Experience: 10}}
+			},
+			problems: []string{"game.levels[0]: level 1 must need 0 experience"},
+		},
+		{
+			name: "experience does not increase",
+			change: func(c *Config) {
+				c.Game.Levels = []LevelConfig{{Level: 1}, {Level: 2, Experience: 100}, {Level: 3, Experience: 100}}
+			},
+			problems: []string{"game.levels[2]: level 3 must need more experience than level 2"},
+		},
+		{
+			name: "negative level rewards",
+			change: func(c *Config) {
+				c.Game.Levels = []LevelConfig{{Level: 1}, {Level: 2, Experience: 100, Materials: MaterialsConfig{Kyber: -1}}}
+			},
+			problems: []string{"game.levels[1]: rewards must not be negative"},
+		},
+		{
+			name: "every problem is reported",
+			change: func(c *Config) {
+				c.Database.URL = ""
+				c.API.ExpandMaxDepth = 0
+				c.Game.QuizPointsPerExperience = 0
+			},
+			problems: []string{
+				"database.url is required",
+				"api.expand_max_depth must be at least 1",
+				"game.quiz_points_per_experience must be positive",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			tt.change(&config)
+			if problems := config.validate(); !reflect.DeepEqual(problems, tt.problems) {
+				t.Errorf("validate() = %q, want %q", problems, tt.problems)
+			}
+		})
+	}
+}
+
+func TestLoadExampleConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		mode    string
+		problem string // a problem Load must report, empty if it must succeed
+	}{
+		{
+			name: "as shipped",
+			mode: "debug",
+		},
+		{
+			name: "release mode with token secret",
+			env:  map[string]string{"GIN_MODE": "release", "AUTH_TOKEN_SECRET": testSecret},
+			mode: "release",
+		},
+		{
+			name:    "release mode without token secret",
+			env:     map[string]string{"GIN_MODE": "release"},
+			problem: "auth.token_secret is required in release mode",
+		},
+		{
+			name:    "smtp without public URL",
+			env:     map[string]string{"MAIL_DRIVER": "smtp", "SMTP_HOST": "smtp.example.com"},
+			problem: "server.public_url is required by the smtp driver",
+		},
+		{
+			name:    "unparsable environment",
+			env:     map[string]string{"PORT": "eighty"},
+			problem: `PORT must be an integer, got "eighty"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("CONFIG_FILE", "../config.example.yaml")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			config, err := Load()
+			if tt.problem != "" {
+				var invalid *InvalidConfigError
+				if !errors.As(err, &invalid) || !strings.Contains(err.Error(), tt.problem) {
+					t.Fatalf("Load() error = %v, want a problem %q", err, tt.problem)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if config.Server.Mode != tt.mode {
+				t.Errorf("server.mode = %q, want %q", config.Server.Mode, tt.mode)
+			}
+			if config.File != "../config.example.yaml" {
+				t.Errorf("file = %q, want the example file", config.File)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	clearEnv(t)
+	path := t.TempDir() + "/config.yaml"
+	if err := os.WriteFile(path, []byte("server:\n  port: 8080\nadmin:\n  token: secret\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "field admin not found") {
+		t.Errorf("Load() error = %v, want the unknown admin key reported", err)
+	}
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"log"
+	"starwars-api/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// catalogVersion is the single row counting writes to the catalog, so that
+// cached catalog responses can be validated without re-running their queries
+type catalogVersion struct {
+	ID         uint `gorm:"primaryKey"`
+	Version    uint64
+	ModifiedAt time.Time
+}
+
+func (catalogVersion) TableName() string {
+	return "catalog_version"
+}
+
+// catalogModels are the models whose tables and join tables make up the
+// catalog, translations included
+var catalogModels = []interface{}{
+	&models.Character{}, &models.Film{}, &models.Species{}, &models.Starship{}, &models.Vehicle{},
+	&models.Planet{}, &models.Organization{}, &models.Weapon{}, &models.Event{}, &models.Translation{},
+}
+
+// catalogTables holds the catalog tables and their many2many join tables
+var catalogTables = make(map[string]bool)
+
+// SetupCatalogVersion registers the callbacks that bump the version row,
+// created by the initial schema migration, on every catalog write, relation
+// changes included
+func SetupCatalogVersion(db *gorm.DB) error {
+	for _, model := range catalogModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		catalogTables[stmt.Schema.Table] = true
+		for _, relationship := range stmt.Schema.Relationships.Many2Many {
+			catalogTables[relationship.JoinTable.Table] = true
+		}
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Register("catalog:version_create", bumpCatalogVersion); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("catalog:version_update", bumpCatalogVersion); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Register("catalog:version_delete", bumpCatalogVersion)
+}
+
+// CatalogVersion returns the catalog's write counter and the time of the last write
+func CatalogVersion(db *gorm.DB) (uint64, time.Time, error) {
+	var current catalogVersion
+	if err := db.Take(&current, 1).Error; err != nil {
+		return 0, time.Time{}, err
+	}
+	return current.Version, current.ModifiedAt, nil
+}
+
+// bumpCatalogVersion records a write that changed catalog rows
+func bumpCatalogVersion(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.RowsAffected == 0 {
+		return
+	}
+	table := tx.Statement.Table
+	if table == "" && tx.Statement.Schema != nil {
+		table = tx.Statement.Schema.Table
+	}
+	if !catalogTables[table] {
+		return
+	}
+
+	session := tx.Session(&gorm.Session{NewDB: true})
+	err := session.Model(&catalogVersion{}).Where("id = ?", 1).UpdateColumns(map[string]interface{}{
+		"version":     gorm.Expr("version + 1"),
+		"modified_at": time.Now(),
+	}).Error
+	if err != nil {
+		log.Printf("Error bumping catalog version after write to %s: %v", table, err)
+	}
+}
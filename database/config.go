@@ -0,0 +1,82 @@
+package database
+
+import (
+	"fmt"
+	"starwars-api/config"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Supported database drivers
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// MemoryDatabase is the SQLite URL of a database that lives in memory only,
+// shared by the connections of the pool and gone when the process exits
+const MemoryDatabase = ":memory:"
+
+// logLevels maps configured log levels to GORM log levels
+var logLevels = map[string]logger.LogLevel{
+	"silent": logger.Silent,
+	"error":  logger.Error,
+	"warn":   logger.Warn,
+	"info":   logger.Info,
+}
+
+// Open connects to the configured database
+func Open(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case DriverSQLite:
+		url := cfg.URL
+		if url == MemoryDatabase {
+			// Every connection to a plain :memory: database gets its own empty
+			// database, so the pool shares one through the shared cache
+			url = "file::memory:?cache=shared"
+		}
+		dialector = sqlite.Open(url)
+	case DriverPostgres:
+		dialector = postgres.Open(cfg.URL)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+
+	level, ok := logLevels[cfg.LogLevel]
+	if !ok {
+		return nil, fmt.Errorf("unsupported database log level %q", cfg.LogLevel)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(level),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime))
+	if cfg.Driver == DriverSQLite && cfg.URL == MemoryDatabase {
+		// The in-memory database is dropped with its last connection
+		sqlDB.SetMaxIdleConns(max(cfg.MaxIdleConns, 1))
+		sqlDB.SetConnMaxLifetime(0)
+	}
+
+	return db, nil
+}
+
+// IsSQLite reports whether db is a SQLite database, for the few features such
+// as full-text search and snapshots that only SQLite provides
+func IsSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == DriverSQLite
+}
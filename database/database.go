@@ -2,91 +2,57 @@ package database
 
 import (
 	"log"
+	"starwars-api/config"
 	"starwars-api/models"
 
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
 
-// Initialize sets up the database connection and runs migrations
-func Initialize() {
+// Connect opens the configured database
+func Connect(cfg config.DatabaseConfig) error {
 	var err error
+	DB, err = Open(cfg)
+	return err
+}
 
-	// Connect to SQLite database
-	DB, err = gorm.Open(sqlite.Open("starwars.db"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
-	if err != nil {
+// Initialize connects to the database, checks that it is migrated and seeds
+// it. Pending migrations are applied first with cfg.MigrateOnStart, for
+// development databases.
+func Initialize(cfg config.DatabaseConfig) {
+	if err := Connect(cfg); err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	// Auto-migrate the schema
-	err = DB.AutoMigrate(
-		&models.Character{},
-		&models.Film{},
-		&models.Species{},
-		&models.Starship{},
-		&models.Vehicle{},
-		&models.Planet{},
-		&models.Organization{},
-		&models.Weapon{},
-		&models.Event{},
-		// Mission models
-		&models.Mission{},
-		&models.MissionObjective{},
-		&models.MissionProgress{},
-		&models.MissionTemplate{},
-		&models.MissionEvent{},
-		&models.MissionReward{},
-		&models.BrightDataMissionSync{},
-		// Game models (existing)
-		&models.Player{},
-		&models.PlayerStats{},
-		&models.QuizQuestion{},
-		&models.GameSession{},
-		&models.GameCard{},
-		&models.PlayerCard{},
-		&models.CardPack{},
-		&models.Battle{},
-		&models.Artifact{},
-		&models.PlayerArtifact{},
-		&models.Achievement{},
-		&models.PlayerAchievement{},
-		&models.QuizSession{},
-		&models.QuizAnswer{},
-		// New fleet models
-		&models.Ship{},
-		&models.Fleet{},
-		&models.ShipUpgrade{},
-		&models.Hangar{},
-		&models.ShipTemplate{},
-		// New battle models
-		&models.BattleParticipant{},
-		&models.BattleAction{},
-		&models.BattleResult{},
-		&models.BattleTemplate{},
-		// New resource models
-		&models.PlayerResources{},
-		&models.ResourceTransaction{},
-		&models.ResourceType{},
-		&models.ResourceConversion{},
-		&models.PlayerResourceGeneration{},
-		&models.ResourceBundle{},
-		// New achievement models
-		&models.AchievementProgress{},
-		&models.AchievementCategory{},
-		&models.AchievementReward{},
-		&models.AchievementLeaderboard{},
-	)
-	if err != nil {
-		log.Fatal("Failed to migrate database:", err)
+	if cfg.MigrateOnStart {
+		if _, err := MigrateUp(DB); err != nil {
+			log.Fatal("Failed to migrate database:", err)
+		}
+	}
+
+	// Refuse to run against a schema this build was not written for
+	if err := CheckMigrations(DB); err != nil {
+		log.Fatal("❌ ", err)
 	}
 
 	log.Println("Database connected and migrated successfully")
 
+	// Set up the cross-entity search index before seeding so it stays in sync
+	if err := SetupSearchIndex(DB); err != nil {
+		log.Fatal("Failed to set up search index:", err)
+	}
+
+	// Track catalog writes so cached catalog responses can be revalidated
+	if err := SetupCatalogVersion(DB); err != nil {
+		log.Fatal("Failed to set up catalog version:", err)
+	}
+
+	// Parse numeric values out of free-text SWAPI fields on every write
+	if err := SetupNumericColumns(DB); err != nil {
+		log.Fatal("Failed to set up numeric columns:", err)
+	}
+
 	// Seed data if tables are empty
 	seedData()
 }
@@ -100,6 +66,9 @@ func seedData() {
 		log.Println("Database already contains data, skipping seed")
 		// Але завжди перевіряємо та додаємо питання для вікторини
 		SeedQuizQuestions()
+		SeedVehicles(DB)
+		SeedEvents(DB)
+		seedEnhancements()
 		return
 	}
 
@@ -1101,6 +1070,15 @@ func seedData() {
 		DB.Create(&planet)
 	}
 
+	// Create vehicles and link them to their pilots and films
+	SeedVehicles(DB)
+
+	// Create timeline events and link them to their participants and films
+	SeedEvents(DB)
+
+	// Add the Bright Data specs of starships and planets
+	seedEnhancements()
+
 	log.Println("Database seeded successfully")
 
 	// Seed quiz questions
@@ -1112,3 +1090,10 @@ func seedData() {
 	// Seed game data
 	SeedGameData(DB)
 }
+
+// seedEnhancements adds the Bright Data enhancements to the catalog once
+func seedEnhancements() {
+	if err := SeedAllBrightDataEnhancements(DB); err != nil {
+		log.Printf("Error seeding Bright Data enhancements: %v", err)
+	}
+}
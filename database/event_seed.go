@@ -0,0 +1,259 @@
+package database
+
+import (
+	"log"
+	"starwars-api/models"
+
+	"gorm.io/gorm"
+)
+
+// eventSeed pairs an event with the SWAPI URLs of its participants and films
+type eventSeed struct {
+	event        models.Event
+	participants []string
+	films        []string
+}
+
+// SeedEvents creates the key events of the galactic timeline
+func SeedEvents(db *gorm.DB) {
+	var count int64
+	db.Model(&models.Event{}).Count(&count)
+
+	if count > 0 {
+		log.Println("Events already exist, skipping seed")
+		return
+	}
+
+	log.Println("Seeding events...")
+
+	seeds := []eventSeed{
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/1/",
+				Name:        "Founding of the Jedi Order",
+				Type:        "founding",
+				Date:        "25,000 BBY",
+				Location:    "Ahch-To",
+				Description: "The first Jedi temple is established and the Jedi Order begins its millennia-long guardianship of the Republic.",
+				Outcome:     "The Jedi Order is founded",
+				Era:         "Old Republic",
+			},
+			participants: nil,
+			films:        nil,
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/2/",
+				Name:        "Battle of Naboo",
+				Type:        "battle",
+				Date:        "32 BBY",
+				Location:    "Naboo",
+				Description: "Gungan and Naboo forces break the Trade Federation blockade while Jedi duel a Sith Lord in Theed.",
+				Outcome:     "Trade Federation defeated; Darth Maul believed slain",
+				Era:         "Fall of the Republic",
+			},
+			participants: []string{"https://swapi.dev/api/people/9/", "https://swapi.dev/api/people/14/", "https://swapi.dev/api/people/12/", "https://swapi.dev/api/people/11/", "https://swapi.dev/api/people/15/", "https://swapi.dev/api/people/24/", "https://swapi.dev/api/people/8/"},
+			films:        []string{"https://swapi.dev/api/films/1/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/3/",
+				Name:        "First Battle of Geonosis",
+				Type:        "battle",
+				Date:        "22 BBY",
+				Location:    "Geonosis",
+				Description: "Clone troopers rescue Jedi from the Geonosian arena, opening the Clone Wars.",
+				Outcome:     "Start of the Clone Wars",
+				Era:         "Fall of the Republic",
+			},
+			participants: []string{"https://swapi.dev/api/people/9/", "https://swapi.dev/api/people/11/", "https://swapi.dev/api/people/12/", "https://swapi.dev/api/people/13/", "https://swapi.dev/api/people/10/", "https://swapi.dev/api/people/21/", "https://swapi.dev/api/people/23/"},
+			films:        []string{"https://swapi.dev/api/films/2/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/4/",
+				Name:        "Battle of Coruscant",
+				Type:        "battle",
+				Date:        "19 BBY",
+				Location:    "Coruscant",
+				Description: "Separatist forces under General Grievous attack Coruscant and abduct Chancellor Palpatine.",
+				Outcome:     "Count Dooku killed; Palpatine rescued",
+				Era:         "Fall of the Republic",
+			},
+			participants: []string{"https://swapi.dev/api/people/9/", "https://swapi.dev/api/people/11/", "https://swapi.dev/api/people/23/", "https://swapi.dev/api/people/25/", "https://swapi.dev/api/people/8/"},
+			films:        []string{"https://swapi.dev/api/films/3/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/5/",
+				Name:        "Great Jedi Purge",
+				Type:        "purge",
+				Date:        "19 BBY",
+				Location:    "Coruscant",
+				Description: "Clone troopers execute Order 66 and turn on their Jedi generals across the galaxy.",
+				Outcome:     "The Jedi Order is nearly destroyed",
+				Era:         "Fall of the Republic",
+			},
+			participants: []string{"https://swapi.dev/api/people/8/", "https://swapi.dev/api/people/11/", "https://swapi.dev/api/people/10/", "https://swapi.dev/api/people/9/", "https://swapi.dev/api/people/13/"},
+			films:        []string{"https://swapi.dev/api/films/3/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/6/",
+				Name:        "Formation of the Galactic Empire",
+				Type:        "founding",
+				Date:        "19 BBY",
+				Location:    "Coruscant",
+				Description: "Supreme Chancellor Palpatine declares himself Emperor before the Galactic Senate.",
+				Outcome:     "The Galactic Republic becomes the Galactic Empire",
+				Era:         "Imperial",
+			},
+			participants: []string{"https://swapi.dev/api/people/8/", "https://swapi.dev/api/people/11/", "https://swapi.dev/api/people/12/"},
+			films:        []string{"https://swapi.dev/api/films/3/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/7/",
+				Name:        "Destruction of Alderaan",
+				Type:        "massacre",
+				Date:        "0 BBY",
+				Location:    "Alderaan",
+				Description: "The Death Star fires its superlaser on Alderaan to demonstrate its power.",
+				Outcome:     "Alderaan is destroyed",
+				Era:         "Imperial",
+			},
+			participants: []string{"https://swapi.dev/api/people/2/", "https://swapi.dev/api/people/7/"},
+			films:        []string{"https://swapi.dev/api/films/4/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/8/",
+				Name:        "Battle of Yavin",
+				Type:        "battle",
+				Date:        "0 BBY",
+				Location:    "Yavin IV",
+				Description: "Rebel starfighters attack the first Death Star above Yavin IV.",
+				Outcome:     "Death Star destroyed; Rebel victory",
+				Era:         "Imperial",
+			},
+			participants: []string{"https://swapi.dev/api/people/1/", "https://swapi.dev/api/people/2/", "https://swapi.dev/api/people/3/", "https://swapi.dev/api/people/4/", "https://swapi.dev/api/people/5/", "https://swapi.dev/api/people/6/", "https://swapi.dev/api/people/7/"},
+			films:        []string{"https://swapi.dev/api/films/4/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/9/",
+				Name:        "Battle of Hoth",
+				Type:        "battle",
+				Date:        "3 ABY",
+				Location:    "Hoth",
+				Description: "Imperial walkers assault Echo Base, forcing the Rebel Alliance to evacuate.",
+				Outcome:     "Imperial victory; Rebels escape",
+				Era:         "Imperial",
+			},
+			participants: []string{"https://swapi.dev/api/people/1/", "https://swapi.dev/api/people/2/", "https://swapi.dev/api/people/3/", "https://swapi.dev/api/people/4/", "https://swapi.dev/api/people/5/", "https://swapi.dev/api/people/6/", "https://swapi.dev/api/people/7/"},
+			films:        []string{"https://swapi.dev/api/films/5/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/10/",
+				Name:        "Duel on Cloud City",
+				Type:        "duel",
+				Date:        "3 ABY",
+				Location:    "Bespin",
+				Description: "Luke Skywalker confronts Darth Vader in the reactor shaft of Cloud City.",
+				Outcome:     "Luke loses his hand and learns the truth about his father",
+				Era:         "Imperial",
+			},
+			participants: []string{"https://swapi.dev/api/people/1/", "https://swapi.dev/api/people/2/", "https://swapi.dev/api/people/3/", "https://swapi.dev/api/people/4/", "https://swapi.dev/api/people/7/", "https://swapi.dev/api/people/20/", "https://swapi.dev/api/people/22/"},
+			films:        []string{"https://swapi.dev/api/films/5/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/11/",
+				Name:        "Battle of Endor",
+				Type:        "battle",
+				Date:        "4 ABY",
+				Location:    "Endor",
+				Description: "The Rebel fleet attacks the second Death Star while a strike team disables its shield generator.",
+				Outcome:     "Emperor and Darth Vader killed; second Death Star destroyed",
+				Era:         "Imperial",
+			},
+			participants: []string{"https://swapi.dev/api/people/1/", "https://swapi.dev/api/people/2/", "https://swapi.dev/api/people/3/", "https://swapi.dev/api/people/4/", "https://swapi.dev/api/people/5/", "https://swapi.dev/api/people/6/", "https://swapi.dev/api/people/7/", "https://swapi.dev/api/people/8/", "https://swapi.dev/api/people/22/", "https://swapi.dev/api/people/28/", "https://swapi.dev/api/people/29/"},
+			films:        []string{"https://swapi.dev/api/films/6/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/12/",
+				Name:        "Galactic Concordance",
+				Type:        "treaty",
+				Date:        "5 ABY",
+				Location:    "Chandrila",
+				Description: "The New Republic and the remnants of the Empire sign a peace treaty after the Battle of Jakku.",
+				Outcome:     "The Galactic Civil War ends",
+				Era:         "New Republic",
+			},
+			participants: []string{"https://swapi.dev/api/people/28/"},
+			films:        nil,
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/13/",
+				Name:        "Battle of Starkiller Base",
+				Type:        "battle",
+				Date:        "34 ABY",
+				Location:    "Starkiller Base",
+				Description: "Resistance pilots destroy the First Order superweapon after it obliterates the Hosnian system.",
+				Outcome:     "Starkiller Base destroyed; Han Solo killed",
+				Era:         "First Order",
+			},
+			participants: []string{"https://swapi.dev/api/people/2/", "https://swapi.dev/api/people/3/", "https://swapi.dev/api/people/4/", "https://swapi.dev/api/people/16/", "https://swapi.dev/api/people/17/", "https://swapi.dev/api/people/18/", "https://swapi.dev/api/people/19/"},
+			films:        []string{"https://swapi.dev/api/films/7/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/14/",
+				Name:        "Battle of Crait",
+				Type:        "battle",
+				Date:        "34 ABY",
+				Location:    "Crait",
+				Description: "The remnants of the Resistance make a last stand in an abandoned Rebel outpost.",
+				Outcome:     "Luke Skywalker becomes one with the Force; the Resistance escapes",
+				Era:         "First Order",
+			},
+			participants: []string{"https://swapi.dev/api/people/1/", "https://swapi.dev/api/people/2/", "https://swapi.dev/api/people/16/", "https://swapi.dev/api/people/17/", "https://swapi.dev/api/people/18/", "https://swapi.dev/api/people/19/"},
+			films:        []string{"https://swapi.dev/api/films/8/"},
+		},
+		{
+			event: models.Event{
+				URL:         "https://swapi.dev/api/events/15/",
+				Name:        "Battle of Exegol",
+				Type:        "battle",
+				Date:        "35 ABY",
+				Location:    "Exegol",
+				Description: "The Resistance and a citizen fleet attack the Sith Eternal armada above Exegol.",
+				Outcome:     "Palpatine destroyed; the First Order falls",
+				Era:         "First Order",
+			},
+			participants: []string{"https://swapi.dev/api/people/8/", "https://swapi.dev/api/people/16/", "https://swapi.dev/api/people/17/", "https://swapi.dev/api/people/18/", "https://swapi.dev/api/people/19/", "https://swapi.dev/api/people/22/"},
+			films:        []string{"https://swapi.dev/api/films/9/"},
+		},
+	}
+
+	for _, seed := range seeds {
+		event := seed.event
+
+		// Link participants and films by their SWAPI URLs
+		if len(seed.participants) > 0 {
+			db.Where("url IN ?", seed.participants).Find(&event.Participants)
+		}
+		if len(seed.films) > 0 {
+			db.Where("url IN ?", seed.films).Find(&event.Films)
+		}
+
+		if err := db.Create(&event).Error; err != nil {
+			log.Printf("Error creating event %s: %v", event.Name, err)
+		}
+	}
+
+	log.Printf("Seeded %d events", len(seeds))
+}
@@ -0,0 +1,6 @@
+// Package initialschema is a frozen copy of the models the initial schema
+// migration creates tables for, as they stood before migrations 4 and later
+// changed them. Keeping them apart from the models means later model changes
+// do not change what migration 1 creates; change the schema with a new
+// migration instead.
+package initialschema
@@ -0,0 +1,273 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is a versioned schema or data change. Migrations are applied in
+// version order, each in its own transaction together with its record in
+// schema_migrations.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error // nil if the migration cannot be reverted
+}
+
+// MigrationStatus is a known migration and when it was applied, nil if pending
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// PendingMigrationsError is returned by CheckMigrations for a database that is
+// not migrated to the schema this build expects
+type PendingMigrationsError struct {
+	Pending []uint
+	Unknown []uint // applied migrations this build does not know, from a newer build
+}
+
+func (e *PendingMigrationsError) Error() string {
+	var problems []string
+	if len(e.Pending) > 0 {
+		problems = append(problems, fmt.Sprintf("%d pending migrations (%s); run `starwars-api migrate up`", len(e.Pending), joinVersions(e.Pending)))
+	}
+	if len(e.Unknown) > 0 {
+		problems = append(problems, fmt.Sprintf("migrations %s were applied by a newer build", joinVersions(e.Unknown)))
+	}
+	return "database schema is out of date: " + strings.Join(problems, "; ")
+}
+
+// schemaMigration records an applied migration
+type schemaMigration struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// sqlMigrationFiles holds the SQL migrations, named <version>_<name>.up.sql
+// and <version>_<name>.down.sql
+//
+//go:embed migrations/*.sql
+var sqlMigrationFiles embed.FS
+
+var sqlMigrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migrations returns the Go and SQL migrations in version order
+func Migrations() ([]Migration, error) {
+	byVersion := make(map[uint]*Migration)
+	for i := range goMigrations {
+		migration := goMigrations[i]
+		if byVersion[migration.Version] != nil {
+			return nil, fmt.Errorf("duplicate migration version %d", migration.Version)
+		}
+		byVersion[migration.Version] = &migration
+	}
+
+	files, err := fs.Glob(sqlMigrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		match := sqlMigrationName.FindStringSubmatch(path.Base(file))
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", file)
+		}
+		version, _ := strconv.ParseUint(match[1], 10, 32)
+		content, err := sqlMigrationFiles.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		migration := byVersion[uint(version)]
+		if migration == nil {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("duplicate migration version %d", version)
+		}
+
+		run := sqlMigration(string(content))
+		if match[3] == "up" {
+			migration.Up = run
+		} else {
+			migration.Down = run
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == nil {
+			return nil, fmt.Errorf("migration %d has no up step", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// sqlMigration runs the statements of a SQL migration file
+func sqlMigration(statements string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Exec(statements).Error
+	}
+}
+
+// MigrationStatuses lists every known migration with when it was applied
+func MigrationStatuses(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		statuses[i] = MigrationStatus{Migration: migration}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// MigrateUp applies every pending migration and returns the applied ones
+func MigrateUp(db *gorm.DB) ([]Migration, error) {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	statuses, err := MigrationStatuses(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			continue
+		}
+
+		migration := status.Migration
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s failed: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// MigrateDown reverts the given number of most recently applied migrations and
+// returns the reverted ones
+func MigrateDown(db *gorm.DB, steps int) ([]Migration, error) {
+	statuses, err := MigrationStatuses(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(statuses) - 1; i >= 0 && len(done) < steps; i-- {
+		if statuses[i].AppliedAt == nil {
+			continue
+		}
+
+		migration := statuses[i].Migration
+		if migration.Down == nil {
+			return done, fmt.Errorf("migration %d %s cannot be reverted", migration.Version, migration.Name)
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("reverting migration %d %s failed: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// CheckMigrations fails with a PendingMigrationsError unless the database has
+// exactly the migrations of this build applied
+func CheckMigrations(db *gorm.DB) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	check := &PendingMigrationsError{}
+	known := make(map[uint]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		if _, ok := applied[migration.Version]; !ok {
+			check.Pending = append(check.Pending, migration.Version)
+		}
+	}
+	for version := range applied {
+		if !known[version] {
+			check.Unknown = append(check.Unknown, version)
+		}
+	}
+	sort.Slice(check.Unknown, func(i, j int) bool { return check.Unknown[i] < check.Unknown[j] })
+
+	if len(check.Pending) > 0 || len(check.Unknown) > 0 {
+		return check
+	}
+	return nil
+}
+
+// appliedMigrations returns the applied migrations by version; a database
+// without schema_migrations has none
+func appliedMigrations(db *gorm.DB) (map[uint]schemaMigration, error) {
+	applied := make(map[uint]schemaMigration)
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return applied, nil
+	}
+
+	var records []schemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+func joinVersions(versions []uint) string {
+	parts := make([]string, len(versions))
+	for i, version := range versions {
+		parts[i] = strconv.FormatUint(uint64(version), 10)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,318 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"starwars-api/config"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// openTestDB opens an empty in-memory SQLite database of its own for a test
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := Open(config.DatabaseConfig{
+		Driver:       DriverSQLite,
+		URL:          "file:" + name + "?mode=memory&cache=shared",
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+		LogLevel:     "silent",
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// schemaOf describes the tables, columns and indexes of a SQLite database.
+// Columns are read back rather than compared as CREATE statements, whose
+// foreign keys GORM writes in no particular order.
+func schemaOf(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var objects []struct {
+		Type    string
+		Name    string
+		TblName string
+		SQL     *string
+	}
+	err := db.Raw("SELECT type, name, tbl_name, sql FROM sqlite_master WHERE name NOT LIKE 'sqlite_%' ORDER BY name").
+		Scan(&objects).Error
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+
+	var schema []string
+	for _, object := range objects {
+		if object.Type != "table" {
+			sql := ""
+			if object.SQL != nil {
+				sql = *object.SQL
+			}
+			schema = append(schema, fmt.Sprintf("%s %s on %s: %s", object.Type, object.Name, object.TblName, sql))
+			continue
+		}
+
+		var columns []struct {
+			Name      string
+			Type      string
+			NotNull   bool
+			DfltValue *string
+			PK        int
+		}
+		if err := db.Raw("SELECT name, type, \"notnull\" AS not_null, dflt_value, pk FROM pragma_table_info(?)", object.Name).
+			Scan(&columns).Error; err != nil {
+			t.Fatalf("failed to read columns of %s: %v", object.Name, err)
+		}
+		for _, column := range columns {
+			dflt := "<none>"
+			if column.DfltValue != nil {
+				dflt = *column.DfltValue
+			}
+			schema = append(schema, fmt.Sprintf("table %s: %s %s not null=%t default=%s pk=%d",
+				object.Name, column.Name, column.Type, column.NotNull, dflt, column.PK))
+		}
+	}
+	return schema
+}
+
+func TestMigrateUpDownRoundTrip(t *testing.T) {
+	db := openTestDB(t)
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := MigrateUp(db)
+	if err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if len(applied) != len(migrations) {
+		t.Fatalf("MigrateUp applied %d migrations, want %d", len(applied), len(migrations))
+	}
+	if err := CheckMigrations(db); err != nil {
+		t.Fatalf("CheckMigrations after MigrateUp: %v", err)
+	}
+	want := schemaOf(t, db)
+
+	reverted, err := MigrateDown(db, len(migrations))
+	if err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	if len(reverted) != len(migrations) {
+		t.Fatalf("MigrateDown reverted %d migrations, want %d", len(reverted), len(migrations))
+	}
+	var tables []string
+	err = db.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'").Scan(&tables).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tables, []string{"schema_migrations"}) {
+		t.Errorf("tables after reverting every migration = %v, want only schema_migrations", tables)
+	}
+
+	if _, err := MigrateUp(db); err != nil {
+		t.Fatalf("MigrateUp after MigrateDown: %v", err)
+	}
+	if got := schemaOf(t, db); !reflect.DeepEqual(got, want) {
+		t.Errorf("schema after migrating down and up again differs:\n%s", diffLines(want, got))
+	}
+}
+
+func TestMigrateDownEachMigration(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reverting the last n migrations and applying them again gives the same
+	// schema, indexes included
+	for steps := 1; steps <= len(migrations); steps++ {
+		migration := migrations[len(migrations)-steps]
+		t.Run(fmt.Sprintf("%04d_%s", migration.Version, migration.Name), func(t *testing.T) {
+			db := openTestDB(t)
+			if _, err := MigrateUp(db); err != nil {
+				t.Fatalf("MigrateUp: %v", err)
+			}
+			want := schemaOf(t, db)
+
+			reverted, err := MigrateDown(db, steps)
+			if err != nil {
+				t.Fatalf("MigrateDown(%d): %v", steps, err)
+			}
+			if last := reverted[len(reverted)-1]; last.Version != migration.Version {
+				t.Fatalf("MigrateDown(%d) stopped at migration %d, want %d", steps, last.Version, migration.Version)
+			}
+			var pending *PendingMigrationsError
+			if err := CheckMigrations(db); err == nil || !errors.As(err, &pending) || len(pending.Pending) != steps {
+				t.Errorf("CheckMigrations after MigrateDown(%d) = %v, want %d pending migrations", steps, err, steps)
+			}
+
+			if _, err := MigrateUp(db); err != nil {
+				t.Fatalf("MigrateUp: %v", err)
+			}
+			if got := schemaOf(t, db); !reflect.DeepEqual(got, want) {
+				t.Errorf("schema after reverting and reapplying differs:\n%s", diffLines(want, got))
+			}
+		})
+	}
+}
+
+// diffLines lists the lines missing from got and the lines only in got
+func diffLines(want, got []string) string {
+	inGot := make(map[string]bool, len(got))
+	for _, line := range got {
+		inGot[line] = true
+	}
+	inWant := make(map[string]bool, len(want))
+	var diff []string
+	for _, line := range want {
+		inWant[line] = true
+		if !inGot[line] {
+			diff = append(diff, "- "+line)
+		}
+	}
+	for _, line := range got {
+		if !inWant[line] {
+			diff = append(diff, "+ "+line)
+		}
+	}
+	return strings.Join(diff, "\n")
+}
+
+func TestProgressionLedgerMerge(t *testing.T) {
+	type resources struct{ credits, crystals, experience int }
+	tests := []struct {
+		name      string
+		level     int // level, experience and credits of the player before the merge
+		exp       int
+		credits   int
+		resources []resources
+		want      [4]int // level, experience, credits and crystals after it
+		opening   int    // opening ledger entries
+	}{
+		{
+			name:    "no resources",
+			level:   1,
+			credits: 100,
+			want:    [4]int{1, 0, 100, 0},
+			opening: 1,
+		},
+		{
+			name:      "untouched resources",
+			level:     1,
+			credits:   100,
+			resources: []resources{{credits: 1000}},
+			want:      [4]int{1, 0, 100, 0},
+			opening:   1,
+		},
+		{
+			name:      "resources beyond the grant",
+			level:     1,
+			exp:       50,
+			credits:   250,
+			resources: []resources{{credits: 1300, crystals: 5, experience: 30}},
+			want:      [4]int{1, 80, 550, 5},
+			opening:   3,
+		},
+		{
+			name:      "resources spent out of the grant",
+			level:     1,
+			credits:   100,
+			resources: []resources{{credits: 200}},
+			want:      [4]int{1, 0, 100, 0},
+			opening:   1,
+		},
+		{
+			name:      "merged experience earns levels",
+			level:     1,
+			exp:       300,
+			credits:   100,
+			resources: []resources{{credits: 1000, experience: 650}},
+			want:      [4]int{4, 950, 100, 0},
+			opening:   2,
+		},
+		{
+			name:    "levels are never lowered",
+			level:   5,
+			exp:     10,
+			credits: 100,
+			want:    [4]int{5, 10, 100, 0},
+			opening: 2,
+		},
+		{
+			name:    "levels stop at the top of the curve",
+			level:   1,
+			exp:     10000000,
+			credits: 0,
+			want:    [4]int{50, 10000000, 0, 0},
+			opening: 1,
+		},
+	}
+
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := 0
+	for _, migration := range migrations {
+		if migration.Version >= 7 {
+			steps++
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			if _, err := MigrateUp(db); err != nil {
+				t.Fatalf("MigrateUp: %v", err)
+			}
+			if _, err := MigrateDown(db, steps); err != nil {
+				t.Fatalf("MigrateDown(%d): %v", steps, err)
+			}
+
+			err := db.Exec("INSERT INTO players (id, username, level, experience, credits) VALUES (1, 'luke', ?, ?, ?)",
+				tt.level, tt.exp, tt.credits).Error
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range tt.resources {
+				err := db.Exec("INSERT INTO player_resources (player_id, credits, crystals, experience) VALUES (1, ?, ?, ?)",
+					r.credits, r.crystals, r.experience).Error
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := MigrateUp(db); err != nil {
+				t.Fatalf("MigrateUp: %v", err)
+			}
+
+			var got [4]int
+			err = db.Raw("SELECT level, experience, credits, crystals FROM players WHERE id = 1").
+				Row().Scan(&got[0], &got[1], &got[2], &got[3])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("level, experience, credits, crystals = %v, want %v", got, tt.want)
+			}
+
+			var opening int64
+			if err := db.Table("ledger_entries").Where("source = ?", "opening_balance").Count(&opening).Error; err != nil {
+				t.Fatal(err)
+			}
+			if int(opening) != tt.opening {
+				t.Errorf("opening ledger entries = %d, want %d", opening, tt.opening)
+			}
+		})
+	}
+}
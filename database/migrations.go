@@ -0,0 +1,584 @@
+package database
+
+import (
+	"fmt"
+	"starwars-api/database/internal/initialschema"
+	"starwars-api/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// goMigrations are the migrations written in Go. SQL migrations live in
+// migrations/ and share the same version sequence; never edit an applied
+// migration, add a new one instead.
+var goMigrations = []Migration{
+	{Version: 1, Name: "initial_schema", Up: createInitialSchema, Down: dropInitialSchema},
+	{Version: 2, Name: "parse_numeric_columns", Up: NormalizeNumericColumns, Down: func(tx *gorm.DB) error {
+		// The numeric columns are derived from their text columns
+		return nil
+	}},
+	{Version: 4, Name: "player_authentication", Up: addPlayerAuthentication, Down: dropPlayerAuthentication},
+	{Version: 5, Name: "roles_api_keys_audit", Up: addRolesAPIKeysAudit, Down: dropRolesAPIKeysAudit},
+	{Version: 6, Name: "email_tokens", Up: addEmailTokens, Down: dropEmailTokens},
+	{Version: 7, Name: "progression_ledger", Up: addProgressionLedger, Down: dropProgressionLedger},
+	{Version: 8, Name: "level_up_history", Up: addLevelUpHistory, Down: dropLevelUpHistory},
+	{Version: 9, Name: "catalog_search", Up: createSearchTable, Down: dropSearchTable},
+	{Version: 10, Name: "split_starship_agility", Up: splitStarshipAgility, Down: mergeStarshipAgility},
+}
+
+// initialSchemaModels are the models created by the initial schema migration,
+// frozen in initialschema. On databases created before migrations existed,
+// the migration adopts the tables AutoMigrate made at startup.
+var initialSchemaModels = []interface{}{
+	&initialschema.Character{},
+	&initialschema.Film{},
+	&initialschema.Species{},
+	&initialschema.Starship{},
+	&initialschema.WeaponSystem{},
+	&initialschema.Vehicle{},
+	&initialschema.Planet{},
+	&initialschema.Organization{},
+	&initialschema.Weapon{},
+	&initialschema.Event{},
+	&initialschema.Translation{},
+	// Mission models
+	&initialschema.Mission{},
+	&initialschema.MissionObjective{},
+	&initialschema.MissionProgress{},
+	&initialschema.MissionTemplate{},
+	&initialschema.MissionEvent{},
+	&initialschema.MissionReward{},
+	&initialschema.BrightDataMissionSync{},
+	// Game models (existing)
+	&initialschema.Player{},
+	&initialschema.PlayerStats{},
+	&initialschema.QuizQuestion{},
+	&initialschema.GameSession{},
+	&initialschema.GameCard{},
+	&initialschema.PlayerCard{},
+	&initialschema.CardPack{},
+	&initialschema.Battle{},
+	&initialschema.Artifact{},
+	&initialschema.PlayerArtifact{},
+	&initialschema.Achievement{},
+	&initialschema.PlayerAchievement{},
+	&initialschema.QuizSession{},
+	&initialschema.QuizAnswer{},
+	// New fleet models
+	&initialschema.Ship{},
+	&initialschema.Fleet{},
+	&initialschema.ShipUpgrade{},
+	&initialschema.Hangar{},
+	&initialschema.ShipTemplate{},
+	// New battle models
+	&initialschema.BattleParticipant{},
+	&initialschema.BattleAction{},
+	&initialschema.BattleResult{},
+	&initialschema.BattleTemplate{},
+	// New resource models
+	&initialschema.PlayerResources{},
+	&initialschema.ResourceTransaction{},
+	&initialschema.ResourceType{},
+	&initialschema.ResourceConversion{},
+	&initialschema.PlayerResourceGeneration{},
+	&initialschema.ResourceBundle{},
+	// New achievement models
+	&initialschema.AchievementProgress{},
+	&initialschema.AchievementCategory{},
+	&initialschema.AchievementReward{},
+	&initialschema.AchievementLeaderboard{},
+	&initialschema.CatalogVersion{},
+}
+
+// createInitialSchema creates the tables of every model and the catalog version row
+func createInitialSchema(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(initialSchemaModels...); err != nil {
+		return fmt.Errorf("failed to create tables: %w", err)
+	}
+	if err := tx.FirstOrCreate(&initialschema.CatalogVersion{ID: 1, ModifiedAt: time.Now()}).Error; err != nil {
+		return fmt.Errorf("failed to create catalog version: %w", err)
+	}
+	return nil
+}
+
+// dropInitialSchema drops the tables of every model, join tables first
+func dropInitialSchema(tx *gorm.DB) error {
+	var tables []interface{}
+	for _, model := range initialSchemaModels {
+		stmt := &gorm.Statement{DB: tx}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		for _, relationship := range stmt.Schema.Relationships.Many2Many {
+			tables = append(tables, relationship.JoinTable.Table)
+		}
+	}
+	for i := len(initialSchemaModels) - 1; i >= 0; i-- {
+		tables = append(tables, initialSchemaModels[i])
+	}
+
+	if err := tx.Migrator().DropTable(tables...); err != nil {
+		return fmt.Errorf("failed to drop tables: %w", err)
+	}
+	return nil
+}
+
+// dropColumn drops the column of a field in place. The SQLite migrator of
+// GORM rebuilds the table instead and loses its indexes; SQLite 3.35 and later
+// drop columns natively, like PostgreSQL.
+func dropColumn(tx *gorm.DB, model interface{}, field string) error {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	column := stmt.Schema.LookUpField(field)
+	if column == nil {
+		return fmt.Errorf("unknown field %s of %s", field, stmt.Schema.Table)
+	}
+	return tx.Exec("ALTER TABLE ? DROP COLUMN ?", clause.Table{Name: stmt.Schema.Table}, clause.Column{Name: column.DBName}).Error
+}
+
+// playerCredentials and playerSession are the schema added by migration 4,
+// kept apart from the models so that later model changes do not alter it
+type playerCredentials struct {
+	PasswordHash string
+}
+
+func (playerCredentials) TableName() string {
+	return "players"
+}
+
+type playerSession struct {
+	ID               string `gorm:"primaryKey"`
+	PlayerID         uint   `gorm:"not null;index"`
+	RefreshTokenHash string `gorm:"not null"`
+	ExpiresAt        time.Time
+	RevokedAt        *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func (playerSession) TableName() string {
+	return "player_sessions"
+}
+
+// addPlayerAuthentication adds player password hashes and login sessions
+func addPlayerAuthentication(tx *gorm.DB) error {
+	if err := tx.Migrator().AddColumn(&playerCredentials{}, "PasswordHash"); err != nil {
+		return fmt.Errorf("failed to add password hashes: %w", err)
+	}
+	if err := tx.Migrator().CreateTable(&playerSession{}); err != nil {
+		return fmt.Errorf("failed to create player sessions: %w", err)
+	}
+	return nil
+}
+
+func dropPlayerAuthentication(tx *gorm.DB) error {
+	if err := tx.Migrator().DropTable(&playerSession{}); err != nil {
+		return fmt.Errorf("failed to drop player sessions: %w", err)
+	}
+	if err := dropColumn(tx, &playerCredentials{}, "PasswordHash"); err != nil {
+		return fmt.Errorf("failed to drop password hashes: %w", err)
+	}
+	return nil
+}
+
+// playerRole, apiKey and auditEntry are the schema added by migration 5
+type playerRole struct {
+	Role string `gorm:"not null;default:player"`
+}
+
+func (playerRole) TableName() string {
+	return "players"
+}
+
+type apiKey struct {
+	ID         uint   `gorm:"primaryKey"`
+	Name       string `gorm:"not null"`
+	Prefix     string `gorm:"not null"`
+	KeyHash    string `gorm:"uniqueIndex;not null"`
+	Role       string `gorm:"not null"`
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (apiKey) TableName() string {
+	return "api_keys"
+}
+
+type auditEntry struct {
+	ID            uint `gorm:"primaryKey"`
+	ActorType     string
+	ActorID       *uint `gorm:"index"`
+	ActorName     string
+	Role          string
+	RequiredRoles string
+	Method        string
+	Path          string
+	Status        int
+	Outcome       string `gorm:"index"`
+	Reason        string
+	ClientIP      string
+	CreatedAt     time.Time `gorm:"index"`
+}
+
+func (auditEntry) TableName() string {
+	return "audit_entries"
+}
+
+// addRolesAPIKeysAudit adds player roles, service account API keys and the
+// audit log of privileged routes. Existing players become plain players.
+func addRolesAPIKeysAudit(tx *gorm.DB) error {
+	if err := tx.Migrator().AddColumn(&playerRole{}, "Role"); err != nil {
+		return fmt.Errorf("failed to add player roles: %w", err)
+	}
+	if err := tx.Migrator().CreateTable(&apiKey{}, &auditEntry{}); err != nil {
+		return fmt.Errorf("failed to create API keys and audit log: %w", err)
+	}
+	return nil
+}
+
+func dropRolesAPIKeysAudit(tx *gorm.DB) error {
+	if err := tx.Migrator().DropTable(&auditEntry{}, &apiKey{}); err != nil {
+		return fmt.Errorf("failed to drop API keys and audit log: %w", err)
+	}
+	if err := dropColumn(tx, &playerRole{}, "Role"); err != nil {
+		return fmt.Errorf("failed to drop player roles: %w", err)
+	}
+	return nil
+}
+
+// playerEmailVerification and emailToken are the schema added by migration 6
+type playerEmailVerification struct {
+	EmailVerifiedAt *time.Time
+}
+
+func (playerEmailVerification) TableName() string {
+	return "players"
+}
+
+type emailToken struct {
+	ID        uint   `gorm:"primaryKey"`
+	PlayerID  uint   `gorm:"not null;index"`
+	Purpose   string `gorm:"not null"`
+	Email     string `gorm:"not null"`
+	TokenHash string `gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (emailToken) TableName() string {
+	return "email_tokens"
+}
+
+// addEmailTokens adds email verification and the tokens of verification and
+// password reset emails. Existing addresses start out unverified.
+func addEmailTokens(tx *gorm.DB) error {
+	if err := tx.Migrator().AddColumn(&playerEmailVerification{}, "EmailVerifiedAt"); err != nil {
+		return fmt.Errorf("failed to add email verification: %w", err)
+	}
+	if err := tx.Migrator().CreateTable(&emailToken{}); err != nil {
+		return fmt.Errorf("failed to create email tokens: %w", err)
+	}
+	return nil
+}
+
+func dropEmailTokens(tx *gorm.DB) error {
+	if err := tx.Migrator().DropTable(&emailToken{}); err != nil {
+		return fmt.Errorf("failed to drop email tokens: %w", err)
+	}
+	if err := dropColumn(tx, &playerEmailVerification{}, "EmailVerifiedAt"); err != nil {
+		return fmt.Errorf("failed to drop email verification: %w", err)
+	}
+	return nil
+}
+
+// playerCrystals, resourceCurrencies and ledgerEntry are the schema changed by
+// migration 7
+type playerCrystals struct {
+	Crystals int `gorm:"default:0"`
+}
+
+func (playerCrystals) TableName() string {
+	return "players"
+}
+
+type resourceCurrencies struct {
+	Credits    int `gorm:"default:1000"`
+	Crystals   int `gorm:"default:0"`
+	Experience int `gorm:"default:0"`
+}
+
+func (resourceCurrencies) TableName() string {
+	return "player_resources"
+}
+
+type ledgerEntry struct {
+	ID                uint   `gorm:"primaryKey"`
+	PlayerID          uint   `gorm:"not null;index"`
+	Currency          string `gorm:"not null"`
+	Amount            int
+	Balance           int
+	Source            string `gorm:"not null"`
+	Description       string
+	RelatedEntityType string
+	RelatedEntityID   string
+	CreatedAt         time.Time `gorm:"index"`
+}
+
+func (ledgerEntry) TableName() string {
+	return "ledger_entries"
+}
+
+// ledgerCurrencies are the balance columns of players kept in the ledger
+var ledgerCurrencies = []string{"experience", "credits", "crystals"}
+
+// resourceGrants are the starting balances player resources were created
+// with, by field. Players already got starting credits of their own, so these
+// are not merged.
+var resourceGrants = map[string]int{"Credits": 1000}
+
+// Levels set by migration 7 follow the default leveling curve of the time:
+// level n+1 needs n² × 100 experience, up to level 50
+const (
+	ledgerLevelExperience = 100
+	ledgerMaxLevel        = 50
+)
+
+// addProgressionLedger makes the players table the single home of experience,
+// credits and crystals, with a ledger of their changes. What player resources
+// hold beyond their starting grant is added to the player's own balances;
+// resources spent out of the grant are not taken from them. Levels are raised
+// to what the merged experience earns, without the rewards of the levels, and
+// every balance starts the ledger with an opening entry.
+func addProgressionLedger(tx *gorm.DB) error {
+	if err := tx.Migrator().AddColumn(&playerCrystals{}, "Crystals"); err != nil {
+		return fmt.Errorf("failed to add player crystals: %w", err)
+	}
+	if err := tx.Migrator().CreateTable(&ledgerEntry{}); err != nil {
+		return fmt.Errorf("failed to create the ledger: %w", err)
+	}
+
+	for _, column := range []string{"Credits", "Crystals", "Experience"} {
+		name := tx.NamingStrategy.ColumnName("", column)
+		grant := resourceGrants[column]
+		err := tx.Exec(fmt.Sprintf(`UPDATE players SET %[1]s = %[1]s + COALESCE((
+			SELECT SUM(CASE WHEN player_resources.%[1]s > ? THEN player_resources.%[1]s - ? ELSE 0 END) FROM player_resources
+			WHERE player_resources.player_id = players.id AND player_resources.deleted_at IS NULL), 0)`, name), grant, grant).Error
+		if err != nil {
+			return fmt.Errorf("failed to merge resource %s: %w", name, err)
+		}
+		if err := dropColumn(tx, &resourceCurrencies{}, column); err != nil {
+			return fmt.Errorf("failed to drop resource %s: %w", name, err)
+		}
+	}
+
+	var players []struct {
+		ID         uint
+		Level      int
+		Experience int
+	}
+	if err := tx.Table("players").Select("id", "level", "experience").Find(&players).Error; err != nil {
+		return fmt.Errorf("failed to load player levels: %w", err)
+	}
+	for _, player := range players {
+		level := 1
+		for level < ledgerMaxLevel && level*level*ledgerLevelExperience <= player.Experience {
+			level++
+		}
+		if level <= player.Level {
+			continue
+		}
+		if err := tx.Table("players").Where("id = ?", player.ID).Update("level", level).Error; err != nil {
+			return fmt.Errorf("failed to set the level of player %d: %w", player.ID, err)
+		}
+	}
+
+	for _, currency := range ledgerCurrencies {
+		err := tx.Exec(fmt.Sprintf(`INSERT INTO ledger_entries (player_id, currency, amount, balance, source, description, created_at)
+			SELECT id, ?, %[1]s, %[1]s, ?, ?, ? FROM players WHERE %[1]s <> 0`, currency),
+			currency, models.SourceOpeningBalance, "Balance before the ledger", time.Now()).Error
+		if err != nil {
+			return fmt.Errorf("failed to open the %s ledger: %w", currency, err)
+		}
+	}
+	return nil
+}
+
+// dropProgressionLedger moves crystals back to player resources and drops the
+// ledger. Credits and experience stay with the player, so that migrating up
+// again does not count them twice.
+func dropProgressionLedger(tx *gorm.DB) error {
+	for _, column := range []string{"Credits", "Crystals", "Experience"} {
+		if err := tx.Migrator().AddColumn(&resourceCurrencies{}, column); err != nil {
+			return fmt.Errorf("failed to add resource currencies: %w", err)
+		}
+	}
+	err := tx.Exec(`UPDATE player_resources SET credits = 0, crystals = COALESCE((
+		SELECT players.crystals FROM players WHERE players.id = player_resources.player_id), 0)`).Error
+	if err != nil {
+		return fmt.Errorf("failed to move crystals back: %w", err)
+	}
+
+	if err := tx.Migrator().DropTable(&ledgerEntry{}); err != nil {
+		return fmt.Errorf("failed to drop the ledger: %w", err)
+	}
+	if err := dropColumn(tx, &playerCrystals{}, "Crystals"); err != nil {
+		return fmt.Errorf("failed to drop player crystals: %w", err)
+	}
+	return nil
+}
+
+// levelUp is the level-up history created by migration 8
+type levelUp struct {
+	ID             uint `gorm:"primaryKey"`
+	PlayerID       uint `gorm:"not null;uniqueIndex:idx_level_ups_player_level"`
+	Level          int  `gorm:"not null;uniqueIndex:idx_level_ups_player_level"`
+	Experience     int
+	Source         string
+	Credits        int
+	Durasteel      int
+	Transparisteel int
+	Tibanna        int
+	Kyber          int
+	HangarSlots    int
+	UnlockedShips  string    // JSON array
+	CreatedAt      time.Time `gorm:"index"`
+}
+
+func (levelUp) TableName() string {
+	return "level_ups"
+}
+
+// addLevelUpHistory creates the history of levels reached. Levels reached
+// before it are not in it.
+func addLevelUpHistory(tx *gorm.DB) error {
+	if err := tx.Migrator().CreateTable(&levelUp{}); err != nil {
+		return fmt.Errorf("failed to create level-up history: %w", err)
+	}
+	return nil
+}
+
+func dropLevelUpHistory(tx *gorm.DB) error {
+	if err := tx.Migrator().DropTable(&levelUp{}); err != nil {
+		return fmt.Errorf("failed to drop level-up history: %w", err)
+	}
+	return nil
+}
+
+// createSearchTable creates the catalog search index. SQLite builds with FTS5
+// (the sqlite_fts5 build tag) get a ranked FTS5 table; other SQLite builds and
+// PostgreSQL get a plain table searched with LIKE. Databases that made the
+// table at startup before this migration keep theirs.
+func createSearchTable(tx *gorm.DB) error {
+	if tx.Migrator().HasTable(SearchTable) {
+		return nil
+	}
+
+	statement := "CREATE TABLE " + SearchTable + " (entity_type TEXT, entity_id INTEGER, url TEXT, title TEXT, body TEXT)"
+	if IsSQLite(tx) {
+		var fts5 bool
+		if err := tx.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5).Error; err != nil {
+			return fmt.Errorf("failed to check for FTS5: %w", err)
+		}
+		if fts5 {
+			statement = "CREATE VIRTUAL TABLE " + SearchTable +
+				" USING fts5(entity_type UNINDEXED, entity_id UNINDEXED, url UNINDEXED, title, body, tokenize = 'porter unicode61')"
+		}
+	}
+	if err := tx.Exec(statement).Error; err != nil {
+		return fmt.Errorf("failed to create search table: %w", err)
+	}
+	return nil
+}
+
+func dropSearchTable(tx *gorm.DB) error {
+	if err := tx.Migrator().DropTable(SearchTable); err != nil {
+		return fmt.Errorf("failed to drop search table: %w", err)
+	}
+	return nil
+}
+
+// gameplayStatsColumns are the starship columns of the gameplay stats, which
+// migration 10 prefixes with gameplay_. Their agility shared the agility
+// column of the physics configuration until then.
+var gameplayStatsColumns = []string{
+	"attack_power", "defense", "speed", "accuracy", "rarity", "unlock_level",
+	"upgrade_cost", "max_level", "special_abilities", "faction",
+}
+
+// starshipGameplayAgility is the column added by migration 10
+type starshipGameplayAgility struct {
+	GameplayAgility int
+}
+
+func (starshipGameplayAgility) TableName() string {
+	return "starships"
+}
+
+// seededAgility is the agility of the enhanced starships seeded before
+// migration 10, in their physics configuration and gameplay stats
+var seededAgility = []struct {
+	Name     string
+	URL      string
+	Physics  float64
+	Gameplay int
+}{
+	{"Millennium Falcon", "https://swapi.dev/api/starships/10/", 7.5, 75},
+	{"X-wing", "https://swapi.dev/api/starships/12/", 9.0, 90},
+	{"TIE Fighter", "https://swapi.dev/api/starships/13/", 9.5, 95},
+	{"Imperial Star Destroyer", "https://swapi.dev/api/starships/3/", 1.0, 10},
+}
+
+// splitStarshipAgility gives the gameplay stats of starships columns of their
+// own. The shared agility column stays with the physics configuration; the
+// seeded starships get both agilities back from the seed, while on other
+// starships the gameplay agility starts unknown, as the shared column cannot
+// tell which of the two it holds.
+func splitStarshipAgility(tx *gorm.DB) error {
+	for _, column := range gameplayStatsColumns {
+		if err := renameColumn(tx, "starships", column, "gameplay_"+column); err != nil {
+			return fmt.Errorf("failed to prefix gameplay %s: %w", column, err)
+		}
+	}
+	if err := tx.Migrator().AddColumn(&starshipGameplayAgility{}, "GameplayAgility"); err != nil {
+		return fmt.Errorf("failed to add gameplay agility: %w", err)
+	}
+
+	// Only starships still holding one of their seeded agilities are restored,
+	// so that enhancements edited since are left alone
+	for _, ship := range seededAgility {
+		err := tx.Exec(`UPDATE starships SET agility = ?, gameplay_agility = ?
+			WHERE (LOWER(name) = LOWER(?) OR url = ?) AND agility IN (?, ?)`,
+			ship.Physics, ship.Gameplay, ship.Name, ship.URL, ship.Physics, ship.Gameplay).Error
+		if err != nil {
+			return fmt.Errorf("failed to restore the agility of %s: %w", ship.Name, err)
+		}
+	}
+	return nil
+}
+
+// mergeStarshipAgility removes the gameplay prefix again. The gameplay
+// agility is dropped; the agility column keeps the physics one.
+func mergeStarshipAgility(tx *gorm.DB) error {
+	if err := dropColumn(tx, &starshipGameplayAgility{}, "GameplayAgility"); err != nil {
+		return fmt.Errorf("failed to drop gameplay agility: %w", err)
+	}
+	for _, column := range gameplayStatsColumns {
+		if err := renameColumn(tx, "starships", "gameplay_"+column, column); err != nil {
+			return fmt.Errorf("failed to unprefix gameplay %s: %w", column, err)
+		}
+	}
+	return nil
+}
+
+// renameColumn renames a column in place, keeping the indexes of the table
+// for the same reason as dropColumn
+func renameColumn(tx *gorm.DB, table, from, to string) error {
+	return tx.Exec("ALTER TABLE ? RENAME COLUMN ? TO ?", clause.Table{Name: table}, clause.Column{Name: from}, clause.Column{Name: to}).Error
+}
@@ -0,0 +1,2 @@
+-- Planet residents and expanded homeworlds look characters up by homeworld URL
+CREATE INDEX IF NOT EXISTS idx_characters_homeworld ON characters (homeworld);
@@ -2,7 +2,6 @@ package database
 
 import (
 	"log"
-	"time"
 
 	"gorm.io/gorm"
 	"starwars-api/models"
@@ -89,11 +88,11 @@ func SeedMissions(db *gorm.DB) error {
 				TerrainScale:    1.0,
 				TerrainHeight:   0.0,
 				WeatherType:     "none",
-				ParticleEffects: []string{"laser_fire", "explosions", "engine_trails"],
+				ParticleEffects: []string{"laser_fire", "explosions", "engine_trails"},
 				MusicTrack:      "battle_of_yavin",
 				FogDensity:      0.0,
 				FogColor:        "#000000",
-				SoundEffects:    []string{"x_wing_engine", "tie_fighter_scream", "explosion"],
+				SoundEffects:    []string{"x_wing_engine", "tie_fighter_scream", "explosion"},
 			},
 			SourceURL:       "https://starwars.fandom.com/wiki/Battle_of_Yavin",
 			WookieepediaURL: "https://starwars.fandom.com/wiki/Battle_of_Yavin",
@@ -178,7 +177,7 @@ func SeedMissions(db *gorm.DB) error {
 				MusicTrack:      "duel_of_fates",
 				FogDensity:      0.05,
 				FogColor:        "#FFAA77",
-				SoundEffects:    []string{"podracer_engine", "crowd_roar", "collision"],
+				SoundEffects:    []string{"podracer_engine", "crowd_roar", "collision"},
 			},
 			SourceURL:       "https://starwars.fandom.com/wiki/Boonta_Eve_Classic",
 			WookieepediaURL: "https://starwars.fandom.com/wiki/Boonta_Eve_Classic",
@@ -216,11 +215,11 @@ func SeedMissions(db *gorm.DB) error {
 				TerrainScale:    50.0,
 				TerrainHeight:   20.0,
 				WeatherType:     "clear",
-				ParticleEffects: []string{"force_energy", "light_beams"],
+				ParticleEffects: []string{"force_energy", "light_beams"},
 				MusicTrack:      "jedi_temple",
 				FogDensity:      0.0,
 				FogColor:        "#FFFFFF",
-				SoundEffects:    []string{"force_hum", "lightsaber_ignite", "temple_echo"],
+				SoundEffects:    []string{"force_hum", "lightsaber_ignite", "temple_echo"},
 			},
 			SourceURL:       "https://starwars.fandom.com/wiki/Holocron",
 			WookieepediaURL: "https://starwars.fandom.com/wiki/Holocron",
@@ -258,11 +257,11 @@ func SeedMissions(db *gorm.DB) error {
 				TerrainScale:    75.0,
 				TerrainHeight:   30.0,
 				WeatherType:     "humid",
-				ParticleEffects: []string{"mist", "security_beams"],
+				ParticleEffects: []string{"mist", "security_beams"},
 				MusicTrack:      "rogue_one_theme",
 				FogDensity:      0.3,
 				FogColor:        "#669966",
-				SoundEffects:    []string{"jungle_ambient", "imperial_comms", "alarm"],
+				SoundEffects:    []string{"jungle_ambient", "imperial_comms", "alarm"},
 			},
 			SourceURL:       "https://starwars.fandom.com/wiki/Battle_of_Scarif",
 			WookieepediaURL: "https://starwars.fandom.com/wiki/Battle_of_Scarif",
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// numericColumn pairs a free-text SWAPI column with the nullable numeric
+// column parsed from it
+type numericColumn struct {
+	Text  string
+	Value string
+}
+
+// numericColumns lists the parsed columns of each catalog table
+var numericColumns = map[string][]numericColumn{
+	"characters": {{"height", "height_value"}, {"mass", "mass_value"}},
+	"starships": {
+		{"cost_in_credits", "cost_in_credits_value"}, {"length", "length_value"}, {"cargo_capacity", "cargo_capacity_value"},
+	},
+	"planets": {{"diameter", "diameter_value"}, {"population", "population_value"}},
+}
+
+// ParseSWAPINumber parses a free-text SWAPI quantity such as "172", "1,358" or
+// "0.8". Values like "unknown", "n/a" or "none" have no number and give nil.
+func ParseSWAPINumber(text string) *float64 {
+	text = strings.ReplaceAll(strings.TrimSpace(text), ",", "")
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	return &value
+}
+
+// SetupNumericColumns registers the callbacks that keep the numeric columns in
+// sync with their text columns on every catalog write
+func SetupNumericColumns(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Register("numeric:normalize_create", normalizeCallback); err != nil {
+		return err
+	}
+	return callbacks.Update().After("gorm:update").Register("numeric:normalize_update", normalizeCallback)
+}
+
+// NormalizeNumericColumns parses the numeric columns of every row, filling in
+// rows written before the columns existed
+func NormalizeNumericColumns(db *gorm.DB) error {
+	for table := range numericColumns {
+		if err := normalizeRows(db, table, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeRows updates the numeric columns of the given rows of a table that
+// no longer match their text
+func normalizeRows(db *gorm.DB, table string, ids []uint) error {
+	columns := []string{"id"}
+	for _, column := range numericColumns[table] {
+		columns = append(columns, column.Text, column.Value)
+	}
+
+	var rows []map[string]interface{}
+	if err := whereIDs(db.Table(table).Select(columns), ids).Find(&rows).Error; err != nil {
+		return fmt.Errorf("failed to load %s for normalization: %w", table, err)
+	}
+
+	for _, row := range rows {
+		updates := make(map[string]interface{})
+		for _, column := range numericColumns[table] {
+			text, _ := row[column.Text].(string)
+			parsed := ParseSWAPINumber(text)
+			current, _ := row[column.Value].(float64)
+
+			switch {
+			case parsed == nil && row[column.Value] != nil:
+				updates[column.Value] = nil
+			case parsed != nil && (row[column.Value] == nil || current != *parsed):
+				updates[column.Value] = *parsed
+			}
+		}
+
+		if len(updates) > 0 {
+			if err := db.Table(table).Where("id = ?", row["id"]).UpdateColumns(updates).Error; err != nil {
+				return fmt.Errorf("failed to normalize %s: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeCallback parses the numeric columns of created or updated catalog rows
+func normalizeCallback(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.Schema == nil {
+		return
+	}
+	table := tx.Statement.Schema.Table
+	if _, ok := numericColumns[table]; !ok {
+		return
+	}
+
+	// Batch updates without primary keys normalize the whole table
+	ids := primaryKeys(tx)
+	if len(ids) == 0 {
+		ids = nil
+	}
+
+	session := tx.Session(&gorm.Session{NewDB: true})
+	if err := normalizeRows(session, table, ids); err != nil {
+		log.Printf("Error normalizing numeric columns of %s: %v", table, err)
+	}
+}
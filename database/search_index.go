@@ -0,0 +1,300 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"starwars-api/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SearchTable is the table holding one search document per catalog entity
+const SearchTable = "catalog_search"
+
+// ftsEnabled reports whether SearchTable is an FTS5 virtual table. Without the
+// sqlite_fts5 build tag, and on PostgreSQL, it is a plain table searched with
+// LIKE instead.
+var ftsEnabled bool
+
+// FullTextSearchEnabled reports whether ranked FTS5 search is available
+func FullTextSearchEnabled() bool {
+	return ftsEnabled
+}
+
+// SearchDocument is the searchable text of a single catalog entity
+type SearchDocument struct {
+	EntityType string
+	EntityID   uint
+	URL        string
+	Title      string
+	Body       string
+}
+
+// searchSource loads search documents for one catalog table; nil ids loads every row
+type searchSource struct {
+	entityType string
+	load       func(db *gorm.DB, ids []uint) ([]SearchDocument, error)
+}
+
+// searchSources maps catalog table names to their search document loaders
+var searchSources = map[string]searchSource{
+	"characters": {"people", func(db *gorm.DB, ids []uint) ([]SearchDocument, error) {
+		var rows []models.Character
+		err := whereIDs(db, ids).Find(&rows).Error
+		docs := make([]SearchDocument, len(rows))
+		for i, r := range rows {
+			docs[i] = SearchDocument{"people", r.ID, r.URL, r.Name, joinText(r.Gender, r.BirthYear, r.HairColor, r.EyeColor)}
+		}
+		return docs, err
+	}},
+	"films": {"films", func(db *gorm.DB, ids []uint) ([]SearchDocument, error) {
+		var rows []models.Film
+		err := whereIDs(db, ids).Find(&rows).Error
+		docs := make([]SearchDocument, len(rows))
+		for i, r := range rows {
+			docs[i] = SearchDocument{"films", r.ID, r.URL, r.Title, joinText(r.OpeningCrawl, r.Director, r.Producer)}
+		}
+		return docs, err
+	}},
+	"planets": {"planets", func(db *gorm.DB, ids []uint) ([]SearchDocument, error) {
+		var rows []models.Planet
+		err := whereIDs(db, ids).Find(&rows).Error
+		docs := make([]SearchDocument, len(rows))
+		for i, r := range rows {
+			docs[i] = SearchDocument{"planets", r.ID, r.URL, r.Name, joinText(r.Climate, r.Terrain)}
+		}
+		return docs, err
+	}},
+	"species": {"species", func(db *gorm.DB, ids []uint) ([]SearchDocument, error) {
+		var rows []models.Species
+		err := whereIDs(db, ids).Find(&rows).Error
+		docs := make([]SearchDocument, len(rows))
+		for i, r := range rows {
+			docs[i] = SearchDocument{"species", r.ID, r.URL, r.Name, joinText(r.Classification, r.Designation, r.Language)}
+		}
+		return docs, err
+	}},
+	"starships": {"starships", func(db *gorm.DB, ids []uint) ([]SearchDocument, error) {
+		var rows []models.Starship
+		err := whereIDs(db, ids).Find(&rows).Error
+		docs := make([]SearchDocument, len(rows))
+		for i, r := range rows {
+			docs[i] = SearchDocument{"starships", r.ID, r.URL, r.Name, joinText(r.Model, r.Manufacturer, r.StarshipClass)}
+		}
+		return docs, err
+	}},
+	"vehicles": {"vehicles", func(db *gorm.DB, ids []uint) ([]SearchDocument, error) {
+		var rows []models.Vehicle
+		err := whereIDs(db, ids).Find(&rows).Error
+		docs := make([]SearchDocument, len(rows))
+		for i, r := range rows {
+			docs[i] = SearchDocument{"vehicles", r.ID, r.URL, r.Name, joinText(r.Model, r.Manufacturer, r.VehicleClass)}
+		}
+		return docs, err
+	}},
+	"organizations": {"organizations", func(db *gorm.DB, ids []uint) ([]SearchDocument, error) {
+		var rows []models.Organization
+		err := whereIDs(db, ids).Find(&rows).Error
+		docs := make([]SearchDocument, len(rows))
+		for i, r := range rows {
+			docs[i] = SearchDocument{"organizations", r.ID, r.URL, r.Name, joinText(r.Type, r.Description, r.Leader)}
+		}
+		return docs, err
+	}},
+	"weapons": {"weapons", func(db *gorm.DB, ids []uint) ([]SearchDocument, error) {
+		var rows []models.Weapon
+		err := whereIDs(db, ids).Find(&rows).Error
+		docs := make([]SearchDocument, len(rows))
+		for i, r := range rows {
+			docs[i] = SearchDocument{"weapons", r.ID, r.URL, r.Name, joinText(r.Type, r.Model, r.Manufacturer, r.Description)}
+		}
+		return docs, err
+	}},
+	"events": {"events", func(db *gorm.DB, ids []uint) ([]SearchDocument, error) {
+		var rows []models.Event
+		err := whereIDs(db, ids).Find(&rows).Error
+		docs := make([]SearchDocument, len(rows))
+		for i, r := range rows {
+			docs[i] = SearchDocument{"events", r.ID, r.URL, r.Name, joinText(r.Type, r.Location, r.Era, r.Description, r.Outcome)}
+		}
+		return docs, err
+	}},
+}
+
+// SearchEntityTypes returns the entity types stored in the search index
+func SearchEntityTypes() []string {
+	types := make([]string, 0, len(searchSources))
+	for _, source := range searchSources {
+		types = append(types, source.entityType)
+	}
+	return types
+}
+
+// SetupSearchIndex detects whether the search table made by the migrations is
+// an FTS5 table, registers the callbacks that keep it in sync with catalog
+// writes and builds it if it is empty
+func SetupSearchIndex(db *gorm.DB) error {
+	if IsSQLite(db) {
+		var definition string
+		err := db.Raw("SELECT sql FROM sqlite_master WHERE name = ?", SearchTable).Scan(&definition).Error
+		if err != nil {
+			return fmt.Errorf("failed to inspect search table: %w", err)
+		}
+		ftsEnabled = strings.Contains(strings.ToLower(definition), "using fts5")
+		if !ftsEnabled {
+			log.Println("Search table is not an FTS5 table, falling back to LIKE search; build with -tags sqlite_fts5 before migrating for ranked search")
+		}
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Register("search:index_create", reindexCallback); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("search:index_update", reindexCallback); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("search:index_delete", pruneCallback); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := db.Table(SearchTable).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to count search documents: %w", err)
+	}
+	if count == 0 {
+		return RebuildSearchIndex(db)
+	}
+	return nil
+}
+
+// RebuildSearchIndex replaces the whole search index with the current catalog
+func RebuildSearchIndex(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM " + SearchTable).Error; err != nil {
+			return err
+		}
+		for table := range searchSources {
+			if err := indexEntities(tx, table, nil); err != nil {
+				return err
+			}
+		}
+		log.Println("Search index rebuilt")
+		return nil
+	})
+}
+
+// indexEntities (re)writes the search documents of the given rows of a table
+func indexEntities(db *gorm.DB, table string, ids []uint) error {
+	source := searchSources[table]
+
+	docs, err := source.load(db, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load %s for indexing: %w", table, err)
+	}
+
+	if ids == nil {
+		err = db.Exec("DELETE FROM "+SearchTable+" WHERE entity_type = ?", source.entityType).Error
+	} else {
+		err = db.Exec("DELETE FROM "+SearchTable+" WHERE entity_type = ? AND entity_id IN ?", source.entityType, ids).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		if err := db.Exec("INSERT INTO "+SearchTable+" (entity_type, entity_id, url, title, body) VALUES (?, ?, ?, ?, ?)",
+			doc.EntityType, doc.EntityID, doc.URL, doc.Title, doc.Body).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reindexCallback refreshes the search documents of created or updated catalog rows
+func reindexCallback(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.Schema == nil {
+		return
+	}
+	table := tx.Statement.Schema.Table
+	if _, ok := searchSources[table]; !ok {
+		return
+	}
+
+	// Batch updates without primary keys reindex the whole table
+	ids := primaryKeys(tx)
+	if len(ids) == 0 {
+		ids = nil
+	}
+
+	session := tx.Session(&gorm.Session{NewDB: true})
+	if err := indexEntities(session, table, ids); err != nil {
+		log.Printf("Error updating search index for %s: %v", table, err)
+	}
+}
+
+// pruneCallback removes search documents whose catalog rows were deleted
+func pruneCallback(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.Schema == nil {
+		return
+	}
+	table := tx.Statement.Schema.Table
+	source, ok := searchSources[table]
+	if !ok {
+		return
+	}
+
+	session := tx.Session(&gorm.Session{NewDB: true})
+	if err := session.Exec("DELETE FROM "+SearchTable+" WHERE entity_type = ? AND entity_id NOT IN (SELECT id FROM "+table+")",
+		source.entityType).Error; err != nil {
+		log.Printf("Error pruning search index for %s: %v", table, err)
+	}
+}
+
+// primaryKeys collects the non-zero primary keys of the statement's model values
+func primaryKeys(tx *gorm.DB) []uint {
+	field := tx.Statement.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return nil
+	}
+
+	var ids []uint
+	collect := func(value reflect.Value) {
+		if id, zero := field.ValueOf(tx.Statement.Context, value); !zero {
+			if id, ok := id.(uint); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	value := reflect.Indirect(tx.Statement.ReflectValue)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			collect(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		collect(value)
+	}
+	return ids
+}
+
+// whereIDs limits a query to the given IDs unless ids is nil
+func whereIDs(db *gorm.DB, ids []uint) *gorm.DB {
+	if ids == nil {
+		return db
+	}
+	return db.Where("id IN ?", ids)
+}
+
+// joinText joins the non-empty fields of a search document body
+func joinText(fields ...string) string {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" && field != "n/a" && field != "unknown" {
+			parts = append(parts, field)
+		}
+	}
+	return strings.Join(parts, " ")
+}
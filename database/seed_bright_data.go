@@ -1,8 +1,10 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"reflect"
 
 	"starwars-api/models"
 
@@ -172,12 +174,12 @@ func SeedBrightDataStarships(db *gorm.DB) error {
 	}
 
 	// Create starships
-	if err := db.Create(&millenniumFalcon).Error; err != nil {
+	if err := saveEnhanced(db, &millenniumFalcon); err != nil {
 		log.Printf("Error creating Millennium Falcon: %v", err)
 		return err
 	}
 
-	if err := db.Create(&xWingFighter).Error; err != nil {
+	if err := saveEnhanced(db, &xWingFighter); err != nil {
 		log.Printf("Error creating X-Wing: %v", err)
 		return err
 	}
@@ -384,12 +386,12 @@ func SeedBrightDataPlanets(db *gorm.DB) error {
 	}
 
 	// Create planets
-	if err := db.Create(&tatooine).Error; err != nil {
+	if err := saveEnhanced(db, &tatooine); err != nil {
 		log.Printf("Error creating Tatooine: %v", err)
 		return err
 	}
 
-	if err := db.Create(&hoth).Error; err != nil {
+	if err := saveEnhanced(db, &hoth); err != nil {
 		log.Printf("Error creating Hoth: %v", err)
 		return err
 	}
@@ -561,12 +563,12 @@ func SeedAdditionalStarships(db *gorm.DB) error {
 	}
 
 	// Create starships
-	if err := db.Create(&tieFighter).Error; err != nil {
+	if err := saveEnhanced(db, &tieFighter); err != nil {
 		log.Printf("Error creating TIE Fighter: %v", err)
 		return err
 	}
 
-	if err := db.Create(&starDestroyer).Error; err != nil {
+	if err := saveEnhanced(db, &starDestroyer); err != nil {
 		log.Printf("Error creating Star Destroyer: %v", err)
 		return err
 	}
@@ -710,12 +712,12 @@ func SeedAdditionalPlanets(db *gorm.DB) error {
 	}
 
 	// Create planets
-	if err := db.Create(&coruscant).Error; err != nil {
+	if err := saveEnhanced(db, &coruscant); err != nil {
 		log.Printf("Error creating Coruscant: %v", err)
 		return err
 	}
 
-	if err := db.Create(&endor).Error; err != nil {
+	if err := saveEnhanced(db, &endor); err != nil {
 		log.Printf("Error creating Endor: %v", err)
 		return err
 	}
@@ -726,6 +728,15 @@ func SeedAdditionalPlanets(db *gorm.DB) error {
 
 // SeedAllBrightDataEnhancements seeds all enhanced data from Bright Data MCP
 func SeedAllBrightDataEnhancements(db *gorm.DB) error {
+	// The weapon systems are only created along with the enhancements
+	var count int64
+	if err := db.Model(&models.WeaponSystem{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
 	log.Println("Starting comprehensive Bright Data MCP seeding...")
 
 	// Seed enhanced starships
@@ -751,3 +762,36 @@ func SeedAllBrightDataEnhancements(db *gorm.DB) error {
 	log.Println("All Bright Data MCP enhancements seeded successfully!")
 	return nil
 }
+
+// saveEnhanced stores an enhanced starship or planet. A catalog entry with the
+// same name, or failing that the same URL, only gets the embedded enhancement
+// columns, keeping its SWAPI fields.
+func saveEnhanced(db *gorm.DB, row interface{}) error {
+	value := reflect.ValueOf(row).Elem()
+	existing := reflect.New(value.Type())
+
+	err := db.Where("LOWER(name) = LOWER(?)", value.FieldByName("Name").String()).Take(existing.Interface()).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = db.Where("url = ?", value.FieldByName("URL").String()).Take(existing.Interface()).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return db.Create(row).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(row); err != nil {
+		return err
+	}
+	var columns []string
+	for _, field := range stmt.Schema.Fields {
+		if len(field.BindNames) > 1 && field.DBName != "" {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	value.FieldByName("ID").Set(existing.Elem().FieldByName("ID"))
+	return db.Model(existing.Interface()).Select(columns).Updates(row).Error
+}
@@ -0,0 +1,508 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"starwars-api/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ImportCounts tallies what an import did with the records of one resource
+type ImportCounts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+// ImportReport summarizes a SWAPI dump import
+type ImportReport struct {
+	Resources  map[string]*ImportCounts `json:"resources"`
+	Unresolved int                      `json:"unresolved"` // relation URLs with no matching record
+}
+
+// Total sums the counts of every resource
+func (r *ImportReport) Total() ImportCounts {
+	var total ImportCounts
+	for _, counts := range r.Resources {
+		total.Created += counts.Created
+		total.Updated += counts.Updated
+		total.Skipped += counts.Skipped
+	}
+	return total
+}
+
+// swapiEntity is a decoded SWAPI record that maps onto a catalog model
+type swapiEntity interface {
+	resourceURL() string
+	// model returns a pointer to a new catalog model holding the record's columns
+	model() interface{}
+	// relations maps association names of the model to the URLs they link to
+	relations() map[string][]string
+}
+
+// swapiTimestamps are the metadata fields shared by every SWAPI resource
+type swapiTimestamps struct {
+	Created string `json:"created"`
+	Edited  string `json:"edited"`
+	URL     string `json:"url"`
+}
+
+func (t swapiTimestamps) resourceURL() string {
+	return t.URL
+}
+
+// times parses the created/edited timestamps, leaving unparseable ones zero
+// so the database fills them in
+func (t swapiTimestamps) times() (created, edited time.Time) {
+	created, _ = time.Parse(time.RFC3339Nano, t.Created)
+	edited, _ = time.Parse(time.RFC3339Nano, t.Edited)
+	return created, edited
+}
+
+type swapiPerson struct {
+	swapiTimestamps
+	Name      string   `json:"name"`
+	Height    string   `json:"height"`
+	Mass      string   `json:"mass"`
+	HairColor string   `json:"hair_color"`
+	EyeColor  string   `json:"eye_color"`
+	BirthYear string   `json:"birth_year"`
+	Gender    string   `json:"gender"`
+	Homeworld string   `json:"homeworld"`
+	Films     []string `json:"films"`
+	Species   []string `json:"species"`
+	Vehicles  []string `json:"vehicles"`
+	Starships []string `json:"starships"`
+}
+
+func (p swapiPerson) model() interface{} {
+	created, edited := p.times()
+	return &models.Character{
+		URL: p.URL, Name: p.Name, Height: p.Height, Mass: p.Mass, HairColor: p.HairColor, EyeColor: p.EyeColor,
+		BirthYear: p.BirthYear, Gender: p.Gender, Homeworld: p.Homeworld, CreatedAt: created, UpdatedAt: edited,
+	}
+}
+
+func (p swapiPerson) relations() map[string][]string {
+	return map[string][]string{"Films": p.Films, "Species": p.Species, "Vehicles": p.Vehicles, "Starships": p.Starships}
+}
+
+type swapiFilm struct {
+	swapiTimestamps
+	Title        string   `json:"title"`
+	EpisodeID    int      `json:"episode_id"`
+	OpeningCrawl string   `json:"opening_crawl"`
+	Director     string   `json:"director"`
+	Producer     string   `json:"producer"`
+	ReleaseDate  string   `json:"release_date"`
+	Characters   []string `json:"characters"`
+	Planets      []string `json:"planets"`
+	Starships    []string `json:"starships"`
+	Vehicles     []string `json:"vehicles"`
+	Species      []string `json:"species"`
+}
+
+func (f swapiFilm) model() interface{} {
+	created, edited := f.times()
+	return &models.Film{
+		URL: f.URL, Title: f.Title, EpisodeID: f.EpisodeID, OpeningCrawl: f.OpeningCrawl, Director: f.Director,
+		Producer: f.Producer, ReleaseDate: f.ReleaseDate, CreatedAt: created, UpdatedAt: edited,
+	}
+}
+
+func (f swapiFilm) relations() map[string][]string {
+	return map[string][]string{
+		"Characters": f.Characters, "Planets": f.Planets, "Starships": f.Starships, "Vehicles": f.Vehicles, "Species": f.Species,
+	}
+}
+
+type swapiPlanet struct {
+	swapiTimestamps
+	Name           string   `json:"name"`
+	RotationPeriod string   `json:"rotation_period"`
+	OrbitalPeriod  string   `json:"orbital_period"`
+	Diameter       string   `json:"diameter"`
+	Climate        string   `json:"climate"`
+	Gravity        string   `json:"gravity"`
+	Terrain        string   `json:"terrain"`
+	SurfaceWater   string   `json:"surface_water"`
+	Population     string   `json:"population"`
+	Films          []string `json:"films"`
+}
+
+func (p swapiPlanet) model() interface{} {
+	created, edited := p.times()
+	return &models.Planet{
+		URL: p.URL, Name: p.Name, RotationPeriod: p.RotationPeriod, OrbitalPeriod: p.OrbitalPeriod, Diameter: p.Diameter,
+		Climate: p.Climate, Gravity: p.Gravity, Terrain: p.Terrain, SurfaceWater: p.SurfaceWater, Population: p.Population,
+		CreatedAt: created, UpdatedAt: edited,
+	}
+}
+
+// relations leaves out residents, which follow from each person's homeworld
+func (p swapiPlanet) relations() map[string][]string {
+	return map[string][]string{"Films": p.Films}
+}
+
+type swapiSpecies struct {
+	swapiTimestamps
+	Name            string   `json:"name"`
+	Classification  string   `json:"classification"`
+	Designation     string   `json:"designation"`
+	AverageHeight   string   `json:"average_height"`
+	SkinColors      string   `json:"skin_colors"`
+	HairColors      string   `json:"hair_colors"`
+	EyeColors       string   `json:"eye_colors"`
+	AverageLifespan string   `json:"average_lifespan"`
+	Homeworld       *string  `json:"homeworld"`
+	Language        string   `json:"language"`
+	People          []string `json:"people"`
+	Films           []string `json:"films"`
+}
+
+func (s swapiSpecies) model() interface{} {
+	created, edited := s.times()
+	species := &models.Species{
+		URL: s.URL, Name: s.Name, Classification: s.Classification, Designation: s.Designation,
+		AverageHeight: s.AverageHeight, AverageLifespan: s.AverageLifespan, SkinColors: s.SkinColors,
+		HairColors: s.HairColors, EyeColors: s.EyeColors, Language: s.Language, CreatedAt: created, UpdatedAt: edited,
+	}
+	if s.Homeworld != nil {
+		species.HomeworldURL = *s.Homeworld
+	}
+	return species
+}
+
+func (s swapiSpecies) relations() map[string][]string {
+	return map[string][]string{"Characters": s.People, "Films": s.Films}
+}
+
+// swapiCraft holds the fields shared by starships and vehicles
+type swapiCraft struct {
+	swapiTimestamps
+	Name                 string   `json:"name"`
+	Model                string   `json:"model"`
+	Manufacturer         string   `json:"manufacturer"`
+	CostInCredits        string   `json:"cost_in_credits"`
+	Length               string   `json:"length"`
+	MaxAtmospheringSpeed string   `json:"max_atmosphering_speed"`
+	Crew                 string   `json:"crew"`
+	Passengers           string   `json:"passengers"`
+	CargoCapacity        string   `json:"cargo_capacity"`
+	Consumables          string   `json:"consumables"`
+	Pilots               []string `json:"pilots"`
+	Films                []string `json:"films"`
+}
+
+func (c swapiCraft) relations() map[string][]string {
+	return map[string][]string{"Pilots": c.Pilots, "Films": c.Films}
+}
+
+type swapiStarship struct {
+	swapiCraft
+	HyperdriveRating string `json:"hyperdrive_rating"`
+	MGLT             string `json:"MGLT"`
+	StarshipClass    string `json:"starship_class"`
+}
+
+func (s swapiStarship) model() interface{} {
+	created, edited := s.times()
+	return &models.Starship{
+		URL: s.URL, Name: s.Name, Model: s.Model, Manufacturer: s.Manufacturer, CostInCredits: s.CostInCredits,
+		Length: s.Length, MaxAtmospheringSpeed: s.MaxAtmospheringSpeed, Crew: s.Crew, Passengers: s.Passengers,
+		CargoCapacity: s.CargoCapacity, Consumables: s.Consumables, HyperdriveRating: s.HyperdriveRating,
+		MGLT: s.MGLT, StarshipClass: s.StarshipClass, CreatedAt: created, UpdatedAt: edited,
+	}
+}
+
+type swapiVehicle struct {
+	swapiCraft
+	VehicleClass string `json:"vehicle_class"`
+}
+
+func (v swapiVehicle) model() interface{} {
+	created, edited := v.times()
+	return &models.Vehicle{
+		URL: v.URL, Name: v.Name, Model: v.Model, Manufacturer: v.Manufacturer, CostInCredits: v.CostInCredits,
+		Length: v.Length, MaxAtmospheringSpeed: v.MaxAtmospheringSpeed, Crew: v.Crew, Passengers: v.Passengers,
+		CargoCapacity: v.CargoCapacity, Consumables: v.Consumables, VehicleClass: v.VehicleClass,
+		CreatedAt: created, UpdatedAt: edited,
+	}
+}
+
+// swapiResources lists the importable resources and their decoders in import order
+var swapiResources = []struct {
+	name   string
+	decode func(raw json.RawMessage) (swapiEntity, error)
+}{
+	{"films", func(raw json.RawMessage) (swapiEntity, error) { var r swapiFilm; return r, json.Unmarshal(raw, &r) }},
+	{"planets", func(raw json.RawMessage) (swapiEntity, error) { var r swapiPlanet; return r, json.Unmarshal(raw, &r) }},
+	{"species", func(raw json.RawMessage) (swapiEntity, error) { var r swapiSpecies; return r, json.Unmarshal(raw, &r) }},
+	{"starships", func(raw json.RawMessage) (swapiEntity, error) { var r swapiStarship; return r, json.Unmarshal(raw, &r) }},
+	{"vehicles", func(raw json.RawMessage) (swapiEntity, error) { var r swapiVehicle; return r, json.Unmarshal(raw, &r) }},
+	{"people", func(raw json.RawMessage) (swapiEntity, error) { var r swapiPerson; return r, json.Unmarshal(raw, &r) }},
+}
+
+// importedRecord tracks a record between the column and relation passes
+type importedRecord struct {
+	entity swapiEntity
+	row    interface{}
+	counts *ImportCounts
+	status string // created, updated, unchanged
+}
+
+// ImportSWAPI upserts the SWAPI JSON dump in dir into the catalog, matching
+// records by URL. Each resource is read from <resource>.json, holding an array,
+// a SWAPI page with "results" or a single record, or from the JSON files of a
+// <resource>/ directory. Relations listed by a record replace its existing ones.
+func ImportSWAPI(db *gorm.DB, dir string) (*ImportReport, error) {
+	report := &ImportReport{Resources: make(map[string]*ImportCounts)}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var records []*importedRecord
+
+		// Columns first, so that every relation target exists before linking
+		for _, resource := range swapiResources {
+			raws, found, err := readSWAPIResource(dir, resource.name)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+
+			counts := &ImportCounts{}
+			report.Resources[resource.name] = counts
+
+			for _, raw := range raws {
+				entity, err := resource.decode(raw)
+				if err != nil || entity.resourceURL() == "" {
+					log.Printf("Skipping invalid %s record: %v", resource.name, err)
+					counts.Skipped++
+					continue
+				}
+
+				record := &importedRecord{entity: entity, counts: counts}
+				if err := upsertColumns(tx, record); err != nil {
+					return fmt.Errorf("failed to import %s: %w", entity.resourceURL(), err)
+				}
+				records = append(records, record)
+			}
+		}
+
+		if len(report.Resources) == 0 {
+			return fmt.Errorf("no SWAPI resources found in %s", dir)
+		}
+
+		for _, record := range records {
+			changed, unresolved, err := replaceRelations(tx, record)
+			if err != nil {
+				return fmt.Errorf("failed to link %s: %w", record.entity.resourceURL(), err)
+			}
+			report.Unresolved += unresolved
+			if changed && record.status == "unchanged" {
+				record.status = "updated"
+			}
+
+			switch record.status {
+			case "created":
+				record.counts.Created++
+			case "updated":
+				record.counts.Updated++
+			default:
+				record.counts.Skipped++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// upsertColumns creates the record's row or updates the columns that changed
+func upsertColumns(tx *gorm.DB, record *importedRecord) error {
+	incoming := record.entity.model()
+	existing := reflect.New(reflect.TypeOf(incoming).Elem()).Interface()
+
+	err := tx.Where("url = ?", record.entity.resourceURL()).Take(existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		record.row, record.status = incoming, "created"
+		return tx.Create(incoming).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	record.row, record.status = existing, "unchanged"
+	changed := changedColumns(existing, incoming)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	record.status = "updated"
+	if err := tx.Model(existing).Select(changed).Updates(incoming).Error; err != nil {
+		return err
+	}
+	// Keep the loaded row current for the relation pass
+	return tx.Where("url = ?", record.entity.resourceURL()).Take(existing).Error
+}
+
+// changedColumns lists the plain fields of incoming that differ from existing,
+// ignoring the primary key, timestamps, relations and enhanced data
+func changedColumns(existing, incoming interface{}) []string {
+	a, b := reflect.ValueOf(existing).Elem(), reflect.ValueOf(incoming).Elem()
+
+	var changed []string
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		switch field.Name {
+		case "ID", "CreatedAt", "UpdatedAt":
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.String, reflect.Int, reflect.Int64, reflect.Float64, reflect.Bool:
+			if a.Field(i).Interface() != b.Field(i).Interface() {
+				changed = append(changed, field.Name)
+			}
+		}
+	}
+	return changed
+}
+
+// replaceRelations points the record's associations at the listed URLs. It
+// reports whether any association changed and how many URLs matched no record.
+func replaceRelations(tx *gorm.DB, record *importedRecord) (bool, int, error) {
+	changed, unresolved := false, 0
+	row := reflect.ValueOf(record.row).Elem()
+
+	names := make([]string, 0)
+	for name := range record.entity.relations() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		urls := uniqueStrings(record.entity.relations()[name])
+		sliceType := row.FieldByName(name).Type()
+
+		targets := reflect.New(sliceType)
+		if len(urls) > 0 {
+			if err := tx.Where("url IN ?", urls).Find(targets.Interface()).Error; err != nil {
+				return false, 0, err
+			}
+		}
+		if missing := len(urls) - targets.Elem().Len(); missing > 0 {
+			unresolved += missing
+		}
+
+		current := reflect.New(sliceType)
+		association := tx.Model(record.row).Association(name)
+		if err := association.Find(current.Interface()); err != nil {
+			return false, 0, err
+		}
+		if sameURLs(current.Elem(), targets.Elem()) {
+			continue
+		}
+
+		if err := association.Replace(targets.Interface()); err != nil {
+			return false, 0, err
+		}
+		changed = true
+	}
+
+	return changed, unresolved, nil
+}
+
+// sameURLs reports whether two slices of catalog models hold the same records
+func sameURLs(a, b reflect.Value) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	urls := make(map[string]bool, a.Len())
+	for i := 0; i < a.Len(); i++ {
+		urls[a.Index(i).FieldByName("URL").String()] = true
+	}
+	for i := 0; i < b.Len(); i++ {
+		if !urls[b.Index(i).FieldByName("URL").String()] {
+			return false
+		}
+	}
+	return true
+}
+
+// readSWAPIResource returns the raw records of a resource, and false if the
+// dump does not contain it
+func readSWAPIResource(dir, name string) ([]json.RawMessage, bool, error) {
+	var files []string
+	if _, err := os.Stat(filepath.Join(dir, name+".json")); err == nil {
+		files = []string{filepath.Join(dir, name+".json")}
+	} else if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(dir, name, "*.json"))
+		if err != nil {
+			return nil, false, err
+		}
+		sort.Strings(files)
+	}
+	if len(files) == 0 {
+		return nil, false, nil
+	}
+
+	var records []json.RawMessage
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		raws, err := splitSWAPIDocument(data)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		records = append(records, raws...)
+	}
+	return records, true, nil
+}
+
+// splitSWAPIDocument accepts an array of records, a page with "results" or a
+// single record
+func splitSWAPIDocument(data []byte) ([]json.RawMessage, error) {
+	var records []json.RawMessage
+	if err := json.Unmarshal(data, &records); err == nil {
+		return records, nil
+	}
+
+	var page struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, err
+	}
+	if page.Results != nil {
+		return page.Results, nil
+	}
+	return []json.RawMessage{data}, nil
+}
+
+// uniqueStrings drops empty and duplicate values, keeping order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
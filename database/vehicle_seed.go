@@ -0,0 +1,207 @@
+package database
+
+import (
+	"log"
+	"starwars-api/models"
+
+	"gorm.io/gorm"
+)
+
+// vehicleSeed pairs a vehicle with the SWAPI URLs of its pilots and films
+type vehicleSeed struct {
+	vehicle models.Vehicle
+	pilots  []string
+	films   []string
+}
+
+// SeedVehicles creates the vehicles piloted by the seeded characters
+func SeedVehicles(db *gorm.DB) {
+	var count int64
+	db.Model(&models.Vehicle{}).Count(&count)
+
+	if count > 0 {
+		log.Println("Vehicles already exist, skipping seed")
+		return
+	}
+
+	log.Println("Seeding vehicles...")
+
+	seeds := []vehicleSeed{
+		{
+			vehicle: models.Vehicle{
+				URL:                  "https://swapi.dev/api/vehicles/14/",
+				Name:                 "Snowspeeder",
+				Model:                "t-47 airspeeder",
+				Manufacturer:         "Incom corporation",
+				CostInCredits:        "unknown",
+				Length:               "4.5",
+				MaxAtmospheringSpeed: "650",
+				Crew:                 "2",
+				Passengers:           "0",
+				CargoCapacity:        "10",
+				Consumables:          "none",
+				VehicleClass:         "airspeeder",
+			},
+			pilots: []string{"https://swapi.dev/api/people/1/"},
+			films:  []string{"https://swapi.dev/api/films/5/"},
+		},
+		{
+			vehicle: models.Vehicle{
+				URL:                  "https://swapi.dev/api/vehicles/19/",
+				Name:                 "AT-ST",
+				Model:                "All Terrain Scout Transport",
+				Manufacturer:         "Kuat Drive Yards, Imperial Department of Military Research",
+				CostInCredits:        "unknown",
+				Length:               "2",
+				MaxAtmospheringSpeed: "90",
+				Crew:                 "2",
+				Passengers:           "0",
+				CargoCapacity:        "200",
+				Consumables:          "none",
+				VehicleClass:         "walker",
+			},
+			pilots: []string{"https://swapi.dev/api/people/4/"},
+			films:  []string{"https://swapi.dev/api/films/5/", "https://swapi.dev/api/films/6/"},
+		},
+		{
+			vehicle: models.Vehicle{
+				URL:                  "https://swapi.dev/api/vehicles/30/",
+				Name:                 "Imperial Speeder Bike",
+				Model:                "74-Z speeder bike",
+				Manufacturer:         "Aratech Repulsor Company",
+				CostInCredits:        "8000",
+				Length:               "3",
+				MaxAtmospheringSpeed: "360",
+				Crew:                 "1",
+				Passengers:           "1",
+				CargoCapacity:        "4",
+				Consumables:          "1 day",
+				VehicleClass:         "speeder",
+			},
+			pilots: []string{"https://swapi.dev/api/people/1/", "https://swapi.dev/api/people/2/"},
+			films:  []string{"https://swapi.dev/api/films/6/"},
+		},
+		{
+			vehicle: models.Vehicle{
+				URL:                  "https://swapi.dev/api/vehicles/38/",
+				Name:                 "Tribubble bongo",
+				Model:                "Tribubble bongo",
+				Manufacturer:         "Otoh Gunga Bongameken Cooperative",
+				CostInCredits:        "unknown",
+				Length:               "15",
+				MaxAtmospheringSpeed: "85",
+				Crew:                 "1",
+				Passengers:           "2",
+				CargoCapacity:        "1600",
+				Consumables:          "unknown",
+				VehicleClass:         "submarine",
+			},
+			pilots: []string{"https://swapi.dev/api/people/9/", "https://swapi.dev/api/people/14/"},
+			films:  []string{"https://swapi.dev/api/films/1/"},
+		},
+		{
+			vehicle: models.Vehicle{
+				URL:                  "https://swapi.dev/api/vehicles/42/",
+				Name:                 "Sith speeder",
+				Model:                "FC-20 speeder bike",
+				Manufacturer:         "Razalon",
+				CostInCredits:        "4000",
+				Length:               "1.5",
+				MaxAtmospheringSpeed: "180",
+				Crew:                 "1",
+				Passengers:           "0",
+				CargoCapacity:        "2",
+				Consumables:          "unknown",
+				VehicleClass:         "speeder",
+			},
+			pilots: []string{"https://swapi.dev/api/people/24/"},
+			films:  []string{"https://swapi.dev/api/films/1/"},
+		},
+		{
+			vehicle: models.Vehicle{
+				URL:                  "https://swapi.dev/api/vehicles/44/",
+				Name:                 "Zephyr-G swoop bike",
+				Model:                "Zephyr-G swoop bike",
+				Manufacturer:         "Mobquet Swoops and Speeders",
+				CostInCredits:        "5750",
+				Length:               "3.68",
+				MaxAtmospheringSpeed: "350",
+				Crew:                 "1",
+				Passengers:           "1",
+				CargoCapacity:        "200",
+				Consumables:          "none",
+				VehicleClass:         "repulsorcraft",
+			},
+			pilots: []string{"https://swapi.dev/api/people/11/"},
+			films:  []string{"https://swapi.dev/api/films/2/"},
+		},
+		{
+			vehicle: models.Vehicle{
+				URL:                  "https://swapi.dev/api/vehicles/46/",
+				Name:                 "XJ-6 airspeeder",
+				Model:                "XJ-6 airspeeder",
+				Manufacturer:         "Narglatch AirTech prefabricated kit",
+				CostInCredits:        "unknown",
+				Length:               "6.23",
+				MaxAtmospheringSpeed: "720",
+				Crew:                 "1",
+				Passengers:           "1",
+				CargoCapacity:        "unknown",
+				Consumables:          "unknown",
+				VehicleClass:         "airspeeder",
+			},
+			pilots: []string{"https://swapi.dev/api/people/11/"},
+			films:  []string{"https://swapi.dev/api/films/2/"},
+		},
+		{
+			vehicle: models.Vehicle{
+				URL:                  "https://swapi.dev/api/vehicles/55/",
+				Name:                 "Flitknot speeder",
+				Model:                "Flitknot speeder",
+				Manufacturer:         "Huppla Pasa Tisc Shipwrights Collective",
+				CostInCredits:        "8000",
+				Length:               "2",
+				MaxAtmospheringSpeed: "634",
+				Crew:                 "1",
+				Passengers:           "0",
+				CargoCapacity:        "unknown",
+				Consumables:          "unknown",
+				VehicleClass:         "speeder",
+			},
+			pilots: []string{"https://swapi.dev/api/people/23/"},
+			films:  []string{"https://swapi.dev/api/films/2/"},
+		},
+		{
+			vehicle: models.Vehicle{
+				URL:                  "https://swapi.dev/api/vehicles/60/",
+				Name:                 "Tsmeu-6 personal wheel bike",
+				Model:                "Tsmeu-6 personal wheel bike",
+				Manufacturer:         "Z-Gomot Ternbuell Guppat Corporation",
+				CostInCredits:        "15000",
+				Length:               "3.5",
+				MaxAtmospheringSpeed: "330",
+				Crew:                 "1",
+				Passengers:           "1",
+				CargoCapacity:        "10",
+				Consumables:          "none",
+				VehicleClass:         "wheeled walker",
+			},
+			pilots: []string{"https://swapi.dev/api/people/25/"},
+			films:  []string{"https://swapi.dev/api/films/3/"},
+		},
+	}
+
+	for _, seed := range seeds {
+		vehicle := seed.vehicle
+
+		// Link pilots and films by their SWAPI URLs
+		db.Where("url IN ?", seed.pilots).Find(&vehicle.Pilots)
+		db.Where("url IN ?", seed.films).Find(&vehicle.Films)
+
+		if err := db.Create(&vehicle).Error; err != nil {
+			log.Printf("Error creating vehicle %s: %v", vehicle.Name, err)
+		}
+	}
+
+	log.Printf("Seeded %d vehicles", len(seeds))
+}
@@ -0,0 +1,190 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// fetchFunc loads the values of a batch of keys in a constant number of queries.
+// Every requested key must be present in the returned map.
+type fetchFunc func(db *gorm.DB, keys []interface{}) (map[interface{}]interface{}, error)
+
+// batch collects the keys requested by sibling fields so they are fetched together
+type batch struct {
+	fetch   fetchFunc
+	pending []interface{}
+	queued  map[interface{}]bool
+	results map[interface{}]interface{}
+	errors  map[interface{}]error
+}
+
+// Loader batches relation lookups for the lifetime of a single GraphQL request.
+// Resolvers return thunks; graphql-go resolves every field of a level before it
+// calls them, so the first thunk fetches the keys of the whole level at once.
+type Loader struct {
+	db      *gorm.DB
+	mu      sync.Mutex
+	batches map[string]*batch
+}
+
+func NewLoader(db *gorm.DB) *Loader {
+	return &Loader{db: db, batches: make(map[string]*batch)}
+}
+
+type loaderKey struct{}
+
+// WithLoader attaches a request loader to the context
+func WithLoader(ctx context.Context, loader *Loader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, loader)
+}
+
+// loaderFrom returns the request loader, or an unshared one if there is none
+func loaderFrom(ctx context.Context, db *gorm.DB) *Loader {
+	if loader, ok := ctx.Value(loaderKey{}).(*Loader); ok {
+		return loader
+	}
+	return NewLoader(db)
+}
+
+// Load queues a key in the named batch and returns a thunk resolving its value
+func (l *Loader) Load(name string, fetch fetchFunc, key interface{}) func() (interface{}, error) {
+	l.mu.Lock()
+	b, ok := l.batches[name]
+	if !ok {
+		b = &batch{
+			fetch:   fetch,
+			queued:  make(map[interface{}]bool),
+			results: make(map[interface{}]interface{}),
+			errors:  make(map[interface{}]error),
+		}
+		l.batches[name] = b
+	}
+	if !b.queued[key] {
+		b.queued[key] = true
+		b.pending = append(b.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(b.pending) > 0 {
+			keys := b.pending
+			b.pending = nil
+
+			results, err := b.fetch(l.db, keys)
+			for _, k := range keys {
+				if err != nil {
+					b.errors[k] = fmt.Errorf("failed to load %s: %w", name, err)
+				} else {
+					b.results[k] = results[k]
+				}
+			}
+		}
+
+		if err := b.errors[key]; err != nil {
+			return nil, err
+		}
+		return b.results[key], nil
+	}
+}
+
+// preloadFetch loads a GORM association of model for a batch of owner IDs
+func preloadFetch(model interface{}, association string) fetchFunc {
+	modelType := reflect.TypeOf(model)
+	relation, _ := modelType.FieldByName(association)
+	return func(db *gorm.DB, keys []interface{}) (map[interface{}]interface{}, error) {
+		owners := reflect.New(reflect.SliceOf(modelType))
+		if err := db.Preload(association).Where("id IN ?", keys).Find(owners.Interface()).Error; err != nil {
+			return nil, err
+		}
+
+		results := make(map[interface{}]interface{}, len(keys))
+		for _, key := range keys {
+			results[key] = reflect.MakeSlice(relation.Type, 0, 0).Interface()
+		}
+		rows := owners.Elem()
+		for i := 0; i < rows.Len(); i++ {
+			row := rows.Index(i)
+			results[idOf(row.FieldByName("ID"))] = row.FieldByName(association).Interface()
+		}
+		return results, nil
+	}
+}
+
+// groupFetch loads the rows of model whose column holds one of the keys,
+// grouped by the key; field is the struct field mapped to column
+func groupFetch(model interface{}, column, field string) fetchFunc {
+	modelType := reflect.TypeOf(model)
+	return func(db *gorm.DB, keys []interface{}) (map[interface{}]interface{}, error) {
+		rows := reflect.New(reflect.SliceOf(modelType))
+		if err := db.Where(column+" IN ?", keys).Order("id").Find(rows.Interface()).Error; err != nil {
+			return nil, err
+		}
+
+		groups := make(map[interface{}]reflect.Value, len(keys))
+		for _, key := range keys {
+			groups[key] = reflect.MakeSlice(reflect.SliceOf(modelType), 0, 0)
+		}
+		for i := 0; i < rows.Elem().Len(); i++ {
+			row := rows.Elem().Index(i)
+			key := keyOf(row.FieldByName(field))
+			if group, ok := groups[key]; ok {
+				groups[key] = reflect.Append(group, row)
+			}
+		}
+
+		results := make(map[interface{}]interface{}, len(groups))
+		for key, group := range groups {
+			results[key] = group.Interface()
+		}
+		return results, nil
+	}
+}
+
+// rowFetch loads single rows of model by a unique column; missing rows resolve to null
+func rowFetch(model interface{}, column, field string) fetchFunc {
+	modelType := reflect.TypeOf(model)
+	return func(db *gorm.DB, keys []interface{}) (map[interface{}]interface{}, error) {
+		rows := reflect.New(reflect.SliceOf(modelType))
+		if err := db.Where(column+" IN ?", keys).Find(rows.Interface()).Error; err != nil {
+			return nil, err
+		}
+
+		results := make(map[interface{}]interface{}, len(keys))
+		for i := 0; i < rows.Elem().Len(); i++ {
+			row := rows.Elem().Index(i)
+			results[keyOf(row.FieldByName(field))] = row.Interface()
+		}
+		return results, nil
+	}
+}
+
+// keyOf normalizes a batch key so that int, uint and *uint IDs compare equal
+func keyOf(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.String {
+		return value.String()
+	}
+	return idOf(value)
+}
+
+// idOf converts an integer primary key to uint
+func idOf(value reflect.Value) uint {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint(value.Int())
+	default:
+		return uint(value.Uint())
+	}
+}
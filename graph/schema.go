@@ -0,0 +1,513 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"starwars-api/models"
+	"starwars-api/services"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"gorm.io/gorm"
+)
+
+// Schema is the GraphQL schema over the catalog and the read-only game models
+type Schema struct {
+	schema graphql.Schema
+	db     *gorm.DB
+}
+
+// NewSchema builds the GraphQL schema backed by the given database
+func NewSchema(db *gorm.DB) (*Schema, error) {
+	b := &builder{db: db}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: b.query()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL schema: %w", err)
+	}
+	return &Schema{schema: schema, db: db}, nil
+}
+
+// Execute runs a GraphQL request with its own loader, so batched results are
+// never shared between requests
+func (s *Schema) Execute(ctx context.Context, query string, variables map[string]interface{}, operationName string) *graphql.Result {
+	return graphql.Do(graphql.Params{
+		Schema:         s.schema,
+		RequestString:  query,
+		VariableValues: variables,
+		OperationName:  operationName,
+		Context:        WithLoader(ctx, NewLoader(s.db)),
+	})
+}
+
+// builder holds the object types while the schema is assembled; fields are
+// thunks because the catalog types reference each other
+type builder struct {
+	db *gorm.DB
+
+	character, film, species, starship, vehicle, planet *graphql.Object
+	organization, weapon, event                         *graphql.Object
+	player, fleet, ship, mission, objective             *graphql.Object
+}
+
+func (b *builder) query() *graphql.Object {
+	b.character = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Character",
+		Description: "A person or droid",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"url":       attr(graphql.String, "URL"),
+				"name":      attr(graphql.String, "Name"),
+				"birthYear": attr(graphql.String, "BirthYear"),
+				"eyeColor":  attr(graphql.String, "EyeColor"),
+				"gender":    attr(graphql.String, "Gender"),
+				"hairColor": attr(graphql.String, "HairColor"),
+				"height":    attr(graphql.String, "Height"),
+				"mass":      attr(graphql.String, "Mass"),
+				"created":   attr(graphql.DateTime, "CreatedAt"),
+				"edited":    attr(graphql.DateTime, "UpdatedAt"),
+				"homeworld": b.one(b.planet, "Character.homeworld", "Homeworld", rowFetch(models.Planet{}, "url", "URL")),
+				"films":     b.many(b.film, "Character.films", preloadFetch(models.Character{}, "Films")),
+				"species":   b.many(b.species, "Character.species", preloadFetch(models.Character{}, "Species")),
+				"starships": b.many(b.starship, "Character.starships", preloadFetch(models.Character{}, "Starships")),
+				"vehicles":  b.many(b.vehicle, "Character.vehicles", preloadFetch(models.Character{}, "Vehicles")),
+			}
+		}),
+	})
+
+	b.film = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Film",
+		Description: "A Star Wars movie",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"url":          attr(graphql.String, "URL"),
+				"title":        attr(graphql.String, "Title"),
+				"episodeId":    attr(graphql.Int, "EpisodeID"),
+				"openingCrawl": attr(graphql.String, "OpeningCrawl"),
+				"director":     attr(graphql.String, "Director"),
+				"producer":     attr(graphql.String, "Producer"),
+				"releaseDate":  attr(graphql.String, "ReleaseDate"),
+				"created":      attr(graphql.DateTime, "CreatedAt"),
+				"edited":       attr(graphql.DateTime, "UpdatedAt"),
+				"characters":   b.many(b.character, "Film.characters", preloadFetch(models.Film{}, "Characters")),
+				"planets":      b.many(b.planet, "Film.planets", preloadFetch(models.Film{}, "Planets")),
+				"species":      b.many(b.species, "Film.species", preloadFetch(models.Film{}, "Species")),
+				"starships":    b.many(b.starship, "Film.starships", preloadFetch(models.Film{}, "Starships")),
+				"vehicles":     b.many(b.vehicle, "Film.vehicles", preloadFetch(models.Film{}, "Vehicles")),
+			}
+		}),
+	})
+
+	b.species = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Species",
+		Description: "A type of person or character",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":              attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"url":             attr(graphql.String, "URL"),
+				"name":            attr(graphql.String, "Name"),
+				"classification":  attr(graphql.String, "Classification"),
+				"designation":     attr(graphql.String, "Designation"),
+				"averageHeight":   attr(graphql.String, "AverageHeight"),
+				"averageLifespan": attr(graphql.String, "AverageLifespan"),
+				"skinColors":      attr(graphql.String, "SkinColors"),
+				"hairColors":      attr(graphql.String, "HairColors"),
+				"eyeColors":       attr(graphql.String, "EyeColors"),
+				"language":        attr(graphql.String, "Language"),
+				"created":         attr(graphql.DateTime, "CreatedAt"),
+				"edited":          attr(graphql.DateTime, "UpdatedAt"),
+				"homeworld":       b.one(b.planet, "Species.homeworld", "HomeworldURL", rowFetch(models.Planet{}, "url", "URL")),
+				"people":          b.many(b.character, "Species.people", preloadFetch(models.Species{}, "Characters")),
+				"films":           b.many(b.film, "Species.films", preloadFetch(models.Species{}, "Films")),
+			}
+		}),
+	})
+
+	b.starship = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Starship",
+		Description: "A hyperdrive-capable transport craft",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                   attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"url":                  attr(graphql.String, "URL"),
+				"name":                 attr(graphql.String, "Name"),
+				"model":                attr(graphql.String, "Model"),
+				"manufacturer":         attr(graphql.String, "Manufacturer"),
+				"costInCredits":        attr(graphql.String, "CostInCredits"),
+				"length":               attr(graphql.String, "Length"),
+				"maxAtmospheringSpeed": attr(graphql.String, "MaxAtmospheringSpeed"),
+				"crew":                 attr(graphql.String, "Crew"),
+				"passengers":           attr(graphql.String, "Passengers"),
+				"cargoCapacity":        attr(graphql.String, "CargoCapacity"),
+				"consumables":          attr(graphql.String, "Consumables"),
+				"hyperdriveRating":     attr(graphql.String, "HyperdriveRating"),
+				"MGLT":                 attr(graphql.String, "MGLT"),
+				"starshipClass":        attr(graphql.String, "StarshipClass"),
+				"created":              attr(graphql.DateTime, "CreatedAt"),
+				"edited":               attr(graphql.DateTime, "UpdatedAt"),
+				"pilots":               b.many(b.character, "Starship.pilots", preloadFetch(models.Starship{}, "Pilots")),
+				"films":                b.many(b.film, "Starship.films", preloadFetch(models.Starship{}, "Films")),
+			}
+		}),
+	})
+
+	b.vehicle = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Vehicle",
+		Description: "A transport craft without hyperdrive capability",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                   attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"url":                  attr(graphql.String, "URL"),
+				"name":                 attr(graphql.String, "Name"),
+				"model":                attr(graphql.String, "Model"),
+				"manufacturer":         attr(graphql.String, "Manufacturer"),
+				"costInCredits":        attr(graphql.String, "CostInCredits"),
+				"length":               attr(graphql.String, "Length"),
+				"maxAtmospheringSpeed": attr(graphql.String, "MaxAtmospheringSpeed"),
+				"crew":                 attr(graphql.String, "Crew"),
+				"passengers":           attr(graphql.String, "Passengers"),
+				"cargoCapacity":        attr(graphql.String, "CargoCapacity"),
+				"consumables":          attr(graphql.String, "Consumables"),
+				"vehicleClass":         attr(graphql.String, "VehicleClass"),
+				"created":              attr(graphql.DateTime, "CreatedAt"),
+				"edited":               attr(graphql.DateTime, "UpdatedAt"),
+				"pilots":               b.many(b.character, "Vehicle.pilots", preloadFetch(models.Vehicle{}, "Pilots")),
+				"films":                b.many(b.film, "Vehicle.films", preloadFetch(models.Vehicle{}, "Films")),
+			}
+		}),
+	})
+
+	b.planet = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Planet",
+		Description: "A large mass, planet or planetoid",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":             attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"url":            attr(graphql.String, "URL"),
+				"name":           attr(graphql.String, "Name"),
+				"rotationPeriod": attr(graphql.String, "RotationPeriod"),
+				"orbitalPeriod":  attr(graphql.String, "OrbitalPeriod"),
+				"diameter":       attr(graphql.String, "Diameter"),
+				"climate":        attr(graphql.String, "Climate"),
+				"gravity":        attr(graphql.String, "Gravity"),
+				"terrain":        attr(graphql.String, "Terrain"),
+				"surfaceWater":   attr(graphql.String, "SurfaceWater"),
+				"population":     attr(graphql.String, "Population"),
+				"created":        attr(graphql.DateTime, "CreatedAt"),
+				"edited":         attr(graphql.DateTime, "UpdatedAt"),
+				"residents":      b.many(b.character, "Planet.residents", preloadFetch(models.Planet{}, "Residents")),
+				"films":          b.many(b.film, "Planet.films", preloadFetch(models.Planet{}, "Films")),
+			}
+		}),
+	})
+
+	b.organization = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Organization",
+		Description: "A government, military, religious or criminal organization",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"url":         attr(graphql.String, "URL"),
+				"name":        attr(graphql.String, "Name"),
+				"type":        attr(graphql.String, "Type"),
+				"description": attr(graphql.String, "Description"),
+				"founded":     attr(graphql.String, "Founded"),
+				"dissolved":   attr(graphql.String, "Dissolved"),
+				"leader":      attr(graphql.String, "Leader"),
+				"created":     attr(graphql.DateTime, "CreatedAt"),
+				"edited":      attr(graphql.DateTime, "UpdatedAt"),
+				"homeworld":   b.one(b.planet, "Organization.homeworld", "Homeworld", rowFetch(models.Planet{}, "url", "URL")),
+				"members":     b.many(b.character, "Organization.members", preloadFetch(models.Organization{}, "Members")),
+			}
+		}),
+	})
+
+	b.weapon = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Weapon",
+		Description: "A lightsaber, blaster, superweapon or other armament",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"url":          attr(graphql.String, "URL"),
+				"name":         attr(graphql.String, "Name"),
+				"type":         attr(graphql.String, "Type"),
+				"manufacturer": attr(graphql.String, "Manufacturer"),
+				"model":        attr(graphql.String, "Model"),
+				"description":  attr(graphql.String, "Description"),
+				"length":       attr(graphql.String, "Length"),
+				"weight":       attr(graphql.String, "Weight"),
+				"color":        attr(graphql.String, "Color"),
+				"crystalType":  attr(graphql.String, "CrystalType"),
+				"created":      attr(graphql.DateTime, "CreatedAt"),
+				"edited":       attr(graphql.DateTime, "UpdatedAt"),
+				"owners":       b.many(b.character, "Weapon.owners", preloadFetch(models.Weapon{}, "Owners")),
+			}
+		}),
+	})
+
+	b.event = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Event",
+		Description: "A battle, treaty, founding or other significant event",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":   attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"url":  attr(graphql.String, "URL"),
+				"name": attr(graphql.String, "Name"),
+				"type": attr(graphql.String, "Type"),
+				"date": attr(graphql.String, "Date"),
+				"year": &graphql.Field{
+					Type:        graphql.Float,
+					Description: "Year relative to the Battle of Yavin, negative before it",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if year, err := services.ParseGalacticYear(p.Source.(models.Event).Date); err == nil {
+							return year, nil
+						}
+						return nil, nil
+					},
+				},
+				"location":     attr(graphql.String, "Location"),
+				"description":  attr(graphql.String, "Description"),
+				"outcome":      attr(graphql.String, "Outcome"),
+				"era":          attr(graphql.String, "Era"),
+				"created":      attr(graphql.DateTime, "CreatedAt"),
+				"edited":       attr(graphql.DateTime, "UpdatedAt"),
+				"participants": b.many(b.character, "Event.participants", preloadFetch(models.Event{}, "Participants")),
+				"films":        b.many(b.film, "Event.films", preloadFetch(models.Event{}, "Films")),
+			}
+		}),
+	})
+
+	b.player = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Player",
+		Description: "A game player; read-only",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":         attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"username":   attr(graphql.String, "Username"),
+				"level":      attr(graphql.Int, "Level"),
+				"experience": attr(graphql.Int, "Experience"),
+				"credits":    attr(graphql.Int, "Credits"),
+				"avatar":     attr(graphql.String, "Avatar"),
+				"createdAt":  attr(graphql.DateTime, "CreatedAt"),
+				"fleets":     b.many(b.fleet, "Player.fleets", groupFetch(models.Fleet{}, "player_id", "PlayerID")),
+				"ships":      b.many(b.ship, "Player.ships", groupFetch(models.Ship{}, "player_id", "PlayerID")),
+			}
+		}),
+	})
+
+	b.fleet = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Fleet",
+		Description: "A player's group of ships; read-only",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"name":        attr(graphql.String, "Name"),
+				"description": attr(graphql.String, "Description"),
+				"maxShips":    attr(graphql.Int, "MaxShips"),
+				"isActive":    attr(graphql.Boolean, "IsActive"),
+				"location":    attr(graphql.String, "Location"),
+				"player":      b.one(b.player, "Fleet.player", "PlayerID", rowFetch(models.Player{}, "id", "ID")),
+				"ships":       b.many(b.ship, "Fleet.ships", groupFetch(models.Ship{}, "fleet_id", "FleetID")),
+			}
+		}),
+	})
+
+	b.ship = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Ship",
+		Description: "A ship owned by a player; read-only",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"name":        attr(graphql.String, "Name"),
+				"class":       attr(graphql.String, "Class"),
+				"faction":     attr(graphql.String, "Faction"),
+				"model":       attr(graphql.String, "Model"),
+				"health":      attr(graphql.Int, "Health"),
+				"maxHealth":   attr(graphql.Int, "MaxHealth"),
+				"shield":      attr(graphql.Int, "Shield"),
+				"maxShield":   attr(graphql.Int, "MaxShield"),
+				"attack":      attr(graphql.Int, "Attack"),
+				"defense":     attr(graphql.Int, "Defense"),
+				"speed":       attr(graphql.Int, "Speed"),
+				"maneuver":    attr(graphql.Int, "Maneuver"),
+				"isActive":    attr(graphql.Boolean, "IsActive"),
+				"isDestroyed": attr(graphql.Boolean, "IsDestroyed"),
+				"location":    attr(graphql.String, "Location"),
+			}
+		}),
+	})
+
+	b.objective = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "MissionObjective",
+		Description: "A step of a mission",
+		Fields: graphql.Fields{
+			"id":               attr(graphql.NewNonNull(graphql.ID), "ID"),
+			"name":             attr(graphql.String, "Name"),
+			"description":      attr(graphql.String, "Description"),
+			"type":             attr(graphql.String, "Type"),
+			"target":           attr(graphql.String, "Target"),
+			"targetCount":      attr(graphql.Int, "TargetCount"),
+			"isOptional":       attr(graphql.Boolean, "IsOptional"),
+			"orderIndex":       attr(graphql.Int, "OrderIndex"),
+			"experienceReward": attr(graphql.Int, "ExperienceReward"),
+			"creditsReward":    attr(graphql.Int, "CreditsReward"),
+		},
+	})
+
+	b.mission = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Mission",
+		Description: "A game mission; read-only",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                attr(graphql.NewNonNull(graphql.ID), "ID"),
+				"name":              attr(graphql.String, "Name"),
+				"description":       attr(graphql.String, "Description"),
+				"shortDescription":  attr(graphql.String, "ShortDescription"),
+				"type":              attr(graphql.String, "Type"),
+				"category":          attr(graphql.String, "Category"),
+				"difficulty":        attr(graphql.Int, "Difficulty"),
+				"minLevel":          attr(graphql.Int, "MinLevel"),
+				"maxLevel":          attr(graphql.Int, "MaxLevel"),
+				"estimatedDuration": attr(graphql.Int, "EstimatedDuration"),
+				"era":               attr(graphql.String, "Era"),
+				"planet":            attr(graphql.String, "Planet"),
+				"faction":           attr(graphql.String, "Faction"),
+				"characters":        attr(graphql.NewList(graphql.String), "Characters"),
+				"experienceReward":  attr(graphql.Int, "ExperienceReward"),
+				"creditsReward":     attr(graphql.Int, "CreditsReward"),
+				"itemRewards":       attr(graphql.NewList(graphql.String), "ItemRewards"),
+				"isActive":          attr(graphql.Boolean, "IsActive"),
+				"isRepeatable":      attr(graphql.Boolean, "IsRepeatable"),
+				"objectives":        b.many(b.objective, "Mission.objectives", preloadFetch(models.Mission{}, "Objectives")),
+			}
+		}),
+	})
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"character":        b.item(b.character, models.Character{}),
+			"allCharacters":    b.list(b.character, models.Character{}, "name"),
+			"film":             b.item(b.film, models.Film{}),
+			"allFilms":         b.list(b.film, models.Film{}, "title"),
+			"species":          b.item(b.species, models.Species{}),
+			"allSpecies":       b.list(b.species, models.Species{}, "name"),
+			"starship":         b.item(b.starship, models.Starship{}),
+			"allStarships":     b.list(b.starship, models.Starship{}, "name"),
+			"vehicle":          b.item(b.vehicle, models.Vehicle{}),
+			"allVehicles":      b.list(b.vehicle, models.Vehicle{}, "name"),
+			"planet":           b.item(b.planet, models.Planet{}),
+			"allPlanets":       b.list(b.planet, models.Planet{}, "name"),
+			"organization":     b.item(b.organization, models.Organization{}),
+			"allOrganizations": b.list(b.organization, models.Organization{}, "name"),
+			"weapon":           b.item(b.weapon, models.Weapon{}),
+			"allWeapons":       b.list(b.weapon, models.Weapon{}, "name"),
+			"event":            b.item(b.event, models.Event{}),
+			"allEvents":        b.list(b.event, models.Event{}, "name"),
+			"player":           b.item(b.player, models.Player{}),
+			"allPlayers":       b.list(b.player, models.Player{}, "username"),
+			"fleet":            b.item(b.fleet, models.Fleet{}),
+			"mission":          b.item(b.mission, models.Mission{}),
+			"allMissions":      b.list(b.mission, models.Mission{}, "name"),
+		},
+	})
+}
+
+// attr resolves a field from the named struct field of the source model
+func attr(fieldType graphql.Output, name string) *graphql.Field {
+	return &graphql.Field{
+		Type: fieldType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return reflect.ValueOf(p.Source).FieldByName(name).Interface(), nil
+		},
+	}
+}
+
+// many resolves a batched to-many relation keyed by the source's ID
+func (b *builder) many(objectType *graphql.Object, name string, fetch fetchFunc) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(objectType))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			key := keyOf(reflect.ValueOf(p.Source).FieldByName("ID"))
+			return loaderFrom(p.Context, b.db).Load(name, fetch, key), nil
+		},
+	}
+}
+
+// one resolves a batched to-one relation keyed by the named field of the source
+func (b *builder) one(objectType *graphql.Object, name, field string, fetch fetchFunc) *graphql.Field {
+	return &graphql.Field{
+		Type: objectType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			key := keyOf(reflect.ValueOf(p.Source).FieldByName(field))
+			if key == nil || key == "" {
+				return nil, nil
+			}
+			return loaderFrom(p.Context, b.db).Load(name, fetch, key), nil
+		},
+	}
+}
+
+// item resolves a single row of model by ID, or null if it does not exist
+func (b *builder) item(objectType *graphql.Object, model interface{}) *graphql.Field {
+	modelType := reflect.TypeOf(model)
+	return &graphql.Field{
+		Type: objectType,
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, err := strconv.ParseUint(fmt.Sprint(p.Args["id"]), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid id %q", p.Args["id"])
+			}
+
+			row := reflect.New(modelType)
+			if err := b.db.First(row.Interface(), id).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, nil
+				}
+				return nil, err
+			}
+			return row.Elem().Interface(), nil
+		},
+	}
+}
+
+// list resolves a page of model rows, optionally filtered by a name search
+func (b *builder) list(objectType *graphql.Object, model interface{}, searchColumn string) *graphql.Field {
+	modelType := reflect.TypeOf(model)
+	return &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(objectType))),
+		Args: graphql.FieldConfigArgument{
+			"page":   &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+			"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+			"search": &graphql.ArgumentConfig{Type: graphql.String},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			page, _ := p.Args["page"].(int)
+			if page < 1 {
+				return nil, errors.New("page must be a positive integer")
+			}
+			limit, _ := p.Args["limit"].(int)
+			if limit < 1 || limit > 100 {
+				limit = 10 // Default to 10 if invalid
+			}
+
+			query := b.db.Order("id").Offset((page - 1) * limit).Limit(limit)
+			if search, _ := p.Args["search"].(string); strings.TrimSpace(search) != "" {
+				query = query.Where("LOWER("+searchColumn+") LIKE ?", "%"+strings.ToLower(strings.TrimSpace(search))+"%")
+			}
+
+			rows := reflect.New(reflect.SliceOf(modelType))
+			if err := query.Find(rows.Interface()).Error; err != nil {
+				return nil, err
+			}
+			return rows.Elem().Interface(), nil
+		},
+	}
+}
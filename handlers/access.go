@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"starwars-api/middleware"
+	"starwars-api/models"
+	"starwars-api/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccessHandler struct {
+	accessService *services.AccessService
+}
+
+func NewAccessHandler(accessService *services.AccessService) *AccessHandler {
+	return &AccessHandler{accessService: accessService}
+}
+
+// SetPlayerRole changes the role of a player
+// PUT /api/v1/admin/players/:id/role
+func (h *AccessHandler) SetPlayerRole(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid parameter",
+			Message: "Invalid player ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	player, err := h.accessService.SetPlayerRole(uint(playerID), req.Role)
+	if err != nil {
+		h.accessError(c, err, "Failed to update role")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      player,
+		Message:   "Role updated successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// ListAPIKeys lists the API keys of service accounts
+// GET /api/v1/admin/api-keys
+func (h *AccessHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.accessService.ListAPIKeys()
+	if err != nil {
+		h.accessError(c, err, "Failed to list API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data: map[string]interface{}{
+			"api_keys": keys,
+			"count":    len(keys),
+		},
+		Message:   "API keys retrieved successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// CreateAPIKey creates an API key; the key is only ever shown in this response
+// POST /api/v1/admin/api-keys
+func (h *AccessHandler) CreateAPIKey(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	apiKey, key, err := h.accessService.CreateAPIKey(req.Name, req.Role)
+	if err != nil {
+		h.accessError(c, err, "Failed to create API key")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Data: map[string]interface{}{
+			"api_key": apiKey,
+			"key":     key,
+		},
+		Message:   "API key created; store the key now, it cannot be shown again",
+		Timestamp: time.Now(),
+	})
+}
+
+// RevokeAPIKey revokes an API key
+// DELETE /api/v1/admin/api-keys/:id
+func (h *AccessHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid parameter",
+			Message: "Invalid API key ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.accessService.RevokeAPIKey(uint(id)); err != nil {
+		h.accessError(c, err, "Failed to revoke API key")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      nil,
+		Message:   "API key revoked successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// ListAuditLog returns the audit log of privileged routes, newest first
+// GET /api/v1/admin/audit?outcome=denied&page=1&limit=10
+func (h *AccessHandler) ListAuditLog(c *gin.Context) {
+	outcome := c.Query("outcome")
+	if outcome != "" && outcome != models.AuditAllowed && outcome != models.AuditDenied {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid outcome parameter",
+			Message: "Outcome must be allowed or denied",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	params, ok := parseListQuery(c, listSpec{})
+	if !ok {
+		return
+	}
+
+	entries, total, err := h.accessService.ListAuditEntries(outcome, params.Limit, (params.Page-1)*params.Limit)
+	if err != nil {
+		h.accessError(c, err, "Failed to list audit entries")
+		return
+	}
+
+	params.Respond(c, total, entries)
+}
+
+// accessError answers a failed access management request
+func (h *AccessHandler) accessError(c *gin.Context, err error, failure string) {
+	switch {
+	case errors.Is(err, services.ErrPlayerNotFound), errors.Is(err, services.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Not found",
+			Message: err.Error(),
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, services.ErrInvalidRole), errors.Is(err, services.ErrAPIKeyNameEmpty):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   failure,
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+	}
+}
+
+func RegisterAccessRoutes(router *gin.Engine, accessService *services.AccessService, roles *middleware.RoleAuth) {
+	handler := NewAccessHandler(accessService)
+
+	admin := router.Group("/api/v1/admin", roles.Require(models.RoleAdmin))
+	{
+		admin.PUT("/players/:id/role", handler.SetPlayerRole)
+		admin.GET("/api-keys", handler.ListAPIKeys)
+		admin.POST("/api-keys", handler.CreateAPIKey)
+		admin.DELETE("/api-keys/:id", handler.RevokeAPIKey)
+		admin.GET("/audit", handler.ListAuditLog)
+	}
+}
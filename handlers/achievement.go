@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+	"starwars-api/middleware"
+	"starwars-api/models"
 	"starwars-api/services"
 	"strconv"
 
@@ -309,7 +311,7 @@ func (h *AchievementHandler) GetAchievementRarities(c *gin.Context) {
 }
 
 // RegisterAchievementRoutes registers all achievement-related routes
-func RegisterAchievementRoutes(router *gin.Engine, achievementService *services.AchievementService) {
+func RegisterAchievementRoutes(router *gin.Engine, achievementService *services.AchievementService, playerAuth gin.HandlerFunc, roles *middleware.RoleAuth) {
 	handler := NewAchievementHandler(achievementService)
 
 	v1 := router.Group("/api/v1")
@@ -322,14 +324,16 @@ func RegisterAchievementRoutes(router *gin.Engine, achievementService *services.
 			achievements.GET("/rarities", handler.GetAchievementRarities)
 			achievements.GET("/category/:category", handler.GetAchievementsByCategory)
 
-			// Player achievements
-			achievements.GET("/:playerId", handler.GetPlayerAchievements)
-			achievements.GET("/:playerId/stats", handler.GetPlayerAchievementStats)
-			achievements.GET("/:playerId/unnotified", handler.GetUnnotifiedAchievements)
-			achievements.POST("/:playerId/initialize", handler.InitializePlayerAchievements)
-			achievements.POST("/:playerId/progress", handler.UpdateProgress)
-			achievements.POST("/:playerId/notify", handler.MarkAsNotified)
-			achievements.POST("/:playerId/:achievementId/claim", handler.ClaimRewards)
+			// Player achievements, for the player only
+			player := achievements.Group("/:playerId", playerAuth, middleware.PlayerOwnsParam("playerId"))
+			player.GET("", handler.GetPlayerAchievements)
+			player.GET("/stats", handler.GetPlayerAchievementStats)
+			player.GET("/unnotified", handler.GetUnnotifiedAchievements)
+			player.POST("/initialize", handler.InitializePlayerAchievements)
+			// Progress pays out rewards, so it is reported by moderators rather than players
+			achievements.POST("/:playerId/progress", roles.Require(models.RoleModerator, models.RoleAdmin), handler.UpdateProgress)
+			player.POST("/notify", handler.MarkAsNotified)
+			player.POST("/:achievementId/claim", handler.ClaimRewards)
 
 			// Leaderboard
 			achievements.GET("/leaderboard", handler.GetLeaderboard)
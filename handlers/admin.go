@@ -0,0 +1,614 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"starwars-api/middleware"
+	"starwars-api/models"
+	"starwars-api/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// adminFieldKind selects how an admin payload field is validated
+type adminFieldKind int
+
+const (
+	// adminText is free text up to MaxLength characters
+	adminText adminFieldKind = iota
+	// adminNumeric is a SWAPI number string such as "1,000", "30-165", "0.5" or "unknown"
+	adminNumeric
+	// adminInteger is a JSON integer
+	adminInteger
+	// adminURL is an absolute http(s) URL
+	adminURL
+	// adminReleaseDate is a YYYY-MM-DD date
+	adminReleaseDate
+	// adminGalacticDate is a BBY/ABY date such as "19BBY"
+	adminGalacticDate
+)
+
+// swapiNumberPattern matches the numeric strings used throughout SWAPI data
+var swapiNumberPattern = regexp.MustCompile(`^(?i:unknown|n/a|none|indefinite|[\d,]+(\.\d+)?(\s*-\s*[\d,]+(\.\d+)?)?)$`)
+
+// adminField maps a payload key to a model field
+type adminField struct {
+	Key       string
+	Field     string
+	Kind      adminFieldKind
+	Required  bool
+	MaxLength int // for text fields; 0 means 255
+}
+
+// adminResource declares how a catalog model is written through the admin API
+type adminResource struct {
+	newRow    func() interface{}
+	fields    []adminField
+	relations map[string]string // payload key -> association
+	preloads  []string          // associations the read transform needs
+	transform func(row interface{}) map[string]interface{}
+}
+
+// adminCraftFields are shared by starships and vehicles
+var adminCraftFields = []adminField{
+	{Key: "url", Field: "URL", Kind: adminURL, Required: true},
+	{Key: "name", Field: "Name", Required: true},
+	{Key: "model", Field: "Model"},
+	{Key: "manufacturer", Field: "Manufacturer"},
+	{Key: "cost_in_credits", Field: "CostInCredits", Kind: adminNumeric},
+	{Key: "length", Field: "Length", Kind: adminNumeric},
+	{Key: "max_atmosphering_speed", Field: "MaxAtmospheringSpeed"},
+	{Key: "crew", Field: "Crew", Kind: adminNumeric},
+	{Key: "passengers", Field: "Passengers", Kind: adminNumeric},
+	{Key: "cargo_capacity", Field: "CargoCapacity", Kind: adminNumeric},
+	{Key: "consumables", Field: "Consumables"},
+}
+
+// adminResources maps admin route names to the catalog models they edit
+var adminResources = map[string]adminResource{
+	"people": {
+		newRow: func() interface{} { return &models.Character{} },
+		fields: []adminField{
+			{Key: "url", Field: "URL", Kind: adminURL, Required: true},
+			{Key: "name", Field: "Name", Required: true},
+			{Key: "birth_year", Field: "BirthYear"},
+			{Key: "eye_color", Field: "EyeColor"},
+			{Key: "gender", Field: "Gender"},
+			{Key: "homeworld", Field: "Homeworld", Kind: adminURL},
+			{Key: "hair_color", Field: "HairColor"},
+			{Key: "height", Field: "Height", Kind: adminNumeric},
+			{Key: "mass", Field: "Mass", Kind: adminNumeric},
+		},
+		relations: map[string]string{"films": "Films", "species": "Species", "starships": "Starships", "vehicles": "Vehicles"},
+		transform: func(row interface{}) map[string]interface{} {
+			return transformCharacterResponse(*row.(*models.Character))
+		},
+	},
+	"films": {
+		newRow: func() interface{} { return &models.Film{} },
+		fields: []adminField{
+			{Key: "url", Field: "URL", Kind: adminURL, Required: true},
+			{Key: "title", Field: "Title", Required: true},
+			{Key: "episode_id", Field: "EpisodeID", Kind: adminInteger},
+			{Key: "opening_crawl", Field: "OpeningCrawl", MaxLength: 10000},
+			{Key: "director", Field: "Director"},
+			{Key: "producer", Field: "Producer"},
+			{Key: "release_date", Field: "ReleaseDate", Kind: adminReleaseDate},
+		},
+		relations: map[string]string{
+			"characters": "Characters", "planets": "Planets", "species": "Species", "starships": "Starships", "vehicles": "Vehicles",
+		},
+		transform: func(row interface{}) map[string]interface{} {
+			return transformFilmResponse(*row.(*models.Film))
+		},
+	},
+	"species": {
+		newRow: func() interface{} { return &models.Species{} },
+		fields: []adminField{
+			{Key: "url", Field: "URL", Kind: adminURL, Required: true},
+			{Key: "name", Field: "Name", Required: true},
+			{Key: "classification", Field: "Classification"},
+			{Key: "designation", Field: "Designation"},
+			{Key: "average_height", Field: "AverageHeight", Kind: adminNumeric},
+			{Key: "average_lifespan", Field: "AverageLifespan", Kind: adminNumeric},
+			{Key: "skin_colors", Field: "SkinColors"},
+			{Key: "hair_colors", Field: "HairColors"},
+			{Key: "eye_colors", Field: "EyeColors"},
+			{Key: "language", Field: "Language"},
+			{Key: "homeworld", Field: "HomeworldURL", Kind: adminURL},
+		},
+		relations: map[string]string{"people": "Characters", "films": "Films"},
+		transform: func(row interface{}) map[string]interface{} {
+			return transformSpeciesResponse(*row.(*models.Species))
+		},
+	},
+	"starships": {
+		newRow: func() interface{} { return &models.Starship{} },
+		fields: append(append([]adminField{}, adminCraftFields...),
+			adminField{Key: "hyperdrive_rating", Field: "HyperdriveRating", Kind: adminNumeric},
+			adminField{Key: "MGLT", Field: "MGLT", Kind: adminNumeric},
+			adminField{Key: "starship_class", Field: "StarshipClass"},
+		),
+		relations: map[string]string{"pilots": "Pilots", "films": "Films"},
+		transform: func(row interface{}) map[string]interface{} {
+			return transformStarshipResponse(*row.(*models.Starship))
+		},
+	},
+	"vehicles": {
+		newRow: func() interface{} { return &models.Vehicle{} },
+		fields: append(append([]adminField{}, adminCraftFields...),
+			adminField{Key: "vehicle_class", Field: "VehicleClass"},
+		),
+		relations: map[string]string{"pilots": "Pilots", "films": "Films"},
+		transform: func(row interface{}) map[string]interface{} {
+			return transformVehicleResponse(*row.(*models.Vehicle))
+		},
+	},
+	"planets": {
+		newRow: func() interface{} { return &models.Planet{} },
+		fields: []adminField{
+			{Key: "url", Field: "URL", Kind: adminURL, Required: true},
+			{Key: "name", Field: "Name", Required: true},
+			{Key: "rotation_period", Field: "RotationPeriod", Kind: adminNumeric},
+			{Key: "orbital_period", Field: "OrbitalPeriod", Kind: adminNumeric},
+			{Key: "diameter", Field: "Diameter", Kind: adminNumeric},
+			{Key: "climate", Field: "Climate"},
+			{Key: "gravity", Field: "Gravity"},
+			{Key: "terrain", Field: "Terrain"},
+			{Key: "surface_water", Field: "SurfaceWater", Kind: adminNumeric},
+			{Key: "population", Field: "Population", Kind: adminNumeric},
+		},
+		// Residents follow from each character's homeworld
+		relations: map[string]string{"films": "Films"},
+		preloads:  []string{"Residents"},
+		transform: func(row interface{}) map[string]interface{} {
+			return transformPlanetResponse(*row.(*models.Planet))
+		},
+	},
+	"organizations": {
+		newRow: func() interface{} { return &models.Organization{} },
+		fields: []adminField{
+			{Key: "url", Field: "URL", Kind: adminURL, Required: true},
+			{Key: "name", Field: "Name", Required: true},
+			{Key: "type", Field: "Type"},
+			{Key: "description", Field: "Description", MaxLength: 10000},
+			{Key: "founded", Field: "Founded"},
+			{Key: "dissolved", Field: "Dissolved"},
+			{Key: "homeworld", Field: "Homeworld", Kind: adminURL},
+			{Key: "leader", Field: "Leader"},
+		},
+		relations: map[string]string{"members": "Members"},
+		transform: func(row interface{}) map[string]interface{} {
+			return transformOrganizationResponse(*row.(*models.Organization))
+		},
+	},
+	"weapons": {
+		newRow: func() interface{} { return &models.Weapon{} },
+		fields: []adminField{
+			{Key: "url", Field: "URL", Kind: adminURL, Required: true},
+			{Key: "name", Field: "Name", Required: true},
+			{Key: "type", Field: "Type"},
+			{Key: "manufacturer", Field: "Manufacturer"},
+			{Key: "model", Field: "Model"},
+			{Key: "description", Field: "Description", MaxLength: 10000},
+			{Key: "length", Field: "Length"},
+			{Key: "weight", Field: "Weight"},
+			{Key: "color", Field: "Color"},
+			{Key: "crystal_type", Field: "CrystalType"},
+		},
+		relations: map[string]string{"owners": "Owners"},
+		transform: func(row interface{}) map[string]interface{} {
+			return transformWeaponResponse(*row.(*models.Weapon))
+		},
+	},
+	"events": {
+		newRow: func() interface{} { return &models.Event{} },
+		fields: []adminField{
+			{Key: "url", Field: "URL", Kind: adminURL, Required: true},
+			{Key: "name", Field: "Name", Required: true},
+			{Key: "type", Field: "Type"},
+			{Key: "date", Field: "Date", Kind: adminGalacticDate, Required: true},
+			{Key: "location", Field: "Location"},
+			{Key: "description", Field: "Description", MaxLength: 10000},
+			{Key: "outcome", Field: "Outcome", MaxLength: 10000},
+			{Key: "era", Field: "Era"},
+		},
+		relations: map[string]string{"participants": "Participants", "films": "Films"},
+		transform: func(row interface{}) map[string]interface{} {
+			return transformEventResponse(*row.(*models.Event))
+		},
+	},
+}
+
+// adminInput is a validated admin payload
+type adminInput struct {
+	columns   map[string]interface{} // model field -> value
+	relations map[string][]string    // association -> URLs
+}
+
+type AdminHandler struct {
+	catalogAdminService *services.CatalogAdminService
+}
+
+func NewAdminHandler(catalogAdminService *services.CatalogAdminService) *AdminHandler {
+	return &AdminHandler{catalogAdminService: catalogAdminService}
+}
+
+// Create adds a catalog entity
+// POST /api/v1/admin/people {"url": "...", "name": "...", "films": ["https://swapi.dev/api/films/1/"]}
+func (h *AdminHandler) Create(name string) gin.HandlerFunc {
+	resource := adminResources[name]
+	return func(c *gin.Context) {
+		input, ok := parseAdminInput(c, resource, true)
+		if !ok {
+			return
+		}
+
+		row := resource.newRow()
+		for field, value := range input.columns {
+			reflect.ValueOf(row).Elem().FieldByName(field).Set(reflect.ValueOf(value))
+		}
+
+		if err := h.catalogAdminService.Create(row, input.relations); err != nil {
+			respondAdminError(c, name, err)
+			return
+		}
+		h.respond(c, http.StatusCreated, resource, row)
+	}
+}
+
+// Replace overwrites every field and relation of a catalog entity; omitted
+// fields are cleared
+// PUT /api/v1/admin/people/:id
+func (h *AdminHandler) Replace(name string) gin.HandlerFunc {
+	return h.update(name, true)
+}
+
+// Patch changes only the fields and relations present in the payload
+// PATCH /api/v1/admin/people/:id {"films": ["https://swapi.dev/api/films/4/"]}
+func (h *AdminHandler) Patch(name string) gin.HandlerFunc {
+	return h.update(name, false)
+}
+
+func (h *AdminHandler) update(name string, full bool) gin.HandlerFunc {
+	resource := adminResources[name]
+	return func(c *gin.Context) {
+		id, ok := parseAdminID(c)
+		if !ok {
+			return
+		}
+		input, ok := parseAdminInput(c, resource, full)
+		if !ok {
+			return
+		}
+
+		row := resource.newRow()
+		if err := h.catalogAdminService.Update(row, id, input.columns, input.relations); err != nil {
+			respondAdminError(c, name, err)
+			return
+		}
+		h.respond(c, http.StatusOK, resource, row)
+	}
+}
+
+// Delete removes a catalog entity and its relation links
+// DELETE /api/v1/admin/people/:id
+func (h *AdminHandler) Delete(name string) gin.HandlerFunc {
+	resource := adminResources[name]
+	return func(c *gin.Context) {
+		id, ok := parseAdminID(c)
+		if !ok {
+			return
+		}
+
+		if err := h.catalogAdminService.Delete(resource.newRow(), id); err != nil {
+			respondAdminError(c, name, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// respond reloads the entity with its relations and writes it in SWAPI format,
+// including every relation the admin API can edit
+func (h *AdminHandler) respond(c *gin.Context, status int, resource adminResource, row interface{}) {
+	id := uint(reflect.ValueOf(row).Elem().FieldByName("ID").Uint())
+
+	preloads := append([]string{}, resource.preloads...)
+	for _, association := range resource.relations {
+		preloads = append(preloads, association)
+	}
+
+	fresh := resource.newRow()
+	if err := h.catalogAdminService.Get(fresh, id, preloads...); err != nil {
+		log.Printf("Error reloading entity %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Entity was saved but could not be reloaded",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	response := resource.transform(fresh)
+	response["id"] = id
+	for key, association := range resource.relations {
+		related := reflect.ValueOf(fresh).Elem().FieldByName(association)
+		urls := make([]string, related.Len())
+		for i := range urls {
+			urls[i] = related.Index(i).FieldByName("URL").String()
+		}
+		response[key] = urls
+	}
+
+	c.JSON(status, response)
+}
+
+// parseAdminInput decodes and validates the JSON payload. With full set, every
+// field is taken from the payload, so missing required fields are errors and
+// missing optional fields and relations are cleared.
+func parseAdminInput(c *gin.Context, resource adminResource, full bool) (*adminInput, bool) {
+	var payload map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: "Body must be a JSON object",
+			Code:    http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	input := &adminInput{columns: make(map[string]interface{}), relations: make(map[string][]string)}
+	var problems []string
+	known := make(map[string]bool)
+
+	for _, field := range resource.fields {
+		known[field.Key] = true
+		raw, present := payload[field.Key]
+		if !present && !full {
+			continue
+		}
+
+		value, problem := validateAdminField(field, raw)
+		if problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+		input.columns[field.Field] = value
+	}
+
+	for key, association := range resource.relations {
+		known[key] = true
+		raw, present := payload[key]
+		if !present && !full {
+			continue
+		}
+
+		urls := []string{}
+		if present && !isJSONNull(raw) {
+			if err := json.Unmarshal(raw, &urls); err != nil {
+				problems = append(problems, key+" must be an array of URLs")
+				continue
+			}
+		}
+		input.relations[association] = uniqueURLs(urls)
+	}
+
+	for key := range payload {
+		if !known[key] {
+			problems = append(problems, key+" is not an editable field")
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: strings.Join(problems, "; "),
+			Code:    http.StatusBadRequest,
+		})
+		return nil, false
+	}
+	return input, true
+}
+
+// validateAdminField converts a raw payload value to the model field's value,
+// or describes why it is invalid; missing and null values become zero values
+func validateAdminField(field adminField, raw json.RawMessage) (interface{}, string) {
+	if field.Kind == adminInteger {
+		if raw == nil || isJSONNull(raw) {
+			if field.Required {
+				return nil, field.Key + " is required"
+			}
+			return 0, ""
+		}
+		var number int
+		if err := json.Unmarshal(raw, &number); err != nil {
+			return nil, field.Key + " must be an integer"
+		}
+		return number, ""
+	}
+
+	var text string
+	if raw != nil && !isJSONNull(raw) {
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, field.Key + " must be a string"
+		}
+	}
+	text = strings.TrimSpace(text)
+
+	if text == "" {
+		if field.Required {
+			return nil, field.Key + " is required"
+		}
+		return "", ""
+	}
+
+	maxLength := field.MaxLength
+	if maxLength == 0 {
+		maxLength = 255
+	}
+	if len([]rune(text)) > maxLength {
+		return nil, fmt.Sprintf("%s must be at most %d characters", field.Key, maxLength)
+	}
+
+	switch field.Kind {
+	case adminNumeric:
+		if !swapiNumberPattern.MatchString(text) {
+			return nil, field.Key + ` must be a number, a range or "unknown"`
+		}
+	case adminURL:
+		if parsed, err := url.Parse(text); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, field.Key + " must be an absolute http(s) URL"
+		}
+	case adminReleaseDate:
+		if _, err := time.Parse("2006-01-02", text); err != nil {
+			return nil, field.Key + " must be a YYYY-MM-DD date"
+		}
+	case adminGalacticDate:
+		if _, err := services.ParseGalacticYear(text); err != nil {
+			return nil, field.Key + " must be a galactic date such as 19BBY or 4ABY"
+		}
+	}
+	return text, ""
+}
+
+// parseAdminID reads the :id path parameter, writing a 400 if it is invalid
+func parseAdminID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid ID",
+			Message: "ID must be a positive integer",
+			Code:    http.StatusBadRequest,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondAdminError maps service errors to HTTP responses
+func respondAdminError(c *gin.Context, name string, err error) {
+	var unresolved *services.UnresolvedURLsError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Not found",
+			Message: "No " + name + " entity with this ID",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, services.ErrDuplicateURL):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Duplicate URL",
+			Message: "Another " + name + " entity already uses this url",
+			Code:    http.StatusConflict,
+		})
+	case errors.As(err, &unresolved):
+		relation := unresolved.Relation
+		for key, association := range adminResources[name].relations {
+			if association == unresolved.Relation {
+				relation = key
+			}
+		}
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "unknown " + relation + ": " + strings.Join(unresolved.URLs, ", "),
+			Code:    http.StatusBadRequest,
+		})
+	default:
+		log.Printf("Error writing %s: %v", name, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to save " + name,
+			Code:    http.StatusInternalServerError,
+		})
+	}
+}
+
+// Snapshot streams a consistent copy of the SQLite database file; PostgreSQL
+// databases are backed up with their own tools
+// GET /api/v1/admin/snapshot
+func (h *AdminHandler) Snapshot(c *gin.Context) {
+	dir, err := os.MkdirTemp("", "starwars-snapshot-")
+	if err != nil {
+		log.Printf("Error creating snapshot directory: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Snapshot error",
+			Message: "Failed to snapshot the database",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "starwars.db")
+	if err := h.catalogAdminService.Snapshot(path); err != nil {
+		if errors.Is(err, services.ErrSnapshotUnsupported) {
+			c.JSON(http.StatusNotImplemented, ErrorResponse{
+				Error:   "Snapshot unavailable",
+				Message: "Snapshots are only available for SQLite databases; use pg_dump for PostgreSQL",
+				Code:    http.StatusNotImplemented,
+			})
+			return
+		}
+		log.Printf("Error snapshotting database: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Snapshot error",
+			Message: "Failed to snapshot the database",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// Downloads are read-only copies; nothing writes back to them
+	c.Header("Content-Type", "application/vnd.sqlite3")
+	c.Header("Cache-Control", "no-store")
+	c.FileAttachment(path, "starwars-"+time.Now().UTC().Format("20060102T150405Z")+".db")
+}
+
+// isJSONNull reports whether a raw JSON value is null
+func isJSONNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// uniqueURLs trims URLs and drops empty and duplicate ones, keeping order
+func uniqueURLs(urls []string) []string {
+	seen := make(map[string]bool, len(urls))
+	unique := make([]string, 0, len(urls))
+	for _, u := range urls {
+		if u = strings.TrimSpace(u); u != "" && !seen[u] {
+			seen[u] = true
+			unique = append(unique, u)
+		}
+	}
+	return unique
+}
+
+func RegisterAdminRoutes(router *gin.Engine, catalogAdminService *services.CatalogAdminService, roles *middleware.RoleAuth) {
+	handler := NewAdminHandler(catalogAdminService)
+
+	admin := router.Group("/api/v1/admin", roles.Require(models.RoleAdmin))
+	{
+		for name := range adminResources {
+			admin.POST("/"+name, handler.Create(name))
+			admin.PUT("/"+name+"/:id", handler.Replace(name))
+			admin.PATCH("/"+name+"/:id", handler.Patch(name))
+			admin.DELETE("/"+name+"/:id", handler.Delete(name))
+		}
+		admin.GET("/snapshot", handler.Snapshot)
+	}
+}
@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"starwars-api/middleware"
+	"starwars-api/models"
+	"starwars-api/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	authService    *services.AuthService
+	accountService *services.AccountService
+}
+
+func NewAuthHandler(authService *services.AuthService, accountService *services.AccountService) *AuthHandler {
+	return &AuthHandler{authService: authService, accountService: accountService}
+}
+
+// Register creates a player with a password and logs it in
+// POST /api/v1/auth/register
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req struct {
+		Username string `json:"username" binding:"required"`
+		Email    string `json:"email" binding:"omitempty,email"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	player := models.Player{
+		Username: req.Username,
+		Email:    req.Email,
+		Role:     models.RolePlayer,
+	}
+	if err := h.authService.Register(&player, req.Password); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUsernameTaken):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "Username taken",
+				Message: err.Error(),
+				Code:    http.StatusConflict,
+			})
+		case errors.Is(err, services.ErrInvalidPassword):
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid password",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Registration failed",
+				Message: err.Error(),
+				Code:    http.StatusInternalServerError,
+			})
+		}
+		return
+	}
+
+	// A failed verification email does not fail the registration; it can be resent
+	if player.Email != "" {
+		if err := h.accountService.SendVerification(&player); err != nil {
+			log.Printf("Error sending verification email to player %d: %v", player.ID, err)
+		}
+	}
+
+	tokens, err := h.authService.Login(req.Username, req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Login failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Data:      tokens,
+		Message:   "Player registered successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// Login exchanges a username and password for tokens
+// POST /api/v1/auth/login
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req struct {
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	tokens, err := h.authService.Login(req.Username, req.Password)
+	if err != nil {
+		h.tokenError(c, err, "Login failed")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      tokens,
+		Message:   "Logged in successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// Refresh exchanges a refresh token for new tokens
+// POST /api/v1/auth/refresh
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	tokens, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		h.tokenError(c, err, "Refresh failed")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      tokens,
+		Message:   "Tokens refreshed successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// Logout ends the session of the access token
+// POST /api/v1/auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.authService.Logout(c.GetString(middleware.SessionKey)); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Logout failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      nil,
+		Message:   "Logged out successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// Me returns the authenticated player
+// GET /api/v1/auth/me
+func (h *AuthHandler) Me(c *gin.Context) {
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      middleware.CurrentPlayer(c),
+		Message:   "Player retrieved successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// SendVerification emails the player a new link to verify its address
+// POST /api/v1/auth/verify-email/send
+func (h *AuthHandler) SendVerification(c *gin.Context) {
+	if err := h.accountService.SendVerification(middleware.CurrentPlayer(c)); err != nil {
+		h.accountError(c, err, "Failed to send verification email")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Data:      nil,
+		Message:   "Verification email sent",
+		Timestamp: time.Now(),
+	})
+}
+
+// VerifyEmail verifies an address with the token of a verification email
+// GET /api/v1/auth/verify-email?token=...
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: "The token parameter is required",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	player, err := h.accountService.VerifyEmail(token)
+	if err != nil {
+		h.accountError(c, err, "Failed to verify email")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      player,
+		Message:   "Email verified successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// ForgotPassword emails a password reset link to a verified address. It
+// answers the same whether or not the address belongs to a player.
+// POST /api/v1/auth/forgot-password
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.accountService.RequestPasswordReset(req.Email); err != nil {
+		log.Printf("Error sending password reset email: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Reset failed",
+			Message: "Failed to send the password reset email",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, SuccessResponse{
+		Data:      nil,
+		Message:   "If the address is verified, a password reset email is on its way",
+		Timestamp: time.Now(),
+	})
+}
+
+// ResetPassword sets a new password with the token of a reset email and ends
+// every session of the player
+// POST /api/v1/auth/reset-password
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.accountService.ResetPassword(req.Token, req.Password); err != nil {
+		h.accountError(c, err, "Failed to reset password")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      nil,
+		Message:   "Password reset successfully; log in with the new password",
+		Timestamp: time.Now(),
+	})
+}
+
+// tokenError answers a failed login or refresh
+func (h *AuthHandler) tokenError(c *gin.Context, err error, failure string) {
+	if errors.Is(err, services.ErrInvalidCredentials) || errors.Is(err, services.ErrInvalidToken) {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: err.Error(),
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   failure,
+		Message: err.Error(),
+		Code:    http.StatusInternalServerError,
+	})
+}
+
+// accountError answers a failed email verification or password reset
+func (h *AuthHandler) accountError(c *gin.Context, err error, failure string) {
+	var rateLimited *services.EmailRateLimitError
+	switch {
+	case errors.As(err, &rateLimited):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(rateLimited.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error:   "Too many emails",
+			Message: err.Error(),
+			Code:    http.StatusTooManyRequests,
+		})
+	case errors.Is(err, services.ErrInvalidToken):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid token",
+			Message: "The link is invalid, expired or already used",
+			Code:    http.StatusBadRequest,
+		})
+	case errors.Is(err, services.ErrEmailAlreadyVerified):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Already verified",
+			Message: err.Error(),
+			Code:    http.StatusConflict,
+		})
+	case errors.Is(err, services.ErrNoEmail), errors.Is(err, services.ErrInvalidEmail), errors.Is(err, services.ErrInvalidPassword):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	default:
+		log.Printf("Error in account flow: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   failure,
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+	}
+}
+
+// authorizePlayer reports whether the authenticated player is playerID, and
+// answers 403 otherwise; for player IDs that come from the body or a record
+// rather than the path
+func authorizePlayer(c *gin.Context, playerID uint) bool {
+	if player := middleware.CurrentPlayer(c); player == nil || player.ID != playerID {
+		middleware.AbortForbiddenPlayer(c)
+		return false
+	}
+	return true
+}
+
+func RegisterAuthRoutes(router *gin.Engine, authService *services.AuthService, accountService *services.AccountService, playerAuth gin.HandlerFunc) {
+	handler := NewAuthHandler(authService, accountService)
+
+	auth := router.Group("/api/v1/auth")
+	{
+		auth.POST("/register", handler.Register)
+		auth.POST("/login", handler.Login)
+		auth.POST("/refresh", handler.Refresh)
+		auth.POST("/logout", playerAuth, handler.Logout)
+		auth.GET("/me", playerAuth, handler.Me)
+
+		// Email verification and password recovery
+		auth.POST("/verify-email/send", playerAuth, handler.SendVerification)
+		auth.GET("/verify-email", handler.VerifyEmail)
+		auth.POST("/forgot-password", handler.ForgotPassword)
+		auth.POST("/reset-password", handler.ResetPassword)
+	}
+
+	// The original player creation route now registers with a password
+	router.POST("/api/v1/game/player/create", handler.Register)
+}
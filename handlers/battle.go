@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"starwars-api/middleware"
 	"starwars-api/services"
 	"strconv"
 
@@ -30,6 +32,9 @@ func (h *BattleHandler) CreateBattle(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !h.authorizeFleet(c, request.PlayerFleetID) {
+		return
+	}
 
 	battle, err := h.battleService.CreateBattle(
 		request.BattleType,
@@ -92,6 +97,17 @@ func (h *BattleHandler) ExecuteAction(c *gin.Context) {
 		return
 	}
 
+	// Players may only act for their own side
+	owned, err := h.battleService.ParticipantOwnedBy(uint(battleID), request.ParticipantID, middleware.CurrentPlayer(c).ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !owned {
+		middleware.AbortForbiddenPlayer(c)
+		return
+	}
+
 	action, err := h.battleService.ExecuteAction(
 		uint(battleID),
 		request.ParticipantID,
@@ -99,6 +115,10 @@ func (h *BattleHandler) ExecuteAction(c *gin.Context) {
 		request.TargetShipID,
 		request.ActionType,
 	)
+	if errors.Is(err, services.ErrNotParticipantShip) {
+		middleware.AbortForbiddenPlayer(c)
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -167,6 +187,12 @@ func (h *BattleHandler) EndBattle(c *gin.Context) {
 		return
 	}
 
+	// Ending a battle by hand concedes it, so players cannot name themselves the winner
+	if request.WinnerID != nil && *request.WinnerID == middleware.CurrentPlayer(c).ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Players cannot declare themselves the winner"})
+		return
+	}
+
 	err = h.battleService.EndBattle(uint(battleID), request.WinnerID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -239,6 +265,9 @@ func (h *BattleHandler) CreateQuickBattle(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !h.authorizeFleet(c, request.PlayerFleetID) {
+		return
+	}
 
 	// Set defaults
 	if request.Difficulty == 0 {
@@ -310,8 +339,44 @@ func (h *BattleHandler) GetBattleStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
+// authorizeFleet reports whether the authenticated player owns the fleet, and
+// answers 403 otherwise
+func (h *BattleHandler) authorizeFleet(c *gin.Context, fleetID uint) bool {
+	owned, err := h.battleService.FleetOwnedBy(fleetID, middleware.CurrentPlayer(c).ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return false
+	}
+	if !owned {
+		middleware.AbortForbiddenPlayer(c)
+		return false
+	}
+	return true
+}
+
+// requireParticipant only lets players fighting in a battle through to its
+// routes
+func (h *BattleHandler) requireParticipant(c *gin.Context) {
+	battleID, err := strconv.ParseUint(c.Param("battleId"), 10, 32)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid battle ID"})
+		return
+	}
+
+	participant, err := h.battleService.IsParticipant(uint(battleID), middleware.CurrentPlayer(c).ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !participant {
+		middleware.AbortForbiddenPlayer(c)
+		return
+	}
+	c.Next()
+}
+
 // RegisterBattleRoutes registers all battle-related routes
-func RegisterBattleRoutes(router *gin.Engine, battleService *services.BattleService) {
+func RegisterBattleRoutes(router *gin.Engine, battleService *services.BattleService, playerAuth gin.HandlerFunc) {
 	handler := NewBattleHandler(battleService)
 
 	v1 := router.Group("/api/v1")
@@ -319,19 +384,20 @@ func RegisterBattleRoutes(router *gin.Engine, battleService *services.BattleServ
 		battle := v1.Group("/battle")
 		{
 			// Battle management
-			battle.POST("/create", handler.CreateBattle)
-			battle.POST("/quick", handler.CreateQuickBattle)
+			battle.POST("/create", playerAuth, handler.CreateBattle)
+			battle.POST("/quick", playerAuth, handler.CreateQuickBattle)
 			battle.GET("/templates", handler.GetBattleTemplates)
 
-			// Battle operations
-			battle.GET("/:battleId", handler.GetBattle)
-			battle.GET("/:battleId/status", handler.GetBattleStatus)
-			battle.POST("/:battleId/start", handler.StartBattle)
-			battle.POST("/:battleId/action", handler.ExecuteAction)
-			battle.POST("/:battleId/end", handler.EndBattle)
+			// Battle operations, for the battle's participants
+			operations := battle.Group("/:battleId", playerAuth, handler.requireParticipant)
+			operations.GET("", handler.GetBattle)
+			operations.GET("/status", handler.GetBattleStatus)
+			operations.POST("/start", handler.StartBattle)
+			operations.POST("/action", handler.ExecuteAction)
+			operations.POST("/end", handler.EndBattle)
 
 			// Player battles
-			battle.GET("/player/:playerId", handler.GetPlayerBattles)
+			battle.GET("/player/:playerId", playerAuth, middleware.PlayerOwnsParam("playerId"), handler.GetPlayerBattles)
 		}
 	}
 }
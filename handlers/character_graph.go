@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"starwars-api/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CharacterGraphHandler struct {
+	graphService *services.CharacterGraphService
+}
+
+func NewCharacterGraphHandler(graphService *services.CharacterGraphService) *CharacterGraphHandler {
+	return &CharacterGraphHandler{graphService: graphService}
+}
+
+// GetPath returns the shortest chain of shared films, starships, vehicles,
+// organizations, weapons and events between two characters
+// GET /api/v1/graph/path?from=1&to=21
+func (h *CharacterGraphHandler) GetPath(c *gin.Context) {
+	from, fromErr := strconv.ParseUint(c.Query("from"), 10, 32)
+	to, toErr := strconv.ParseUint(c.Query("to"), 10, 32)
+	if fromErr != nil || toErr != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid path parameters",
+			Message: "from and to must be character IDs",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	path, err := h.graphService.Path(uint(from), uint(to))
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Character not found",
+				Message: "No character exists with the given from or to ID",
+				Code:    http.StatusNotFound,
+			})
+		case errors.Is(err, services.ErrNoConnection):
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "No connection",
+				Message: "The characters share no chain of relations",
+				Code:    http.StatusNotFound,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Database error",
+				Message: "Failed to build character graph",
+				Code:    http.StatusInternalServerError,
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      path,
+		Message:   "Path retrieved successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// GetConnections returns the characters sharing the most with a character
+// GET /api/v1/people/:id/connections?limit=20
+func (h *CharacterGraphHandler) GetConnections(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid character ID",
+			Message: "Character ID must be a positive integer",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	connections, err := h.graphService.Connections(uint(id), limit)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Character not found",
+				Message: "No character exists with this ID",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to build character graph",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      connections,
+		Message:   "Connections retrieved successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+func RegisterCharacterGraphRoutes(router *gin.Engine, graphService *services.CharacterGraphService) {
+	handler := NewCharacterGraphHandler(graphService)
+
+	v1 := router.Group("/api/v1")
+	{
+		v1.GET("/graph/path", handler.GetPath)
+		v1.GET("/people/:id/connections", handler.GetConnections)
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"starwars-api/config"
+	"starwars-api/middleware"
+	"starwars-api/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConfigHandler struct {
+	config *config.Config
+}
+
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+	return &ConfigHandler{config: cfg}
+}
+
+// GetConfig returns the configuration the server runs with, secrets redacted
+// GET /api/v1/admin/config
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      h.config.Redacted(),
+		Message:   "Configuration retrieved successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+func RegisterConfigRoutes(router *gin.Engine, cfg *config.Config, roles *middleware.RoleAuth) {
+	handler := NewConfigHandler(cfg)
+
+	router.GET("/api/v1/admin/config", roles.Require(models.RoleAdmin), handler.GetConfig)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"starwars-api/database"
+	"starwars-api/models"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseInclude reads ?include=enhanced, which adds the Bright Data blocks to
+// planet and starship responses. It writes a 400 response and returns false
+// for anything else.
+func parseInclude(c *gin.Context) (bool, bool) {
+	enhanced := false
+	for _, value := range strings.Split(c.Query("include"), ",") {
+		switch strings.TrimSpace(value) {
+		case "":
+		case "enhanced":
+			enhanced = true
+		default:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid include parameter",
+				Message: fmt.Sprintf("Cannot include %q; supported: enhanced", value),
+				Code:    http.StatusBadRequest,
+			})
+			return false, false
+		}
+	}
+	return enhanced, true
+}
+
+// GetPlanetDetails returns a planet with its specs, 3D environment and gameplay data
+func GetPlanetDetails(c *gin.Context) {
+	id := c.Param("id")
+
+	expand, ok := parseExpand(c, "planets")
+	if !ok {
+		return
+	}
+
+	var planet models.Planet
+	result := expand.Preload(database.DB).Where("id = ?", id).First(&planet)
+
+	if result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Planet not found"})
+		return
+	}
+
+	response, ok := expand.RenderOne(c, planet)
+	if !ok {
+		return
+	}
+	addPlanetEnhancements(response, planet)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetStarshipDetails returns a starship with its technical specs, physics and
+// 3D configuration, gameplay stats and weapon systems
+func GetStarshipDetails(c *gin.Context) {
+	id := c.Param("id")
+
+	expand, ok := parseExpand(c, "starships")
+	if !ok {
+		return
+	}
+
+	var starship models.Starship
+	result := expand.Preload(database.DB).Preload("WeaponSystems").Where("id = ?", id).First(&starship)
+
+	if result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Starship not found"})
+		return
+	}
+
+	response, ok := expand.RenderOne(c, starship)
+	if !ok {
+		return
+	}
+	addStarshipEnhancements(response, starship)
+	c.JSON(http.StatusOK, response)
+}
+
+// addPlanetEnhancements adds the Bright Data blocks of a planet to its response
+func addPlanetEnhancements(response map[string]interface{}, planet models.Planet) {
+	response["planet_specs"] = enhancedBlock(planet.PlanetSpecs)
+	response["environment_3d"] = enhancedBlock(planet.Environment3D)
+	response["gameplay_data"] = enhancedBlock(planet.GameplayData)
+}
+
+// addStarshipEnhancements adds the Bright Data blocks of a starship, whose
+// weapon systems must be preloaded, to its response
+func addStarshipEnhancements(response map[string]interface{}, starship models.Starship) {
+	response["technical_specs"] = enhancedBlock(starship.TechnicalSpecs)
+	response["physics_config"] = enhancedBlock(starship.PhysicsConfig)
+	response["model_3d_config"] = enhancedBlock(starship.Model3DConfig)
+	response["gameplay_stats"] = enhancedBlock(starship.GameplayStats)
+
+	weaponSystems := starship.WeaponSystems
+	if weaponSystems == nil {
+		weaponSystems = []models.WeaponSystem{}
+	}
+	response["weapon_systems"] = weaponSystems
+}
+
+// enhancedBlock returns an embedded enhancement block, or nil if the entity
+// has no enhanced data
+func enhancedBlock(block interface{}) interface{} {
+	value := reflect.ValueOf(block)
+	if value.IsNil() || value.Elem().IsZero() {
+		return nil
+	}
+	return block
+}
@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"starwars-api/database"
+	"starwars-api/middleware"
+	"starwars-api/models"
+	"starwars-api/services"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// MaxExpandDepth limits how many levels of relations ?expand= may inline,
+// e.g. species.homeworld is two levels deep
+var MaxExpandDepth = 3
+
+// expandRelation describes a response field that ?expand= can replace with the
+// related objects
+type expandRelation struct {
+	Entity      string // entity type of the related objects
+	Association string // GORM association holding the related objects, if any
+	URLField    string // otherwise the model field holding the related object's URL
+}
+
+// expandEntity describes how a catalog entity type is loaded and rendered
+type expandEntity struct {
+	newRows   func() interface{} // pointer to an empty model slice
+	transform func(row interface{}) map[string]interface{}
+	preloads  []string // associations its SWAPI representation lists as URLs
+	relations map[string]expandRelation
+}
+
+// expandEntities holds every catalog entity type by its API name
+var expandEntities = map[string]expandEntity{
+	"people": {
+		newRows: func() interface{} { return &[]models.Character{} },
+		transform: func(row interface{}) map[string]interface{} {
+			return transformCharacterResponse(row.(models.Character))
+		},
+		preloads: []string{"Films", "Species", "Starships", "Vehicles"},
+		relations: map[string]expandRelation{
+			"films":     {Entity: "films", Association: "Films"},
+			"species":   {Entity: "species", Association: "Species"},
+			"starships": {Entity: "starships", Association: "Starships"},
+			"vehicles":  {Entity: "vehicles", Association: "Vehicles"},
+			"homeworld": {Entity: "planets", URLField: "Homeworld"},
+		},
+	},
+	"films": {
+		newRows:   func() interface{} { return &[]models.Film{} },
+		transform: func(row interface{}) map[string]interface{} { return transformFilmResponse(row.(models.Film)) },
+		preloads:  []string{"Characters"},
+		relations: map[string]expandRelation{
+			"characters": {Entity: "people", Association: "Characters"},
+		},
+	},
+	"species": {
+		newRows:   func() interface{} { return &[]models.Species{} },
+		transform: func(row interface{}) map[string]interface{} { return transformSpeciesResponse(row.(models.Species)) },
+		preloads:  []string{"Characters"},
+		relations: map[string]expandRelation{
+			"people":    {Entity: "people", Association: "Characters"},
+			"homeworld": {Entity: "planets", URLField: "HomeworldURL"},
+		},
+	},
+	"starships": {
+		newRows:   func() interface{} { return &[]models.Starship{} },
+		transform: func(row interface{}) map[string]interface{} { return transformStarshipResponse(row.(models.Starship)) },
+		preloads:  []string{"Pilots"},
+		relations: map[string]expandRelation{
+			"pilots": {Entity: "people", Association: "Pilots"},
+		},
+	},
+	"vehicles": {
+		newRows:   func() interface{} { return &[]models.Vehicle{} },
+		transform: func(row interface{}) map[string]interface{} { return transformVehicleResponse(row.(models.Vehicle)) },
+		preloads:  []string{"Pilots", "Films"},
+		relations: map[string]expandRelation{
+			"pilots": {Entity: "people", Association: "Pilots"},
+			"films":  {Entity: "films", Association: "Films"},
+		},
+	},
+	"planets": {
+		newRows:   func() interface{} { return &[]models.Planet{} },
+		transform: func(row interface{}) map[string]interface{} { return transformPlanetResponse(row.(models.Planet)) },
+		preloads:  []string{"Residents", "Films"},
+		relations: map[string]expandRelation{
+			"residents": {Entity: "people", Association: "Residents"},
+			"films":     {Entity: "films", Association: "Films"},
+		},
+	},
+	"organizations": {
+		newRows: func() interface{} { return &[]models.Organization{} },
+		transform: func(row interface{}) map[string]interface{} {
+			return transformOrganizationResponse(row.(models.Organization))
+		},
+		preloads: []string{"Members"},
+		relations: map[string]expandRelation{
+			"members":   {Entity: "people", Association: "Members"},
+			"homeworld": {Entity: "planets", URLField: "Homeworld"},
+		},
+	},
+	"weapons": {
+		newRows:   func() interface{} { return &[]models.Weapon{} },
+		transform: func(row interface{}) map[string]interface{} { return transformWeaponResponse(row.(models.Weapon)) },
+		preloads:  []string{"Owners"},
+		relations: map[string]expandRelation{
+			"owners": {Entity: "people", Association: "Owners"},
+		},
+	},
+	"events": {
+		newRows:   func() interface{} { return &[]models.Event{} },
+		transform: func(row interface{}) map[string]interface{} { return transformEventResponse(row.(models.Event)) },
+		preloads:  []string{"Participants", "Films"},
+		relations: map[string]expandRelation{
+			"participants": {Entity: "people", Association: "Participants"},
+			"films":        {Entity: "films", Association: "Films"},
+		},
+	},
+}
+
+// expandTree is the set of relations to expand, each with its nested expansions
+type expandTree map[string]expandTree
+
+// expansion loads and renders catalog entities of one type, inlining the
+// relations requested with ?expand= and translating them into the negotiated
+// language
+type expansion struct {
+	entity   string
+	tree     expandTree
+	language string
+}
+
+// parseExpand reads the comma-separated, dot-nested relation paths of ?expand=
+// (e.g. films,species.homeworld). It writes a 400 response and returns false if
+// a path names an unknown relation or is nested deeper than MaxExpandDepth.
+func parseExpand(c *gin.Context, entity string) (*expansion, bool) {
+	e := &expansion{entity: entity, tree: expandTree{}, language: c.GetString(middleware.LanguageKey)}
+
+	for _, path := range strings.Split(c.Query("expand"), ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		keys := strings.Split(path, ".")
+		if len(keys) > MaxExpandDepth {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid expand parameter",
+				Message: fmt.Sprintf("Cannot expand %q: relations nest at most %d levels deep", path, MaxExpandDepth),
+				Code:    http.StatusBadRequest,
+			})
+			return nil, false
+		}
+
+		current, node := entity, e.tree
+		for _, key := range keys {
+			relation, ok := expandEntities[current].relations[key]
+			if !ok {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "Invalid expand parameter",
+					Message: fmt.Sprintf("Cannot expand %q: %s have no %q relation", path, current, key),
+					Code:    http.StatusBadRequest,
+				})
+				return nil, false
+			}
+
+			if node[key] == nil {
+				node[key] = expandTree{}
+			}
+			current, node = relation.Entity, node[key]
+		}
+	}
+
+	return e, true
+}
+
+// Preload adds the associations needed to render the entities, expanded ones included
+func (e *expansion) Preload(db *gorm.DB) *gorm.DB {
+	for _, path := range preloadPaths(e.entity, e.tree, "") {
+		db = db.Preload(path)
+	}
+	return db
+}
+
+// Render transforms a slice of loaded models into their API representation.
+// It writes a 500 response and returns false if an expansion fails to load.
+func (e *expansion) Render(c *gin.Context, rows interface{}) ([]map[string]interface{}, bool) {
+	slice := reflect.ValueOf(rows)
+	values := make([]reflect.Value, slice.Len())
+	for i := range values {
+		values[i] = slice.Index(i)
+	}
+
+	rendered, err := renderExpanded(e.entity, values, e.tree, e.language)
+	if err != nil {
+		log.Printf("Error expanding %s relations: %v", e.entity, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to load expanded relations",
+			Code:    http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	return rendered, true
+}
+
+// RenderOne transforms a single loaded model, as Render does
+func (e *expansion) RenderOne(c *gin.Context, row interface{}) (map[string]interface{}, bool) {
+	rendered, ok := e.Render(c, []interface{}{row})
+	if !ok {
+		return nil, false
+	}
+	return rendered[0], true
+}
+
+// preloadPaths lists the associations, prefixed with the path of the parent
+// association, that rendering entities of the given type with the tree needs
+func preloadPaths(entity string, tree expandTree, prefix string) []string {
+	paths := make([]string, 0, len(expandEntities[entity].preloads))
+	for _, preload := range expandEntities[entity].preloads {
+		paths = append(paths, prefix+preload)
+	}
+
+	for _, key := range sortedExpandKeys(tree) {
+		relation := expandEntities[entity].relations[key]
+		if relation.Association != "" {
+			paths = append(paths, preloadPaths(relation.Entity, tree[key], prefix+relation.Association+".")...)
+		}
+	}
+	return paths
+}
+
+// renderExpanded transforms and translates the rows and replaces each expanded
+// relation with the rendered related objects. Relations held as associations
+// are already preloaded; relations held as URLs are loaded with one query per
+// relation.
+func renderExpanded(entity string, rows []reflect.Value, tree expandTree, language string) ([]map[string]interface{}, error) {
+	spec := expandEntities[entity]
+
+	rendered := make([]map[string]interface{}, len(rows))
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		// Rows arrive as model structs, pointers to them or interfaces holding them
+		for row.Kind() == reflect.Interface || row.Kind() == reflect.Pointer {
+			row = row.Elem()
+		}
+		rows[i] = row
+		rendered[i] = spec.transform(row.Interface())
+		ids[i] = uint(row.FieldByName("ID").Uint())
+	}
+
+	// Untranslated fields keep their English text
+	translations, err := services.NewTranslationService(database.DB).Lookup(entity, language, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range ids {
+		for field, text := range translations[id] {
+			rendered[i][field] = text
+		}
+	}
+
+	for _, key := range sortedExpandKeys(tree) {
+		relation := spec.relations[key]
+
+		if relation.Association != "" {
+			var related []reflect.Value
+			counts := make([]int, len(rows))
+			for i, row := range rows {
+				field := row.FieldByName(relation.Association)
+				for j := 0; j < field.Len(); j++ {
+					related = append(related, field.Index(j))
+				}
+				counts[i] = field.Len()
+			}
+
+			objects, err := renderExpanded(relation.Entity, related, tree[key], language)
+			if err != nil {
+				return nil, err
+			}
+
+			offset := 0
+			for i := range rows {
+				rendered[i][key] = objects[offset : offset+counts[i]]
+				offset += counts[i]
+			}
+			continue
+		}
+
+		urls := make([]string, 0, len(rows))
+		for _, row := range rows {
+			if url := row.FieldByName(relation.URLField).String(); url != "" {
+				urls = append(urls, url)
+			}
+		}
+
+		objects, err := loadExpandedByURL(relation.Entity, urls, tree[key], language)
+		if err != nil {
+			return nil, err
+		}
+
+		// Unknown or empty URLs expand to null
+		for i, row := range rows {
+			url := row.FieldByName(relation.URLField).String()
+			if object, ok := objects[url]; ok {
+				rendered[i][key] = object
+			} else {
+				rendered[i][key] = nil
+			}
+		}
+	}
+
+	return rendered, nil
+}
+
+// loadExpandedByURL loads and renders the entities with the given URLs, keyed by URL
+func loadExpandedByURL(entity string, urls []string, tree expandTree, language string) (map[string]map[string]interface{}, error) {
+	objects := make(map[string]map[string]interface{}, len(urls))
+	if len(urls) == 0 {
+		return objects, nil
+	}
+
+	rows := expandEntities[entity].newRows()
+	query := database.DB.Where("url IN ?", urls)
+	for _, path := range preloadPaths(entity, tree, "") {
+		query = query.Preload(path)
+	}
+	if err := query.Find(rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", entity, err)
+	}
+
+	slice := reflect.ValueOf(rows).Elem()
+	values := make([]reflect.Value, slice.Len())
+	for i := range values {
+		values[i] = slice.Index(i)
+	}
+
+	rendered, err := renderExpanded(entity, values, tree, language)
+	if err != nil {
+		return nil, err
+	}
+	for i, object := range rendered {
+		objects[values[i].FieldByName("URL").String()] = object
+	}
+	return objects, nil
+}
+
+// sortedExpandKeys returns the relations of a tree in a stable order
+func sortedExpandKeys(tree expandTree) []string {
+	keys := make([]string, 0, len(tree))
+	for key := range tree {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
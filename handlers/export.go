@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"starwars-api/database"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is the number of rows loaded and flushed at a time
+const exportBatchSize = 500
+
+// exportFormats maps export file extensions to their content types
+var exportFormats = map[string]string{
+	".csv":    "text/csv; charset=utf-8",
+	".ndjson": "application/x-ndjson",
+}
+
+// ExportCatalog streams every entity of a catalog type, in its SWAPI
+// representation with relations as URL lists, as CSV or newline-delimited JSON
+// GET /api/v1/export/people.csv
+func ExportCatalog(c *gin.Context) {
+	file := c.Param("file")
+	extension := path.Ext(file)
+	entityName := strings.TrimSuffix(file, extension)
+
+	contentType, ok := exportFormats[extension]
+	entity, known := expandEntities[entityName]
+	if !ok || !known {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Unknown export",
+			Message: "Export one of " + strings.Join(sortedEntityNames(), ", ") + " as .csv or .ndjson",
+			Code:    http.StatusNotFound,
+		})
+		return
+	}
+
+	// Relations are loaded with each batch; querying the first batch before
+	// writing anything lets database errors still produce an error response
+	rows := entity.newRows()
+	query := database.DB.Order("id")
+	for _, preload := range entity.preloads {
+		query = query.Preload(preload)
+	}
+
+	var write func(row map[string]interface{}) error
+	started := false
+	start := func() error {
+		started = true
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file))
+		c.Status(http.StatusOK)
+
+		var err error
+		write, err = newExportWriter(c.Writer, extension, entity)
+		return err
+	}
+
+	result := query.FindInBatches(rows, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		batchRows := reflect.ValueOf(rows).Elem()
+		for i := 0; i < batchRows.Len(); i++ {
+			if err := write(entity.transform(batchRows.Index(i).Interface())); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+
+	if result.Error != nil {
+		log.Printf("Error exporting %s: %v", entityName, result.Error)
+		if !started {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Database error",
+				Message: "Failed to export " + entityName,
+				Code:    http.StatusInternalServerError,
+			})
+		}
+		// A response already being streamed can only be cut short
+		c.Abort()
+		return
+	}
+
+	if !started {
+		// An empty table still exports its CSV header
+		if err := start(); err != nil {
+			log.Printf("Error exporting %s: %v", entityName, err)
+		}
+		c.Writer.WriteHeaderNow()
+	}
+}
+
+// newExportWriter starts an export, writing the CSV header row, and returns
+// the function writing each transformed entity
+func newExportWriter(w gin.ResponseWriter, extension string, entity expandEntity) (func(map[string]interface{}) error, error) {
+	if extension == ".ndjson" {
+		encoder := json.NewEncoder(w)
+		return func(row map[string]interface{}) error { return encoder.Encode(row) }, nil
+	}
+
+	// CSV columns are the sorted fields of the entity's representation
+	zero := reflect.Zero(reflect.ValueOf(entity.newRows()).Elem().Type().Elem())
+	columns := make([]string, 0)
+	for column := range entity.transform(zero.Interface()) {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	writer.Flush()
+
+	record := make([]string, len(columns))
+	return func(row map[string]interface{}) error {
+		for i, column := range columns {
+			record[i] = csvValue(row[column])
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+		writer.Flush()
+		return writer.Error()
+	}, nil
+}
+
+// csvValue flattens a field of an entity's representation into a CSV cell;
+// URL lists are separated by spaces
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, " ")
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// sortedEntityNames lists the catalog entity types by API name
+func sortedEntityNames() []string {
+	names := make([]string, 0, len(expandEntities))
+	for name := range expandEntities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
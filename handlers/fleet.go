@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"starwars-api/middleware"
 	"starwars-api/models"
 	"starwars-api/services"
 	"strconv"
@@ -268,28 +269,32 @@ func (h *FleetHandler) UpgradeHangar(c *gin.Context) {
 }
 
 // RegisterFleetRoutes registers all fleet-related routes
-func RegisterFleetRoutes(router *gin.Engine, fleetService *services.FleetService) {
+func RegisterFleetRoutes(router *gin.Engine, fleetService *services.FleetService, playerAuth gin.HandlerFunc) {
 	handler := NewFleetHandler(fleetService)
 
 	v1 := router.Group("/api/v1")
 	{
 		fleet := v1.Group("/fleet")
 		{
+			fleet.GET("/ships/available", handler.GetAvailableShips)
+
+			// A player's own fleet
+			player := fleet.Group("/:playerId", playerAuth, middleware.PlayerOwnsParam("playerId"))
+
 			// Fleet management
-			fleet.GET("/:playerId", handler.GetPlayerFleet)
-			fleet.GET("/:playerId/ships", handler.GetPlayerShips)
-			fleet.POST("/:playerId/ships/:shipId", handler.AddShipToFleet)
-			fleet.DELETE("/:playerId/ships/:shipId", handler.RemoveShipFromFleet)
+			player.GET("", handler.GetPlayerFleet)
+			player.GET("/ships", handler.GetPlayerShips)
+			player.POST("/ships/:shipId", handler.AddShipToFleet)
+			player.DELETE("/ships/:shipId", handler.RemoveShipFromFleet)
 
 			// Ship management
-			fleet.GET("/ships/available", handler.GetAvailableShips)
-			fleet.POST("/:playerId/ships/purchase", handler.PurchaseShip)
-			fleet.POST("/:playerId/ships/:shipId/upgrade", handler.UpgradeShip)
-			fleet.POST("/:playerId/ships/:shipId/repair", handler.RepairShip)
+			player.POST("/ships/purchase", handler.PurchaseShip)
+			player.POST("/ships/:shipId/upgrade", handler.UpgradeShip)
+			player.POST("/ships/:shipId/repair", handler.RepairShip)
 
 			// Hangar management
-			fleet.GET("/:playerId/hangar", handler.GetPlayerHangar)
-			fleet.POST("/:playerId/hangar/upgrade", handler.UpgradeHangar)
+			player.GET("/hangar", handler.GetPlayerHangar)
+			player.POST("/hangar/upgrade", handler.UpgradeHangar)
 		}
 	}
 }
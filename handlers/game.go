@@ -1,62 +1,30 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"starwars-api/config"
 	"starwars-api/database"
+	"starwars-api/middleware"
 	"starwars-api/models"
+	"starwars-api/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-// === PLAYER HANDLERS ===
-
-// CreatePlayer створює нового гравця
-func CreatePlayer(c *gin.Context) {
-	var req struct {
-		Username string `json:"username" binding:"required"`
-		Email    string `json:"email"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Перевіряємо, чи існує гравець з таким username
-	var existingPlayer models.Player
-	if err := database.DB.Where("username = ?", req.Username).First(&existingPlayer).Error; err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
-		return
-	}
+// GameBalance містить ігровий баланс: стартові кредити, криву рівнів і бонуси вікторини.
+// Задається з конфігурації під час запуску.
+var GameBalance = config.Default().Game
 
-	player := models.Player{
-		Username:   req.Username,
-		Email:      req.Email,
-		Level:      1,
-		Experience: 0,
-		Credits:    100,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-	}
-
-	if err := database.DB.Create(&player).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create player"})
-		return
-	}
-
-	// Створюємо початкову статистику для гравця
-	stats := models.PlayerStats{
-		PlayerID: player.ID,
-	}
-	database.DB.Create(&stats)
+// Progression змінює рівень, досвід і валюти гравців. Задається під час запуску.
+var Progression *services.ProgressionService
 
-	c.JSON(http.StatusCreated, player)
-}
+// === PLAYER HANDLERS ===
 
 // GetPlayerProfile отримує профіль гравця
 func GetPlayerProfile(c *gin.Context) {
@@ -89,7 +57,7 @@ func UpdatePlayerProfile(c *gin.Context) {
 
 	var req struct {
 		Username string `json:"username"`
-		Email    string `json:"email"`
+		Email    string `json:"email" binding:"omitempty,email"`
 		Avatar   string `json:"avatar"`
 	}
 
@@ -109,11 +77,23 @@ func UpdatePlayerProfile(c *gin.Context) {
 	}
 
 	// Оновлюємо поля
-	if req.Username != "" {
+	if req.Username != "" && req.Username != player.Username {
+		// Ім'я має бути унікальним, як і під час реєстрації
+		var count int64
+		if err := database.DB.Model(&models.Player{}).Where("username = ?", req.Username).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": services.ErrUsernameTaken.Error()})
+			return
+		}
 		player.Username = req.Username
 	}
-	if req.Email != "" {
+	if req.Email != "" && req.Email != player.Email {
+		// Нову адресу потрібно підтвердити знову
 		player.Email = req.Email
+		player.EmailVerifiedAt = nil
 	}
 	if req.Avatar != "" {
 		player.Avatar = req.Avatar
@@ -191,7 +171,7 @@ func AddExperience(c *gin.Context) {
 	}
 
 	var req struct {
-		Experience int `json:"experience" binding:"required"`
+		Experience int `json:"experience" binding:"required,min=1"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -199,73 +179,118 @@ func AddExperience(c *gin.Context) {
 		return
 	}
 
-	var player models.Player
-	if err := database.DB.First(&player, playerID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+	// Рівень і бонус за нього рахує ProgressionService
+	grantToPlayer(c, uint(playerID), services.Change{Experience: req.Experience})
+}
+
+// AddCredits додає кредити гравцю
+func AddCredits(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
 		return
 	}
 
-	// Додаємо досвід
-	player.Experience += req.Experience
+	var req struct {
+		Credits int `json:"credits" binding:"required"`
+	}
 
-	// Обчислюємо новий рівень
-	newLevel := calculateLevel(player.Experience)
-	if newLevel > player.Level {
-		player.Level = newLevel
-		// Можна додати бонуси за підвищення рівня
-		player.Credits += (newLevel - player.Level) * 50
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	player.UpdatedAt = time.Now()
+	grantToPlayer(c, uint(playerID), services.Change{Credits: req.Credits})
+}
 
-	if err := database.DB.Save(&player).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update player"})
+// grantToPlayer змінює баланси гравця від імені модератора чи адміністратора
+// і відповідає оновленим профілем
+func grantToPlayer(c *gin.Context, playerID uint, grant services.Change) {
+	grant.Source = models.SourceAdmin
+	if principal := middleware.CurrentPrincipal(c); principal != nil {
+		grant.Description = "Granted by " + principal.Name
+	}
+
+	if _, err := Progression.Apply(database.DB, playerID, grant); err != nil {
+		progressionError(c, err)
+		return
+	}
+
+	var player models.Player
+	if err := database.DB.First(&player, playerID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
 	}
 
 	c.JSON(http.StatusOK, player)
 }
 
-// AddCredits додає кредити гравцю
-func AddCredits(c *gin.Context) {
+// GetPlayerLedger повертає журнал змін досвіду й валют гравця, від найновіших
+// GET /api/v1/game/player/:id/ledger?currency=credits&page=1&limit=10
+func GetPlayerLedger(c *gin.Context) {
 	playerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
 		return
 	}
 
-	var req struct {
-		Credits int `json:"credits" binding:"required"`
+	currency := c.Query("currency")
+	switch currency {
+	case "", models.CurrencyExperience, models.CurrencyCredits, models.CurrencyCrystals:
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid currency parameter",
+			Message: "Currency must be experience, credits or crystals",
+			Code:    http.StatusBadRequest,
+		})
+		return
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	params, ok := parseListQuery(c, listSpec{})
+	if !ok {
 		return
 	}
 
-	var player models.Player
-	if err := database.DB.First(&player, playerID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+	entries, total, err := Progression.ListLedger(uint(playerID), currency, params.Limit, (params.Page-1)*params.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ledger"})
 		return
 	}
 
-	player.Credits += req.Credits
-	player.UpdatedAt = time.Now()
+	params.Respond(c, total, entries)
+}
 
-	if err := database.DB.Save(&player).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update player"})
+// GetPlayerLevelUps повертає рівні, яких досяг гравець, і отримані за них нагороди
+// GET /api/v1/game/player/:id/level-ups?page=1&limit=10
+func GetPlayerLevelUps(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
 		return
 	}
 
-	c.JSON(http.StatusOK, player)
+	params, ok := parseListQuery(c, listSpec{})
+	if !ok {
+		return
+	}
+
+	levelUps, total, err := Progression.ListLevelUps(uint(playerID), params.Limit, (params.Page-1)*params.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch level-ups"})
+		return
+	}
+
+	params.Respond(c, total, levelUps)
+}
+
+// GetLevels повертає криву рівнів: потрібний досвід і нагороди кожного рівня
+// GET /api/v1/game/levels
+func GetLevels(c *gin.Context) {
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      Progression.Levels(),
+		Message:   "Levels retrieved successfully",
+		Timestamp: time.Now(),
+	})
 }
 
 // === GAME SESSION HANDLERS ===
@@ -273,7 +298,7 @@ func AddCredits(c *gin.Context) {
 // CreateGameSession створює нову ігрову сесію
 func CreateGameSession(c *gin.Context) {
 	var req struct {
-		PlayerID uint   `json:"player_id" binding:"required"`
+		PlayerID uint   `json:"player_id"`
 		GameType string `json:"game_type" binding:"required"`
 		Data     string `json:"data"`
 	}
@@ -283,9 +308,15 @@ func CreateGameSession(c *gin.Context) {
 		return
 	}
 
+	// Сесія належить автентифікованому гравцю; player_id необов'язковий
+	player := middleware.CurrentPlayer(c)
+	if req.PlayerID != 0 && !authorizePlayer(c, req.PlayerID) {
+		return
+	}
+
 	session := models.GameSession{
 		ID:        uuid.New().String(),
-		PlayerID:  req.PlayerID,
+		PlayerID:  player.ID,
 		GameType:  req.GameType,
 		Data:      req.Data,
 		Score:     0,
@@ -323,6 +354,9 @@ func CompleteGameSession(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
 	}
+	if !authorizePlayer(c, session.PlayerID) {
+		return
+	}
 
 	now := time.Now()
 	session.Score = req.Score
@@ -339,12 +373,15 @@ func CompleteGameSession(c *gin.Context) {
 
 // === UTILITY FUNCTIONS ===
 
-// calculateLevel обчислює рівень на основі досвіду
-func calculateLevel(experience int) int {
-	// Формула: level = floor(sqrt(experience / 100)) + 1
-	level := 1
-	for level*level*100 <= experience {
-		level++
+// progressionError відповідає на невдалу зміну балансів гравця
+func progressionError(c *gin.Context, err error) {
+	var insufficient *services.InsufficientFundsError
+	switch {
+	case errors.Is(err, services.ErrPlayerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+	case errors.As(err, &insufficient), errors.Is(err, services.ErrNegativeExperience):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update player"})
 	}
-	return level
 }
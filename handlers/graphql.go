@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"starwars-api/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLHandler struct {
+	schema *graph.Schema
+}
+
+func NewGraphQLHandler(schema *graph.Schema) *GraphQLHandler {
+	return &GraphQLHandler{schema: schema}
+}
+
+// graphQLRequest is the standard GraphQL-over-HTTP request body
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// Query executes a GraphQL query sent as a JSON body or as query parameters
+// POST /graphql {"query": "{ character(id: 1) { name films { title } } }"}
+// GET /graphql?query={allFilms{title}}&variables={}
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graphQLRequest
+	if c.Request.Method == http.MethodPost {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request body",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	} else {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "Invalid variables parameter",
+					Message: "Variables must be a JSON object",
+					Code:    http.StatusBadRequest,
+				})
+				return
+			}
+		}
+	}
+
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing query",
+			Message: "A GraphQL query is required",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Field errors are reported in the result alongside partial data
+	c.JSON(http.StatusOK, h.schema.Execute(c.Request.Context(), req.Query, req.Variables, req.OperationName))
+}
+
+func RegisterGraphQLRoutes(router *gin.Engine, schema *graph.Schema) {
+	handler := NewGraphQLHandler(schema)
+
+	router.GET("/graphql", handler.Query)
+	router.POST("/graphql", handler.Query)
+}
@@ -1,13 +1,11 @@
 package handlers
 
 import (
-	"fmt"
 	"log"
 	"net/http"
 	"starwars-api/database"
 	"starwars-api/models"
-	"strconv"
-	"strings"
+	"starwars-api/services"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -27,46 +25,167 @@ type SuccessResponse struct {
 	Timestamp time.Time   `json:"timestamp"`
 }
 
-// GetCharacters returns a paginated list of characters with enhanced error handling
-func GetCharacters(c *gin.Context) {
-	// Validate and parse query parameters
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid page parameter",
-			Message: "Page must be a positive integer",
-			Code:    http.StatusBadRequest,
-		})
-		return
+// Search, filter and sort fields for each catalog list endpoint
+var (
+	// "created" and "edited" sort by the SWAPI timestamp fields
+	timestampSorts = map[string]string{"created": "created_at", "edited": "updated_at"}
+
+	characterListSpec = listSpec{
+		SearchColumns: []string{"name"},
+		Filters: map[string]listFilter{
+			"gender":     {Column: "gender"},
+			"birth_year": {Column: "birth_year"},
+			"homeworld":  {Column: "homeworld"},
+			"eye_color":  {Column: "eye_color", Mode: filterContains},
+			"hair_color": {Column: "hair_color", Mode: filterContains},
+		},
+		Ranges: map[string]string{"height": "height_value", "mass": "mass_value"},
+		Sorts: withTimestampSorts(map[string]string{
+			"name": "name", "birth_year": "birth_year", "gender": "gender", "height": "height_value", "mass": "mass_value",
+		}),
+	}
+
+	filmListSpec = listSpec{
+		SearchColumns: []string{"title"},
+		Filters: map[string]listFilter{
+			"episode_id": {Column: "episode_id", Mode: filterInteger},
+			"director":   {Column: "director", Mode: filterContains},
+			"producer":   {Column: "producer", Mode: filterContains},
+		},
+		Sorts: withTimestampSorts(map[string]string{
+			"title": "title", "episode_id": "episode_id", "release_date": "release_date", "director": "director",
+		}),
+	}
+
+	speciesListSpec = listSpec{
+		SearchColumns: []string{"name"},
+		Filters: map[string]listFilter{
+			"classification": {Column: "classification"},
+			"designation":    {Column: "designation"},
+			"language":       {Column: "language"},
+			"skin_colors":    {Column: "skin_colors", Mode: filterContains},
+			"hair_colors":    {Column: "hair_colors", Mode: filterContains},
+			"eye_colors":     {Column: "eye_colors", Mode: filterContains},
+		},
+		Sorts: withTimestampSorts(map[string]string{
+			"name": "name", "classification": "classification", "designation": "designation",
+			"average_height": "average_height", "average_lifespan": "average_lifespan", "language": "language",
+		}),
+	}
+
+	starshipListSpec = listSpec{
+		SearchColumns: []string{"name", "model"},
+		Filters: map[string]listFilter{
+			"starship_class": {Column: "starship_class"},
+			"manufacturer":   {Column: "manufacturer", Mode: filterContains},
+			"model":          {Column: "model", Mode: filterContains},
+			// Bright Data gameplay stats
+			"rarity":  {Column: "gameplay_rarity"},
+			"faction": {Column: "gameplay_faction"},
+		},
+		Ranges: map[string]string{
+			"cost_in_credits": "cost_in_credits_value", "length": "length_value", "cargo_capacity": "cargo_capacity_value",
+		},
+		Sorts: withTimestampSorts(map[string]string{
+			"name": "name", "model": "model", "manufacturer": "manufacturer", "starship_class": "starship_class",
+			"cost_in_credits": "cost_in_credits_value", "length": "length_value", "cargo_capacity": "cargo_capacity_value",
+			"crew": "crew", "passengers": "passengers", "hyperdrive_rating": "hyperdrive_rating", "MGLT": "mglt",
+		}),
+	}
+
+	vehicleListSpec = listSpec{
+		SearchColumns: []string{"name", "model"},
+		Filters: map[string]listFilter{
+			"vehicle_class": {Column: "vehicle_class"},
+			"manufacturer":  {Column: "manufacturer", Mode: filterContains},
+			"model":         {Column: "model", Mode: filterContains},
+		},
+		Sorts: withTimestampSorts(map[string]string{
+			"name": "name", "model": "model", "manufacturer": "manufacturer", "vehicle_class": "vehicle_class",
+			"cost_in_credits": "cost_in_credits", "length": "length", "crew": "crew", "passengers": "passengers",
+		}),
+	}
+
+	planetListSpec = listSpec{
+		SearchColumns: []string{"name"},
+		Filters: map[string]listFilter{
+			"climate": {Column: "climate", Mode: filterContains},
+			"terrain": {Column: "terrain", Mode: filterContains},
+			"gravity": {Column: "gravity", Mode: filterContains},
+			// Bright Data gameplay data
+			"faction_control":    {Column: "faction_control"},
+			"imperial_presence":  {Column: "imperial_presence"},
+			"resource_abundance": {Column: "resource_abundance"},
+		},
+		Ranges: map[string]string{"diameter": "diameter_value", "population": "population_value"},
+		Sorts: withTimestampSorts(map[string]string{
+			"name": "name", "climate": "climate", "diameter": "diameter_value", "population": "population_value",
+			"rotation_period": "rotation_period", "orbital_period": "orbital_period",
+		}),
+	}
+
+	organizationListSpec = listSpec{
+		SearchColumns: []string{"name"},
+		Filters: map[string]listFilter{
+			"type":      {Column: "type"},
+			"homeworld": {Column: "homeworld"},
+			"leader":    {Column: "leader", Mode: filterContains},
+		},
+		Sorts: withTimestampSorts(map[string]string{
+			"name": "name", "type": "type", "founded": "founded",
+		}),
+	}
+
+	weaponListSpec = listSpec{
+		SearchColumns: []string{"name"},
+		Filters: map[string]listFilter{
+			"type":         {Column: "type"},
+			"color":        {Column: "color"},
+			"manufacturer": {Column: "manufacturer", Mode: filterContains},
+			"crystal_type": {Column: "crystal_type", Mode: filterContains},
+		},
+		Sorts: withTimestampSorts(map[string]string{
+			"name": "name", "type": "type", "color": "color", "manufacturer": "manufacturer",
+		}),
+	}
+
+	eventListSpec = listSpec{
+		SearchColumns: []string{"name"},
+		Filters: map[string]listFilter{
+			"era":      {Column: "era"},
+			"type":     {Column: "type"},
+			"location": {Column: "location", Mode: filterContains},
+		},
+		Sorts: withTimestampSorts(map[string]string{
+			"name": "name", "type": "type", "era": "era", "location": "location",
+		}),
 	}
+)
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 10 // Default to 10 if invalid
+// withTimestampSorts adds the created/edited sort keys to an entity's sort fields
+func withTimestampSorts(sorts map[string]string) map[string]string {
+	for key, column := range timestampSorts {
+		sorts[key] = column
 	}
+	return sorts
+}
 
-	offset := (page - 1) * limit
-	search := strings.TrimSpace(c.Query("search"))
+// GetCharacters returns a paginated, filterable and sortable list of characters
+func GetCharacters(c *gin.Context) {
+	params, ok := parseListQuery(c, characterListSpec)
+	if !ok {
+		return
+	}
 
-	// Validate search parameter length
-	if len(search) > 100 {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid search parameter",
-			Message: "Search query too long (max 100 characters)",
-			Code:    http.StatusBadRequest,
-		})
+	expand, ok := parseExpand(c, "people")
+	if !ok {
 		return
 	}
 
 	var characters []models.Character
 	var total int64
 
-	query := database.DB.Model(&models.Character{})
-
-	// Apply search filter if provided
-	if search != "" {
-		query = query.Where("name ILIKE ?", "%"+search+"%")
-	}
+	query := params.Apply(database.DB.Model(&models.Character{}))
 
 	// Get total count with error handling
 	if err := query.Count(&total).Error; err != nil {
@@ -79,9 +198,8 @@ func GetCharacters(c *gin.Context) {
 		return
 	}
 
-	// Get paginated results with preloaded relationships
-	if err := query.Preload("Films").Preload("Species").Preload("Starships").
-		Offset(offset).Limit(limit).Find(&characters).Error; err != nil {
+	// Get the requested page with preloaded relationships
+	if err := expand.Preload(params.Paginate(query)).Find(&characters).Error; err != nil {
 		log.Printf("Error fetching characters: %v", err)
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Database error",
@@ -91,203 +209,524 @@ func GetCharacters(c *gin.Context) {
 		return
 	}
 
-	// Build pagination URLs
-	baseURL := "http://localhost:8080/api/people"
-	var next, previous *string
-
-	if int64(page*limit) < total {
-		nextURL := fmt.Sprintf("%s?page=%d&limit=%d", baseURL, page+1, limit)
-		if search != "" {
-			nextURL += "&search=" + search
-		}
-		next = &nextURL
-	}
-
-	if page > 1 {
-		prevURL := fmt.Sprintf("%s?page=%d&limit=%d", baseURL, page-1, limit)
-		if search != "" {
-			prevURL += "&search=" + search
-		}
-		previous = &prevURL
-	}
-
-	// Transform characters to include URL arrays for relationships
-	transformedCharacters := make([]map[string]interface{}, len(characters))
-	for i, char := range characters {
-		transformedCharacters[i] = transformCharacterResponse(char)
-	}
-
-	response := models.PaginatedResponse{
-		Count:    total,
-		Next:     next,
-		Previous: previous,
-		Results:  transformedCharacters,
+	// Transform characters to include URL arrays or expanded objects for relationships
+	transformedCharacters, ok := expand.Render(c, characters)
+	if !ok {
+		return
 	}
 
-	// Add response headers
-	c.Header("X-Total-Count", fmt.Sprintf("%d", total))
-	c.Header("X-Page", fmt.Sprintf("%d", page))
-	c.Header("X-Limit", fmt.Sprintf("%d", limit))
-
-	c.JSON(http.StatusOK, response)
+	params.Respond(c, total, transformedCharacters)
 }
 
 // GetCharacterByID returns a specific character by ID
 func GetCharacterByID(c *gin.Context) {
 	id := c.Param("id")
 
+	expand, ok := parseExpand(c, "people")
+	if !ok {
+		return
+	}
+
 	var character models.Character
-	result := database.DB.Preload("Films").Preload("Species").Preload("Starships").
-		Where("id = ?", id).First(&character)
+	result := expand.Preload(database.DB).Where("id = ?", id).First(&character)
 
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Character not found"})
 		return
 	}
 
-	response := transformCharacterResponse(character)
+	response, ok := expand.RenderOne(c, character)
+	if !ok {
+		return
+	}
 	c.JSON(http.StatusOK, response)
 }
 
-// GetFilms returns all films
+// GetFilms returns a paginated, filterable and sortable list of films
 func GetFilms(c *gin.Context) {
+	params, ok := parseListQuery(c, filmListSpec)
+	if !ok {
+		return
+	}
+
+	expand, ok := parseExpand(c, "films")
+	if !ok {
+		return
+	}
+
 	var films []models.Film
-	database.DB.Preload("Characters").Find(&films)
+	var total int64
+
+	query := params.Apply(database.DB.Model(&models.Film{}))
 
-	// Transform films to include URL arrays for relationships
-	transformedFilms := make([]map[string]interface{}, len(films))
-	for i, film := range films {
-		transformedFilms[i] = transformFilmResponse(film)
+	// Get total count with error handling
+	if err := query.Count(&total).Error; err != nil {
+		log.Printf("Error counting films: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve film count",
+			Code:    http.StatusInternalServerError,
+		})
+		return
 	}
 
-	response := models.PaginatedResponse{
-		Count:    int64(len(films)),
-		Next:     nil,
-		Previous: nil,
-		Results:  transformedFilms,
+	// Get the requested page with preloaded relationships
+	if err := expand.Preload(params.Paginate(query)).Find(&films).Error; err != nil {
+		log.Printf("Error fetching films: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve films",
+			Code:    http.StatusInternalServerError,
+		})
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	// Transform films to include URL arrays or expanded objects for relationships
+	transformedFilms, ok := expand.Render(c, films)
+	if !ok {
+		return
+	}
+
+	params.Respond(c, total, transformedFilms)
 }
 
-// GetSpecies returns all species
+// GetSpecies returns a paginated, filterable and sortable list of species
 func GetSpecies(c *gin.Context) {
+	params, ok := parseListQuery(c, speciesListSpec)
+	if !ok {
+		return
+	}
+
+	expand, ok := parseExpand(c, "species")
+	if !ok {
+		return
+	}
+
 	var species []models.Species
-	database.DB.Preload("Characters").Find(&species)
+	var total int64
+
+	query := params.Apply(database.DB.Model(&models.Species{}))
 
-	// Transform species to include URL arrays for relationships
-	transformedSpecies := make([]map[string]interface{}, len(species))
-	for i, sp := range species {
-		transformedSpecies[i] = transformSpeciesResponse(sp)
+	// Get total count with error handling
+	if err := query.Count(&total).Error; err != nil {
+		log.Printf("Error counting species: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve species count",
+			Code:    http.StatusInternalServerError,
+		})
+		return
 	}
 
-	response := models.PaginatedResponse{
-		Count:    int64(len(species)),
-		Next:     nil,
-		Previous: nil,
-		Results:  transformedSpecies,
+	// Get the requested page with preloaded relationships
+	if err := expand.Preload(params.Paginate(query)).Find(&species).Error; err != nil {
+		log.Printf("Error fetching species: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve species",
+			Code:    http.StatusInternalServerError,
+		})
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	// Transform species to include URL arrays or expanded objects for relationships
+	transformedSpecies, ok := expand.Render(c, species)
+	if !ok {
+		return
+	}
+
+	params.Respond(c, total, transformedSpecies)
 }
 
-// GetStarships returns all starships
+// GetStarships returns a paginated, filterable and sortable list of starships
 func GetStarships(c *gin.Context) {
+	params, ok := parseListQuery(c, starshipListSpec)
+	if !ok {
+		return
+	}
+
+	expand, ok := parseExpand(c, "starships")
+	if !ok {
+		return
+	}
+
+	enhanced, ok := parseInclude(c)
+	if !ok {
+		return
+	}
+
 	var starships []models.Starship
-	database.DB.Preload("Pilots").Find(&starships)
+	var total int64
+
+	query := params.Apply(database.DB.Model(&models.Starship{}))
+
+	// Get total count with error handling
+	if err := query.Count(&total).Error; err != nil {
+		log.Printf("Error counting starships: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve starship count",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
 
-	// Transform starships to include URL arrays for relationships
-	transformedStarships := make([]map[string]interface{}, len(starships))
-	for i, ship := range starships {
-		transformedStarships[i] = transformStarshipResponse(ship)
+	// Get the requested page with preloaded relationships
+	page := expand.Preload(params.Paginate(query))
+	if enhanced {
+		page = page.Preload("WeaponSystems")
+	}
+	if err := page.Find(&starships).Error; err != nil {
+		log.Printf("Error fetching starships: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve starships",
+			Code:    http.StatusInternalServerError,
+		})
+		return
 	}
 
-	response := models.PaginatedResponse{
-		Count:    int64(len(starships)),
-		Next:     nil,
-		Previous: nil,
-		Results:  transformedStarships,
+	// Transform starships to include URL arrays or expanded objects for relationships
+	transformedStarships, ok := expand.Render(c, starships)
+	if !ok {
+		return
+	}
+	if enhanced {
+		for i, ship := range starships {
+			addStarshipEnhancements(transformedStarships[i], ship)
+		}
+	}
+
+	params.Respond(c, total, transformedStarships)
+}
+
+// GetVehicles returns a paginated, filterable and sortable list of vehicles
+func GetVehicles(c *gin.Context) {
+	params, ok := parseListQuery(c, vehicleListSpec)
+	if !ok {
+		return
+	}
+
+	expand, ok := parseExpand(c, "vehicles")
+	if !ok {
+		return
 	}
 
+	var vehicles []models.Vehicle
+	var total int64
+
+	query := params.Apply(database.DB.Model(&models.Vehicle{}))
+
+	// Get total count with error handling
+	if err := query.Count(&total).Error; err != nil {
+		log.Printf("Error counting vehicles: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve vehicle count",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// Get the requested page with preloaded relationships
+	if err := expand.Preload(params.Paginate(query)).Find(&vehicles).Error; err != nil {
+		log.Printf("Error fetching vehicles: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve vehicles",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// Transform vehicles to include URL arrays or expanded objects for relationships
+	transformedVehicles, ok := expand.Render(c, vehicles)
+	if !ok {
+		return
+	}
+
+	params.Respond(c, total, transformedVehicles)
+}
+
+// GetVehicleByID returns a specific vehicle by ID
+func GetVehicleByID(c *gin.Context) {
+	id := c.Param("id")
+
+	expand, ok := parseExpand(c, "vehicles")
+	if !ok {
+		return
+	}
+
+	var vehicle models.Vehicle
+	result := expand.Preload(database.DB).Where("id = ?", id).First(&vehicle)
+
+	if result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vehicle not found"})
+		return
+	}
+
+	response, ok := expand.RenderOne(c, vehicle)
+	if !ok {
+		return
+	}
 	c.JSON(http.StatusOK, response)
 }
 
-// GetPlanets returns all planets
+// GetPlanets returns a paginated, filterable and sortable list of planets
 func GetPlanets(c *gin.Context) {
+	params, ok := parseListQuery(c, planetListSpec)
+	if !ok {
+		return
+	}
+
+	expand, ok := parseExpand(c, "planets")
+	if !ok {
+		return
+	}
+
+	enhanced, ok := parseInclude(c)
+	if !ok {
+		return
+	}
+
 	var planets []models.Planet
-	database.DB.Find(&planets)
+	var total int64
+
+	query := params.Apply(database.DB.Model(&models.Planet{}))
+
+	// Get total count with error handling
+	if err := query.Count(&total).Error; err != nil {
+		log.Printf("Error counting planets: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve planet count",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
 
-	// Transform planets to match SWAPI format
-	transformedPlanets := make([]map[string]interface{}, len(planets))
-	for i, planet := range planets {
-		transformedPlanets[i] = transformPlanetResponse(planet)
+	// Get the requested page with preloaded relationships
+	if err := expand.Preload(params.Paginate(query)).Find(&planets).Error; err != nil {
+		log.Printf("Error fetching planets: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve planets",
+			Code:    http.StatusInternalServerError,
+		})
+		return
 	}
 
-	response := models.PaginatedResponse{
-		Count:    int64(len(planets)),
-		Next:     nil,
-		Previous: nil,
-		Results:  transformedPlanets,
+	// Transform planets to include URL arrays or expanded objects for relationships
+	transformedPlanets, ok := expand.Render(c, planets)
+	if !ok {
+		return
+	}
+	if enhanced {
+		for i, planet := range planets {
+			addPlanetEnhancements(transformedPlanets[i], planet)
+		}
 	}
 
-	c.JSON(http.StatusOK, response)
+	params.Respond(c, total, transformedPlanets)
 }
 
 // GetPlanetByID returns a specific planet by ID
 func GetPlanetByID(c *gin.Context) {
 	id := c.Param("id")
 
+	expand, ok := parseExpand(c, "planets")
+	if !ok {
+		return
+	}
+
+	enhanced, ok := parseInclude(c)
+	if !ok {
+		return
+	}
+
 	var planet models.Planet
-	result := database.DB.Where("id = ?", id).First(&planet)
+	result := expand.Preload(database.DB).Where("id = ?", id).First(&planet)
 
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Planet not found"})
 		return
 	}
 
-	response := transformPlanetResponse(planet)
+	response, ok := expand.RenderOne(c, planet)
+	if !ok {
+		return
+	}
+	if enhanced {
+		addPlanetEnhancements(response, planet)
+	}
 	c.JSON(http.StatusOK, response)
 }
 
-// GetOrganizations returns all organizations
+// GetOrganizations returns a paginated, filterable and sortable list of organizations
 func GetOrganizations(c *gin.Context) {
+	params, ok := parseListQuery(c, organizationListSpec)
+	if !ok {
+		return
+	}
+
+	expand, ok := parseExpand(c, "organizations")
+	if !ok {
+		return
+	}
+
 	var organizations []models.Organization
-	database.DB.Preload("Members").Find(&organizations)
+	var total int64
+
+	query := params.Apply(database.DB.Model(&models.Organization{}))
 
-	// Transform organizations
-	transformedOrganizations := make([]map[string]interface{}, len(organizations))
-	for i, org := range organizations {
-		transformedOrganizations[i] = transformOrganizationResponse(org)
+	// Get total count with error handling
+	if err := query.Count(&total).Error; err != nil {
+		log.Printf("Error counting organizations: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve organization count",
+			Code:    http.StatusInternalServerError,
+		})
+		return
 	}
 
-	response := models.PaginatedResponse{
-		Count:    int64(len(organizations)),
-		Next:     nil,
-		Previous: nil,
-		Results:  transformedOrganizations,
+	// Get the requested page with preloaded relationships
+	if err := expand.Preload(params.Paginate(query)).Find(&organizations).Error; err != nil {
+		log.Printf("Error fetching organizations: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve organizations",
+			Code:    http.StatusInternalServerError,
+		})
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	// Transform organizations to include URL arrays or expanded objects for relationships
+	transformedOrganizations, ok := expand.Render(c, organizations)
+	if !ok {
+		return
+	}
+
+	params.Respond(c, total, transformedOrganizations)
 }
 
-// GetWeapons returns all weapons
+// GetWeapons returns a paginated, filterable and sortable list of weapons
 func GetWeapons(c *gin.Context) {
+	params, ok := parseListQuery(c, weaponListSpec)
+	if !ok {
+		return
+	}
+
+	expand, ok := parseExpand(c, "weapons")
+	if !ok {
+		return
+	}
+
 	var weapons []models.Weapon
-	database.DB.Preload("Owners").Find(&weapons)
+	var total int64
+
+	query := params.Apply(database.DB.Model(&models.Weapon{}))
+
+	// Get total count with error handling
+	if err := query.Count(&total).Error; err != nil {
+		log.Printf("Error counting weapons: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve weapon count",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// Get the requested page with preloaded relationships
+	if err := expand.Preload(params.Paginate(query)).Find(&weapons).Error; err != nil {
+		log.Printf("Error fetching weapons: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve weapons",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// Transform weapons to include URL arrays or expanded objects for relationships
+	transformedWeapons, ok := expand.Render(c, weapons)
+	if !ok {
+		return
+	}
+
+	params.Respond(c, total, transformedWeapons)
+}
+
+// GetEvents returns a paginated, filterable and sortable list of events
+func GetEvents(c *gin.Context) {
+	params, ok := parseListQuery(c, eventListSpec)
+	if !ok {
+		return
+	}
+
+	expand, ok := parseExpand(c, "events")
+	if !ok {
+		return
+	}
+
+	var events []models.Event
+	var total int64
+
+	query := params.Apply(database.DB.Model(&models.Event{}))
 
-	// Transform weapons
-	transformedWeapons := make([]map[string]interface{}, len(weapons))
-	for i, weapon := range weapons {
-		transformedWeapons[i] = transformWeaponResponse(weapon)
+	// Get total count with error handling
+	if err := query.Count(&total).Error; err != nil {
+		log.Printf("Error counting events: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve event count",
+			Code:    http.StatusInternalServerError,
+		})
+		return
 	}
 
-	response := models.PaginatedResponse{
-		Count:    int64(len(weapons)),
-		Next:     nil,
-		Previous: nil,
-		Results:  transformedWeapons,
+	// Get the requested page with preloaded relationships
+	if err := expand.Preload(params.Paginate(query)).Find(&events).Error; err != nil {
+		log.Printf("Error fetching events: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve events",
+			Code:    http.StatusInternalServerError,
+		})
+		return
 	}
 
+	// Transform events to include URL arrays or expanded objects for relationships
+	transformedEvents, ok := expand.Render(c, events)
+	if !ok {
+		return
+	}
+
+	params.Respond(c, total, transformedEvents)
+}
+
+// GetEventByID returns a specific event by ID
+func GetEventByID(c *gin.Context) {
+	id := c.Param("id")
+
+	expand, ok := parseExpand(c, "events")
+	if !ok {
+		return
+	}
+
+	var event models.Event
+	result := expand.Preload(database.DB).Where("id = ?", id).First(&event)
+
+	if result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+
+	response, ok := expand.RenderOne(c, event)
+	if !ok {
+		return
+	}
 	c.JSON(http.StatusOK, response)
 }
 
@@ -405,6 +844,37 @@ func transformStarshipResponse(starship models.Starship) map[string]interface{}
 	}
 }
 
+func transformVehicleResponse(vehicle models.Vehicle) map[string]interface{} {
+	pilotURLs := make([]string, len(vehicle.Pilots))
+	for i, pilot := range vehicle.Pilots {
+		pilotURLs[i] = pilot.URL
+	}
+
+	filmURLs := make([]string, len(vehicle.Films))
+	for i, film := range vehicle.Films {
+		filmURLs[i] = film.URL
+	}
+
+	return map[string]interface{}{
+		"url":                    vehicle.URL,
+		"name":                   vehicle.Name,
+		"model":                  vehicle.Model,
+		"manufacturer":           vehicle.Manufacturer,
+		"cost_in_credits":        vehicle.CostInCredits,
+		"length":                 vehicle.Length,
+		"max_atmosphering_speed": vehicle.MaxAtmospheringSpeed,
+		"crew":                   vehicle.Crew,
+		"passengers":             vehicle.Passengers,
+		"cargo_capacity":         vehicle.CargoCapacity,
+		"consumables":            vehicle.Consumables,
+		"vehicle_class":          vehicle.VehicleClass,
+		"pilots":                 pilotURLs,
+		"films":                  filmURLs,
+		"created":                vehicle.CreatedAt,
+		"edited":                 vehicle.UpdatedAt,
+	}
+}
+
 func transformPlanetResponse(planet models.Planet) map[string]interface{} {
 	residentURLs := make([]string, len(planet.Residents))
 	for i, resident := range planet.Residents {
@@ -477,3 +947,37 @@ func transformWeaponResponse(weapon models.Weapon) map[string]interface{} {
 		"edited":       weapon.UpdatedAt,
 	}
 }
+
+func transformEventResponse(event models.Event) map[string]interface{} {
+	participantURLs := make([]string, len(event.Participants))
+	for i, participant := range event.Participants {
+		participantURLs[i] = participant.URL
+	}
+
+	filmURLs := make([]string, len(event.Films))
+	for i, film := range event.Films {
+		filmURLs[i] = film.URL
+	}
+
+	// Sortable year relative to the Battle of Yavin, null if the date is unparseable
+	var year *float64
+	if parsed, err := services.ParseGalacticYear(event.Date); err == nil {
+		year = &parsed
+	}
+
+	return map[string]interface{}{
+		"url":          event.URL,
+		"name":         event.Name,
+		"type":         event.Type,
+		"date":         event.Date,
+		"year":         year,
+		"location":     event.Location,
+		"description":  event.Description,
+		"outcome":      event.Outcome,
+		"era":          event.Era,
+		"participants": participantURLs,
+		"films":        filmURLs,
+		"created":      event.CreatedAt,
+		"edited":       event.UpdatedAt,
+	}
+}
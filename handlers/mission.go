@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"time"
 
+	"starwars-api/middleware"
+	"starwars-api/models"
 	"starwars-api/services"
 
 	"github.com/gin-gonic/gin"
@@ -147,7 +149,7 @@ func (h *MissionHandler) StartMission(c *gin.Context) {
 	}
 
 	var requestBody struct {
-		PlayerID int `json:"player_id" binding:"required"`
+		PlayerID int `json:"player_id"`
 	}
 
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
@@ -159,6 +161,13 @@ func (h *MissionHandler) StartMission(c *gin.Context) {
 		return
 	}
 
+	// Missions are played by the authenticated player; player_id is optional
+	if requestBody.PlayerID == 0 {
+		requestBody.PlayerID = int(middleware.CurrentPlayer(c).ID)
+	} else if !authorizePlayer(c, uint(requestBody.PlayerID)) {
+		return
+	}
+
 	progress, err := h.missionService.StartMission(requestBody.PlayerID, missionID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
@@ -240,7 +249,7 @@ func (h *MissionHandler) UpdateObjectiveProgress(c *gin.Context) {
 	}
 
 	var requestBody struct {
-		PlayerID int `json:"player_id" binding:"required"`
+		PlayerID int `json:"player_id"`
 		Progress int `json:"progress" binding:"required"`
 	}
 
@@ -253,6 +262,13 @@ func (h *MissionHandler) UpdateObjectiveProgress(c *gin.Context) {
 		return
 	}
 
+	// Missions are played by the authenticated player; player_id is optional
+	if requestBody.PlayerID == 0 {
+		requestBody.PlayerID = int(middleware.CurrentPlayer(c).ID)
+	} else if !authorizePlayer(c, uint(requestBody.PlayerID)) {
+		return
+	}
+
 	err = h.missionService.UpdateObjectiveProgress(requestBody.PlayerID, missionID, objectiveID, requestBody.Progress)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
@@ -283,7 +299,7 @@ func (h *MissionHandler) CompleteMission(c *gin.Context) {
 	}
 
 	var requestBody struct {
-		PlayerID int `json:"player_id" binding:"required"`
+		PlayerID int `json:"player_id"`
 		Rating   int `json:"rating" binding:"required,min=1,max=5"`
 	}
 
@@ -296,6 +312,13 @@ func (h *MissionHandler) CompleteMission(c *gin.Context) {
 		return
 	}
 
+	// Missions are played by the authenticated player; player_id is optional
+	if requestBody.PlayerID == 0 {
+		requestBody.PlayerID = int(middleware.CurrentPlayer(c).ID)
+	} else if !authorizePlayer(c, uint(requestBody.PlayerID)) {
+		return
+	}
+
 	progress, err := h.missionService.CompleteMission(requestBody.PlayerID, missionID, requestBody.Rating)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
@@ -443,8 +466,9 @@ func (h *MissionHandler) GetLastSyncStatus(c *gin.Context) {
 }
 
 // RegisterMissionRoutes registers all mission-related routes
-func RegisterMissionRoutes(router *gin.Engine, missionService *services.MissionService) {
+func RegisterMissionRoutes(router *gin.Engine, missionService *services.MissionService, playerAuth gin.HandlerFunc, roles *middleware.RoleAuth) {
 	handler := NewMissionHandler(missionService)
+	ownsPlayer := middleware.PlayerOwnsParam("playerID")
 
 	v1 := router.Group("/api/v1")
 	{
@@ -452,20 +476,20 @@ func RegisterMissionRoutes(router *gin.Engine, missionService *services.MissionS
 		v1.GET("/missions", handler.GetAllMissions)
 		v1.GET("/missions/type/:type", handler.GetMissionsByType)
 		v1.GET("/missions/category/:category", handler.GetMissionsByCategory)
-		v1.GET("/missions/available/:playerID", handler.GetAvailableMissions)
+		v1.GET("/missions/available/:playerID", playerAuth, ownsPlayer, handler.GetAvailableMissions)
 
 		// Mission progress operations
-		v1.POST("/missions/:missionID/start", handler.StartMission)
-		v1.POST("/missions/:missionID/complete", handler.CompleteMission)
-		v1.GET("/missions/:missionID/progress/:playerID", handler.GetMissionProgress)
-		v1.PUT("/missions/:missionID/objectives/:objectiveID/progress", handler.UpdateObjectiveProgress)
+		v1.POST("/missions/:missionID/start", playerAuth, handler.StartMission)
+		v1.POST("/missions/:missionID/complete", playerAuth, handler.CompleteMission)
+		v1.GET("/missions/:missionID/progress/:playerID", playerAuth, ownsPlayer, handler.GetMissionProgress)
+		v1.PUT("/missions/:missionID/objectives/:objectiveID/progress", playerAuth, handler.UpdateObjectiveProgress)
 
 		// Mission history and statistics
-		v1.GET("/missions/history/:playerID", handler.GetPlayerMissionHistory)
+		v1.GET("/missions/history/:playerID", playerAuth, ownsPlayer, handler.GetPlayerMissionHistory)
 		v1.GET("/missions/:missionID/statistics", handler.GetMissionStatistics)
 
 		// Bright Data integration
-		v1.POST("/missions/sync/bright-data", handler.SyncWithBrightData)
+		v1.POST("/missions/sync/bright-data", roles.Require(models.RoleAdmin), handler.SyncWithBrightData)
 		v1.GET("/missions/sync/history", handler.GetSyncHistory)
 		v1.GET("/missions/sync/status", handler.GetLastSyncStatus)
 	}
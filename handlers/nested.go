@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"reflect"
+	"starwars-api/database"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// nestedParent is the parent entity a nested list belongs to
+type nestedParent struct {
+	ID  uint
+	URL string
+}
+
+// nestedList describes a catalog list nested under a parent entity, such as
+// the characters of a film. It is searched, filtered, sorted and expanded like
+// the top-level list of its entity.
+type nestedList struct {
+	ParentTable string
+	ParentName  string // for "<ParentName> not found"
+	Entity      string // expand entity of the listed rows
+	Plural      string // for error messages
+	Spec        listSpec
+	scope       func(db *gorm.DB, parent nestedParent) *gorm.DB
+}
+
+// throughJoinTable scopes a list to the rows linked to the parent by a
+// many2many join table
+func throughJoinTable(table, parentColumn, rowColumn string) func(*gorm.DB, nestedParent) *gorm.DB {
+	return func(db *gorm.DB, parent nestedParent) *gorm.DB {
+		linked := database.DB.Table(table).Select(rowColumn).Where(parentColumn+" = ?", parent.ID)
+		return db.Where("id IN (?)", linked)
+	}
+}
+
+var (
+	filmCharactersList = nestedList{
+		ParentTable: "films", ParentName: "Film", Entity: "people", Plural: "characters",
+		Spec:  characterListSpec,
+		scope: throughJoinTable("character_films", "film_id", "character_id"),
+	}
+
+	filmPlanetsList = nestedList{
+		ParentTable: "films", ParentName: "Film", Entity: "planets", Plural: "planets",
+		Spec:  planetListSpec,
+		scope: throughJoinTable("film_planets", "film_id", "planet_id"),
+	}
+
+	planetResidentsList = nestedList{
+		ParentTable: "planets", ParentName: "Planet", Entity: "people", Plural: "residents",
+		Spec: characterListSpec,
+		// Characters reference their homeworld by URL
+		scope: func(db *gorm.DB, parent nestedParent) *gorm.DB {
+			return db.Where("homeworld = ?", parent.URL)
+		},
+	}
+
+	organizationMembersList = nestedList{
+		ParentTable: "organizations", ParentName: "Organization", Entity: "people", Plural: "members",
+		Spec:  characterListSpec,
+		scope: throughJoinTable("character_organizations", "organization_id", "character_id"),
+	}
+
+	characterOrganizationsList = nestedList{
+		ParentTable: "characters", ParentName: "Character", Entity: "organizations", Plural: "organizations",
+		Spec:  organizationListSpec,
+		scope: throughJoinTable("character_organizations", "character_id", "organization_id"),
+	}
+
+	characterWeaponsList = nestedList{
+		ParentTable: "characters", ParentName: "Character", Entity: "weapons", Plural: "weapons",
+		Spec:  weaponListSpec,
+		scope: throughJoinTable("character_weapons", "character_id", "weapon_id"),
+	}
+)
+
+// GetFilmCharacters returns a paginated, filterable and sortable list of the characters of a film
+func GetFilmCharacters(c *gin.Context) {
+	listNested(c, filmCharactersList)
+}
+
+// GetFilmPlanets returns a paginated, filterable and sortable list of the planets of a film
+func GetFilmPlanets(c *gin.Context) {
+	listNested(c, filmPlanetsList)
+}
+
+// GetPlanetResidents returns a paginated, filterable and sortable list of the residents of a planet
+func GetPlanetResidents(c *gin.Context) {
+	listNested(c, planetResidentsList)
+}
+
+// GetOrganizationMembers returns a paginated, filterable and sortable list of the members of an organization
+func GetOrganizationMembers(c *gin.Context) {
+	listNested(c, organizationMembersList)
+}
+
+// GetCharacterOrganizations returns a paginated, filterable and sortable list of the organizations of a character
+func GetCharacterOrganizations(c *gin.Context) {
+	listNested(c, characterOrganizationsList)
+}
+
+// GetCharacterWeapons returns a paginated, filterable and sortable list of the weapons of a character
+func GetCharacterWeapons(c *gin.Context) {
+	listNested(c, characterWeaponsList)
+}
+
+// listNested responds with a page of the rows of a nested list
+func listNested(c *gin.Context, list nestedList) {
+	params, ok := parseListQuery(c, list.Spec)
+	if !ok {
+		return
+	}
+
+	expand, ok := parseExpand(c, list.Entity)
+	if !ok {
+		return
+	}
+
+	var parent nestedParent
+	result := database.DB.Table(list.ParentTable).Select("id", "url").Where("id = ?", c.Param("id")).Take(&parent)
+	if result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": list.ParentName + " not found"})
+		return
+	}
+
+	rows := expandEntities[list.Entity].newRows()
+	var total int64
+
+	query := params.Apply(list.scope(database.DB.Model(rows), parent))
+
+	// Get total count with error handling
+	if err := query.Count(&total).Error; err != nil {
+		log.Printf("Error counting %s: %v", list.Plural, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve " + list.Plural + " count",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// Get the requested page with preloaded relationships
+	if err := expand.Preload(params.Paginate(query)).Find(rows).Error; err != nil {
+		log.Printf("Error fetching %s: %v", list.Plural, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve " + list.Plural,
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	transformed, ok := expand.Render(c, reflect.ValueOf(rows).Elem().Interface())
+	if !ok {
+		return
+	}
+
+	params.Respond(c, total, transformed)
+}
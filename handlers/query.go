@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"starwars-api/models"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// filterMode controls how a query parameter is matched against its column
+type filterMode int
+
+const (
+	// filterExact matches case-insensitively; comma-separated values are OR'ed
+	filterExact filterMode = iota
+	// filterContains matches a case-insensitive substring; comma-separated values are OR'ed
+	filterContains
+	// filterInteger matches integer columns such as episode_id; comma-separated values are OR'ed
+	filterInteger
+)
+
+// listFilter maps a query parameter to a filterable column
+type listFilter struct {
+	Column string
+	Mode   filterMode
+}
+
+// rangeOperators maps the suffixes of range filter parameters to SQL operators
+var rangeOperators = map[string]string{"_gt": ">", "_gte": ">=", "_lt": "<", "_lte": "<="}
+
+// listSpec declares how a catalog list endpoint can be searched, filtered and sorted
+type listSpec struct {
+	SearchColumns []string
+	Filters       map[string]listFilter
+	Ranges        map[string]string // range filter name -> numeric column
+	Sorts         map[string]string // sort key -> column
+}
+
+// rangeCondition compares a numeric column with a bound
+type rangeCondition struct {
+	Column   string
+	Operator string
+	Value    float64
+}
+
+// listQuery is the parsed page, search, filter and sort state of a list request
+type listQuery struct {
+	Page    int
+	Limit   int
+	Search  string
+	filters map[string][]string
+	numbers map[string][]int64 // values of integer filters
+	ranges  []rangeCondition
+	sorts   []clause.OrderByColumn
+	spec    listSpec
+}
+
+// reservedListParams are query parameters that are never treated as filters
+var reservedListParams = map[string]bool{
+	"page": true, "limit": true, "search": true, "sort": true, "format": true, "expand": true, "include": true, "lang": true,
+}
+
+// parseListQuery reads page, limit, search, sort, filter and range filter
+// parameters such as ?height_gte=180 from the request. It writes a 400 response
+// and returns false if any of them is invalid.
+func parseListQuery(c *gin.Context, spec listSpec) (*listQuery, bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid page parameter",
+			Message: "Page must be a positive integer",
+			Code:    http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10 // Default to 10 if invalid
+	}
+
+	search := strings.TrimSpace(c.Query("search"))
+	if len(search) > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid search parameter",
+			Message: "Search query too long (max 100 characters)",
+			Code:    http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	q := &listQuery{
+		Page:    page,
+		Limit:   limit,
+		Search:  search,
+		filters: make(map[string][]string),
+		numbers: make(map[string][]int64),
+		spec:    spec,
+	}
+
+	for param, values := range c.Request.URL.Query() {
+		if reservedListParams[param] {
+			continue
+		}
+		if condition, isRange, valid := parseRangeFilter(spec, param, values); isRange {
+			if !valid {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "Invalid range filter",
+					Message: fmt.Sprintf("%s must be a number", param),
+					Code:    http.StatusBadRequest,
+				})
+				return nil, false
+			}
+			q.ranges = append(q.ranges, condition...)
+			continue
+		}
+		filter, ok := spec.Filters[param]
+		if !ok {
+			continue
+		}
+
+		var terms []string
+		for _, value := range values {
+			for _, term := range strings.Split(value, ",") {
+				if term = strings.TrimSpace(term); term != "" {
+					terms = append(terms, strings.ToLower(term))
+				}
+			}
+		}
+		if len(terms) == 0 {
+			continue
+		}
+
+		if filter.Mode != filterInteger {
+			q.filters[param] = terms
+			continue
+		}
+		for _, term := range terms {
+			number, err := strconv.ParseInt(term, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "Invalid filter",
+					Message: fmt.Sprintf("%s must be an integer", param),
+					Code:    http.StatusBadRequest,
+				})
+				return nil, false
+			}
+			q.numbers[param] = append(q.numbers[param], number)
+		}
+	}
+
+	if sortParam := strings.TrimSpace(c.Query("sort")); sortParam != "" {
+		for _, key := range strings.Split(sortParam, ",") {
+			key = strings.TrimSpace(key)
+			desc := strings.HasPrefix(key, "-")
+			key = strings.TrimPrefix(key, "-")
+
+			column, ok := spec.Sorts[key]
+			if !ok {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "Invalid sort parameter",
+					Message: fmt.Sprintf("Cannot sort by %q", key),
+					Code:    http.StatusBadRequest,
+				})
+				return nil, false
+			}
+
+			// Unknown values of numeric columns sort last either way
+			if spec.isNumeric(column) {
+				q.sorts = append(q.sorts, clause.OrderByColumn{Column: clause.Column{Name: column + " IS NULL", Raw: true}})
+			}
+			q.sorts = append(q.sorts, clause.OrderByColumn{
+				Column: clause.Column{Table: clause.CurrentTable, Name: column},
+				Desc:   desc,
+			})
+		}
+	}
+
+	return q, true
+}
+
+// parseRangeFilter reads a range filter parameter such as mass_lt=100. It
+// reports whether the parameter is a range filter and whether its values are numbers.
+func parseRangeFilter(spec listSpec, param string, values []string) ([]rangeCondition, bool, bool) {
+	for suffix, operator := range rangeOperators {
+		name, found := strings.CutSuffix(param, suffix)
+		column, ok := spec.Ranges[name]
+		if !found || !ok {
+			continue
+		}
+
+		conditions := make([]rangeCondition, 0, len(values))
+		for _, value := range values {
+			bound, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return nil, true, false
+			}
+			conditions = append(conditions, rangeCondition{Column: column, Operator: operator, Value: bound})
+		}
+		return conditions, true, true
+	}
+	return nil, false, false
+}
+
+// isNumeric reports whether a column is one of the spec's numeric columns
+func (spec listSpec) isNumeric(column string) bool {
+	for _, numeric := range spec.Ranges {
+		if numeric == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply adds the search and filter conditions to the query
+func (q *listQuery) Apply(db *gorm.DB) *gorm.DB {
+	if q.Search != "" && len(q.spec.SearchColumns) > 0 {
+		pattern := "%" + strings.ToLower(q.Search) + "%"
+		conditions := make([]string, len(q.spec.SearchColumns))
+		args := make([]interface{}, 0, len(q.spec.SearchColumns)*2)
+		for i, column := range q.spec.SearchColumns {
+			conditions[i] = "LOWER(?) LIKE ?"
+			args = append(args, clause.Column{Table: clause.CurrentTable, Name: column}, pattern)
+		}
+		db = db.Where(strings.Join(conditions, " OR "), args...)
+	}
+
+	for param, terms := range q.filters {
+		filter := q.spec.Filters[param]
+		column := clause.Column{Table: clause.CurrentTable, Name: filter.Column}
+
+		switch filter.Mode {
+		case filterContains:
+			conditions := make([]string, len(terms))
+			args := make([]interface{}, 0, len(terms)*2)
+			for i, term := range terms {
+				conditions[i] = "LOWER(?) LIKE ?"
+				args = append(args, column, "%"+term+"%")
+			}
+			db = db.Where(strings.Join(conditions, " OR "), args...)
+		default:
+			db = db.Where("LOWER(?) IN ?", column, terms)
+		}
+	}
+
+	for param, numbers := range q.numbers {
+		column := clause.Column{Table: clause.CurrentTable, Name: q.spec.Filters[param].Column}
+		db = db.Where("? IN ?", column, numbers)
+	}
+
+	// Rows whose value is unknown never match a range
+	for _, condition := range q.ranges {
+		column := clause.Column{Table: clause.CurrentTable, Name: condition.Column}
+		db = db.Where("? "+condition.Operator+" ?", column, condition.Value)
+	}
+
+	return db
+}
+
+// Paginate adds the sort order, offset and limit to the query
+func (q *listQuery) Paginate(db *gorm.DB) *gorm.DB {
+	for _, order := range q.sorts {
+		db = db.Order(order)
+	}
+
+	// Keep page boundaries stable between requests
+	db = db.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: "id"}})
+
+	return db.Offset((q.Page - 1) * q.Limit).Limit(q.Limit)
+}
+
+// Respond writes the paginated response with next/previous links and count headers
+func (q *listQuery) Respond(c *gin.Context, total int64, results interface{}) {
+	var next, previous *string
+
+	if int64(q.Page*q.Limit) < total {
+		nextURL := q.pageURL(c, q.Page+1)
+		next = &nextURL
+	}
+
+	if q.Page > 1 {
+		prevURL := q.pageURL(c, q.Page-1)
+		previous = &prevURL
+	}
+
+	c.Header("X-Total-Count", fmt.Sprintf("%d", total))
+	c.Header("X-Page", fmt.Sprintf("%d", q.Page))
+	c.Header("X-Limit", fmt.Sprintf("%d", q.Limit))
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Count:    total,
+		Next:     next,
+		Previous: previous,
+		Results:  results,
+	})
+}
+
+// PublicURL is the base URL clients reach the API at, for pagination links
+// behind proxies that do not forward the host; empty uses the request's host
+var PublicURL string
+
+// pageURL rebuilds the request URL for another page, keeping every other parameter
+func (q *listQuery) pageURL(c *gin.Context, page int) string {
+	params := c.Request.URL.Query()
+	params.Set("page", strconv.Itoa(page))
+	params.Set("limit", strconv.Itoa(q.Limit))
+	return publicLink(c, c.Request.URL.Path, params)
+}
+
+// publicLink returns the absolute URL of an API path as clients reach it
+func publicLink(c *gin.Context, path string, params url.Values) string {
+	link := url.URL{
+		Scheme:   requestScheme(c),
+		Host:     c.Request.Host,
+		Path:     path,
+		RawQuery: params.Encode(),
+	}
+	if public, err := url.Parse(PublicURL); PublicURL != "" && err == nil {
+		link.Scheme, link.Host = public.Scheme, public.Host
+		link.Path = strings.TrimSuffix(public.Path, "/") + link.Path
+	}
+	return link.String()
+}
+
+// requestScheme returns the scheme the client used, honouring reverse proxies
+func requestScheme(c *gin.Context) string {
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		return proto
+	}
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"starwars-api/database"
+	"starwars-api/middleware"
 	"starwars-api/models"
+	"starwars-api/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -45,6 +47,11 @@ func GetQuizQuestions(c *gin.Context) {
 		return
 	}
 
+	if err := translateQuizQuestions(c, questions); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch questions"})
+		return
+	}
+
 	// Перемішуємо відповіді для кожного питання
 	for i := range questions {
 		wrongAnswers := questions[i].GetWrongAnswersArray()
@@ -90,12 +97,43 @@ func GetQuizCategories(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"categories": categories})
 }
 
+// translateQuizQuestions замінює тексти питань перекладами на узгоджену мову;
+// неперекладені поля залишаються англійською
+func translateQuizQuestions(c *gin.Context, questions []models.QuizQuestion) error {
+	ids := make([]uint, len(questions))
+	for i, question := range questions {
+		ids[i] = question.ID
+	}
+
+	translations, err := services.NewTranslationService(database.DB).
+		Lookup("quiz_questions", c.GetString(middleware.LanguageKey), ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range questions {
+		fields := translations[questions[i].ID]
+		for field, target := range map[string]*string{
+			"question":       &questions[i].Question,
+			"correct_answer": &questions[i].CorrectAnswer,
+			"wrong_answers":  &questions[i].WrongAnswers, // JSON-масив, як і в моделі
+			"hint":           &questions[i].Hint,
+			"explanation":    &questions[i].Explanation,
+		} {
+			if text, ok := fields[field]; ok {
+				*target = text
+			}
+		}
+	}
+	return nil
+}
+
 // === QUIZ SESSION HANDLERS ===
 
 // CreateQuizSession створює нову сесію вікторини
 func CreateQuizSession(c *gin.Context) {
 	var req struct {
-		PlayerID   uint   `json:"player_id" binding:"required"`
+		PlayerID   uint   `json:"player_id"`
 		Category   string `json:"category"`
 		Difficulty int    `json:"difficulty"`
 	}
@@ -105,20 +143,15 @@ func CreateQuizSession(c *gin.Context) {
 		return
 	}
 
-	// Перевіряємо, чи існує гравець
-	var player models.Player
-	if err := database.DB.First(&player, req.PlayerID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+	// Сесія належить автентифікованому гравцю; player_id необов'язковий
+	player := middleware.CurrentPlayer(c)
+	if req.PlayerID != 0 && !authorizePlayer(c, req.PlayerID) {
 		return
 	}
 
 	session := models.QuizSession{
 		ID:         uuid.New().String(),
-		PlayerID:   req.PlayerID,
+		PlayerID:   player.ID,
 		Category:   req.Category,
 		Difficulty: req.Difficulty,
 		StartedAt:  time.Now(),
@@ -157,6 +190,9 @@ func SubmitQuizAnswer(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
 	}
+	if !authorizePlayer(c, session.PlayerID) {
+		return
+	}
 
 	// Знаходимо питання
 	var question models.QuizQuestion
@@ -169,14 +205,23 @@ func SubmitQuizAnswer(c *gin.Context) {
 		return
 	}
 
+	// Відповідь приймається англійською або мовою гравця
+	originalAnswer := question.CorrectAnswer
+	questions := []models.QuizQuestion{question}
+	if err := translateQuizQuestions(c, questions); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	question = questions[0]
+
 	// Перевіряємо правильність відповіді
-	isCorrect := req.SelectedAnswer == question.CorrectAnswer
+	isCorrect := req.SelectedAnswer == originalAnswer || req.SelectedAnswer == question.CorrectAnswer
 	pointsEarned := 0
 	if isCorrect {
 		pointsEarned = question.Points
 		// Бонус за швидкість (якщо відповів швидко)
-		if req.TimeSpent < 10 {
-			pointsEarned += 5
+		if req.TimeSpent < GameBalance.QuizSpeedBonusSeconds {
+			pointsEarned += GameBalance.QuizSpeedBonusPoints
 		}
 	}
 
@@ -239,6 +284,14 @@ func CompleteQuizSession(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
 	}
+	if !authorizePlayer(c, session.PlayerID) {
+		return
+	}
+	// Досвід за сесію нараховується один раз
+	if session.CompletedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Quiz session already completed"})
+		return
+	}
 
 	// Завершуємо сесію
 	now := time.Now()
@@ -273,18 +326,18 @@ func CompleteQuizSession(c *gin.Context) {
 	}
 
 	// Додаємо досвід гравцю
-	experienceGained := session.Score / 10 // 1 досвід за кожні 10 очок
+	experienceGained := session.Score / GameBalance.QuizPointsPerExperience // 1 досвід за кожні QuizPointsPerExperience очок
 	if experienceGained > 0 {
-		var player models.Player
-		if err := database.DB.First(&player, session.PlayerID).Error; err == nil {
-			player.Experience += experienceGained
-			newLevel := calculateLevel(player.Experience)
-			if newLevel > player.Level {
-				player.Level = newLevel
-				player.Credits += (newLevel - player.Level) * 50 // Бонус за рівень
-			}
-			player.UpdatedAt = time.Now()
-			database.DB.Save(&player)
+		_, err := Progression.Apply(database.DB, session.PlayerID, services.Change{
+			Experience:        experienceGained,
+			Source:            models.SourceQuiz,
+			Description:       "Quiz completed",
+			RelatedEntityType: "quiz_session",
+			RelatedEntityID:   session.ID,
+		})
+		if err != nil {
+			progressionError(c, err)
+			return
 		}
 	}
 
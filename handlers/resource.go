@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+	"starwars-api/middleware"
+	"starwars-api/models"
 	"starwars-api/services"
 	"strconv"
 
@@ -100,15 +102,16 @@ func (h *ResourceHandler) SpendResources(c *gin.Context) {
 		return
 	}
 
+	// Negative amounts would add resources
 	var request struct {
-		Credits        int    `json:"credits"`
-		Crystals       int    `json:"crystals"`
-		Durasteel      int    `json:"durasteel"`
-		Transparisteel int    `json:"transparisteel"`
-		Tibanna        int    `json:"tibanna"`
-		Kyber          int    `json:"kyber"`
-		Energy         int    `json:"energy"`
-		Fuel           int    `json:"fuel"`
+		Credits        int    `json:"credits" binding:"min=0"`
+		Crystals       int    `json:"crystals" binding:"min=0"`
+		Durasteel      int    `json:"durasteel" binding:"min=0"`
+		Transparisteel int    `json:"transparisteel" binding:"min=0"`
+		Tibanna        int    `json:"tibanna" binding:"min=0"`
+		Kyber          int    `json:"kyber" binding:"min=0"`
+		Energy         int    `json:"energy" binding:"min=0"`
+		Fuel           int    `json:"fuel" binding:"min=0"`
 		Source         string `json:"source" binding:"required"`
 		Description    string `json:"description"`
 	}
@@ -216,7 +219,7 @@ func (h *ResourceHandler) UpgradeResourceGeneration(c *gin.Context) {
 
 	var request struct {
 		ResourceType string `json:"resource_type" binding:"required"`
-		UpgradeCost  int    `json:"upgrade_cost" binding:"required"`
+		UpgradeCost  int    `json:"upgrade_cost" binding:"required,min=1"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -376,25 +379,28 @@ func (h *ResourceHandler) GetResourceBundles(c *gin.Context) {
 }
 
 // RegisterResourceRoutes registers all resource-related routes
-func RegisterResourceRoutes(router *gin.Engine, resourceService *services.ResourceService) {
+func RegisterResourceRoutes(router *gin.Engine, resourceService *services.ResourceService, playerAuth gin.HandlerFunc, roles *middleware.RoleAuth) {
 	handler := NewResourceHandler(resourceService)
 
 	v1 := router.Group("/api/v1")
 	{
 		resources := v1.Group("/resources")
 		{
+			// A player's own resources
+			player := resources.Group("/:playerId", playerAuth, middleware.PlayerOwnsParam("playerId"))
+
 			// Resource management
-			resources.GET("/:playerId", handler.GetPlayerResources)
-			resources.POST("/:playerId/add", handler.AddResources)
-			resources.POST("/:playerId/spend", handler.SpendResources)
-			resources.GET("/:playerId/transactions", handler.GetResourceTransactions)
+			player.GET("", handler.GetPlayerResources)
+			resources.POST("/:playerId/add", roles.Require(models.RoleModerator, models.RoleAdmin), handler.AddResources)
+			player.POST("/spend", handler.SpendResources)
+			player.GET("/transactions", handler.GetResourceTransactions)
 
 			// Resource conversion
 			resources.GET("/conversions", handler.GetAvailableConversions)
-			resources.POST("/:playerId/convert", handler.ConvertResources)
+			player.POST("/convert", handler.ConvertResources)
 
 			// Resource upgrades
-			resources.POST("/:playerId/upgrade", handler.UpgradeResourceGeneration)
+			player.POST("/upgrade", handler.UpgradeResourceGeneration)
 
 			// Resource information
 			resources.GET("/types", handler.GetResourceTypes)
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"starwars-api/database"
+	"starwars-api/services"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchService *services.SearchService
+}
+
+func NewSearchHandler(searchService *services.SearchService) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// Search returns ranked catalog entities of every type matching the query
+// GET /api/v1/search?q=skywalker&type=people,starships&page=1&limit=10
+func (h *SearchHandler) Search(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" || len(q) > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid q parameter",
+			Message: "Search query must be between 1 and 100 characters",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var types []string
+	if typeParam := c.Query("type"); typeParam != "" {
+		known := make(map[string]bool)
+		for _, entityType := range database.SearchEntityTypes() {
+			known[entityType] = true
+		}
+
+		for _, entityType := range strings.Split(typeParam, ",") {
+			entityType = strings.TrimSpace(entityType)
+			if !known[entityType] {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "Invalid type parameter",
+					Message: "Unknown entity type: " + entityType,
+					Code:    http.StatusBadRequest,
+				})
+				return
+			}
+			types = append(types, entityType)
+		}
+	}
+
+	params, ok := parseListQuery(c, listSpec{})
+	if !ok {
+		return
+	}
+
+	results, total, err := h.searchService.Search(q, types, params.Limit, (params.Page-1)*params.Limit)
+	if err != nil {
+		log.Printf("Error searching catalog: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to search the catalog",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	params.Respond(c, total, results)
+}
+
+func RegisterSearchRoutes(router *gin.Engine, searchService *services.SearchService) {
+	handler := NewSearchHandler(searchService)
+
+	v1 := router.Group("/api/v1")
+	{
+		v1.GET("/search", handler.Search)
+	}
+}
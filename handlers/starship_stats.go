@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"starwars-api/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxComparedStarships limits how many starships can be compared at once
+const maxComparedStarships = 10
+
+type StarshipStatsHandler struct {
+	statsService *services.StarshipStatsService
+}
+
+func NewStarshipStatsHandler(statsService *services.StarshipStatsService) *StarshipStatsHandler {
+	return &StarshipStatsHandler{statsService: statsService}
+}
+
+// Compare returns the stats of starships side by side with per-stat winners
+// GET /api/v1/starships/compare?ids=1,2,3
+func (h *StarshipStatsHandler) Compare(c *gin.Context) {
+	var ids []uint
+	seen := make(map[uint]bool)
+	for _, part := range strings.Split(c.Query("ids"), ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid ids parameter",
+				Message: "ids must be a comma-separated list of starship IDs",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+	}
+
+	if len(ids) < 2 || len(ids) > maxComparedStarships {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid ids parameter",
+			Message: "Compare between 2 and " + strconv.Itoa(maxComparedStarships) + " different starships",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	comparison, err := h.statsService.Compare(ids)
+	if err != nil {
+		var missing *services.MissingStarshipsError
+		if errors.As(err, &missing) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Starship not found",
+				Message: err.Error(),
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to compare starships",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      comparison,
+		Message:   "Starships compared successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// Rankings ranks the catalog's starships by a stat
+// GET /api/v1/starships/rankings?by=attack_power&limit=10
+func (h *StarshipStatsHandler) Rankings(c *gin.Context) {
+	by := c.DefaultQuery("by", "attack_power")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	rankings, err := h.statsService.Rankings(by, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownStat) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid by parameter",
+				Message: "Rank by one of: " + strings.Join(services.StarshipStatKeys(), ", "),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to rank starships",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      rankings,
+		Message:   "Starship rankings retrieved successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+func RegisterStarshipStatsRoutes(router *gin.Engine, statsService *services.StarshipStatsService) {
+	handler := NewStarshipStatsHandler(statsService)
+
+	v1 := router.Group("/api/v1")
+	{
+		v1.GET("/starships/compare", handler.Compare)
+		v1.GET("/starships/rankings", handler.Rankings)
+	}
+}
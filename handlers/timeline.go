@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"starwars-api/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TimelineHandler struct {
+	timelineService *services.TimelineService
+}
+
+func NewTimelineHandler(timelineService *services.TimelineService) *TimelineHandler {
+	return &TimelineHandler{timelineService: timelineService}
+}
+
+// GetTimeline returns events and films in chronological order
+// GET /api/v1/timeline?era=Imperial&type=battle&from=32BBY&to=4ABY&films=false
+func (h *TimelineHandler) GetTimeline(c *gin.Context) {
+	filter := services.TimelineFilter{
+		Era:          c.Query("era"),
+		Type:         c.Query("type"),
+		IncludeFilms: c.DefaultQuery("films", "true") != "false",
+	}
+
+	for param, target := range map[string]**float64{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+
+		year, err := parseTimelineYear(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid " + param + " parameter",
+				Message: "Use a galactic date such as 19BBY or 4ABY, or a signed year",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		*target = &year
+	}
+
+	timeline, err := h.timelineService.GetTimeline(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to build timeline",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      timeline,
+		Message:   "Timeline retrieved successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// parseTimelineYear accepts either a BBY/ABY date or a signed numeric year
+func parseTimelineYear(value string) (float64, error) {
+	if year, err := strconv.ParseFloat(value, 64); err == nil {
+		return year, nil
+	}
+	return services.ParseGalacticYear(value)
+}
+
+func RegisterTimelineRoutes(router *gin.Engine, timelineService *services.TimelineService) {
+	handler := NewTimelineHandler(timelineService)
+
+	v1 := router.Group("/api/v1")
+	{
+		v1.GET("/timeline", handler.GetTimeline)
+	}
+}
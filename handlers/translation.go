@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"starwars-api/middleware"
+	"starwars-api/models"
+	"starwars-api/services"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxTranslationFileSize limits uploaded translation files
+const maxTranslationFileSize = 10 << 20
+
+type TranslationHandler struct {
+	translationService *services.TranslationService
+}
+
+func NewTranslationHandler(translationService *services.TranslationService) *TranslationHandler {
+	return &TranslationHandler{translationService: translationService}
+}
+
+// ListLanguages returns the translated languages with their number of translations
+// GET /api/v1/admin/translations
+func (h *TranslationHandler) ListLanguages(c *gin.Context) {
+	summaries, err := h.translationService.Summaries()
+	if err != nil {
+		log.Printf("Error listing translations: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to list translations",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      summaries,
+		Message:   "Translations retrieved successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// Upload replaces the translations of a language with an uploaded file, sent
+// as the JSON body or as the "file" field of a multipart form
+// PUT /api/v1/admin/translations/uk {"films": {"1": {"title": "Прихована загроза"}}}
+func (h *TranslationHandler) Upload(c *gin.Context) {
+	language := middleware.PrimaryLanguage(c.Param("lang"))
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxTranslationFileSize)
+
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		upload, err := c.FormFile("file")
+		if err != nil {
+			respondInvalidTranslations(c, "Send the translation file as the \"file\" form field")
+			return
+		}
+		file, err := upload.Open()
+		if err != nil {
+			respondInvalidTranslations(c, "Failed to read the uploaded file")
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	var translations services.TranslationFile
+	if err := json.NewDecoder(body).Decode(&translations); err != nil {
+		respondInvalidTranslations(c, "Translation files map entity types to IDs to fields, e.g. {\"films\": {\"1\": {\"title\": \"...\"}}}")
+		return
+	}
+
+	count, err := h.translationService.Import(language, translations)
+	if err != nil {
+		var invalid *services.InvalidTranslationsError
+		switch {
+		case errors.Is(err, services.ErrInvalidLanguage):
+			respondInvalidLanguage(c)
+		case errors.As(err, &invalid):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":    "Invalid translations",
+				"message":  "The translation file was rejected; nothing was changed",
+				"code":     http.StatusUnprocessableEntity,
+				"problems": invalid.Problems,
+			})
+		default:
+			log.Printf("Error importing %s translations: %v", language, err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Database error",
+				Message: "Failed to save translations",
+				Code:    http.StatusInternalServerError,
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      services.LanguageSummary{Language: language, Translations: int64(count)},
+		Message:   "Translations uploaded successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+// Download returns the translations of a language in the upload format
+// GET /api/v1/admin/translations/uk
+func (h *TranslationHandler) Download(c *gin.Context) {
+	language := middleware.PrimaryLanguage(c.Param("lang"))
+
+	translations, err := h.translationService.Export(language)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidLanguage) {
+			respondInvalidLanguage(c)
+			return
+		}
+		log.Printf("Error exporting %s translations: %v", language, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to load translations",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+// Delete removes every translation of a language
+// DELETE /api/v1/admin/translations/uk
+func (h *TranslationHandler) Delete(c *gin.Context) {
+	language := middleware.PrimaryLanguage(c.Param("lang"))
+
+	deleted, err := h.translationService.Delete(language)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidLanguage) {
+			respondInvalidLanguage(c)
+			return
+		}
+		log.Printf("Error deleting %s translations: %v", language, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to delete translations",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Data:      services.LanguageSummary{Language: language, Translations: deleted},
+		Message:   "Translations deleted successfully",
+		Timestamp: time.Now(),
+	})
+}
+
+func respondInvalidTranslations(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   "Invalid translation file",
+		Message: message,
+		Code:    http.StatusBadRequest,
+	})
+}
+
+func respondInvalidLanguage(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   "Invalid language",
+		Message: "Use a two- or three-letter language code other than en, e.g. uk",
+		Code:    http.StatusBadRequest,
+	})
+}
+
+func RegisterTranslationRoutes(router *gin.Engine, translationService *services.TranslationService, roles *middleware.RoleAuth) {
+	handler := NewTranslationHandler(translationService)
+
+	translations := router.Group("/api/v1/admin/translations", roles.Require(models.RoleAdmin))
+	{
+		translations.GET("", handler.ListLanguages)
+		translations.GET("/:lang", handler.Download)
+		translations.PUT("/:lang", handler.Upload)
+		translations.DELETE("/:lang", handler.Delete)
+	}
+}
@@ -0,0 +1,173 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"regexp"
+	"starwars-api/config"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(message Message) error
+}
+
+// New returns the mailer of the configured driver
+func New(cfg config.MailConfig) (Mailer, error) {
+	if _, err := mail.ParseAddress(cfg.From); err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", cfg.From, err)
+	}
+
+	switch cfg.Driver {
+	case "smtp":
+		return &SMTPMailer{config: cfg.SMTP, from: cfg.From}, nil
+	case "file":
+		if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create mail directory: %w", err)
+		}
+		return &FileMailer{dir: cfg.Dir, from: cfg.From}, nil
+	case "log":
+		return &LogMailer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver %q", cfg.Driver)
+	}
+}
+
+// SMTPMailer sends emails through an SMTP server, upgrading the connection
+// with STARTTLS when the server offers it
+type SMTPMailer struct {
+	config config.SMTPConfig
+	from   string
+}
+
+func (m *SMTPMailer) Send(message Message) error {
+	from, _ := mail.ParseAddress(m.from)
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := m.config.Host + ":" + strconv.Itoa(m.config.Port)
+	if err := smtp.SendMail(addr, auth, from.Address, []string{message.To}, format(m.from, message)); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", message.To, err)
+	}
+	return nil
+}
+
+// FileMailer writes every email to a file of its directory instead of
+// sending it, for local development and tests
+type FileMailer struct {
+	dir  string
+	from string
+	sent atomic.Uint64
+}
+
+// unsafeFileName matches the characters of an address not kept in file names
+var unsafeFileName = regexp.MustCompile(`[^A-Za-z0-9@._-]+`)
+
+func (m *FileMailer) Send(message Message) error {
+	name := fmt.Sprintf("%s-%d-%s.eml",
+		time.Now().UTC().Format("20060102T150405"), m.sent.Add(1), unsafeFileName.ReplaceAllString(message.To, "_"))
+	if err := os.WriteFile(filepath.Join(m.dir, name), format(m.from, message), 0o600); err != nil {
+		return fmt.Errorf("failed to write email to %s: %w", message.To, err)
+	}
+	return nil
+}
+
+// LogMailer logs every email instead of sending it
+type LogMailer struct{}
+
+func (m *LogMailer) Send(message Message) error {
+	log.Printf("📧 Email to %s: %s\n%s", message.To, message.Subject, message.Text)
+	return nil
+}
+
+// format renders a message as RFC 5322 text. ASCII text is sent as is, so
+// that links stay readable in files and logs; anything else is
+// quoted-printable encoded.
+func format(from string, message Message) []byte {
+	encoding, body := "7bit", []byte(strings.ReplaceAll(message.Text, "\n", "\r\n"))
+	if !isPlainASCII(message.Text) {
+		var encoded bytes.Buffer
+		writer := quotedprintable.NewWriter(&encoded)
+		writer.Write([]byte(message.Text))
+		writer.Close()
+		encoding, body = "quoted-printable", encoded.Bytes()
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "From: %s\r\n", from)
+	fmt.Fprintf(&out, "To: %s\r\n", message.To)
+	fmt.Fprintf(&out, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&out, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	out.WriteString("MIME-Version: 1.0\r\n")
+	out.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	fmt.Fprintf(&out, "Content-Transfer-Encoding: %s\r\n\r\n", encoding)
+	out.Write(body)
+	return out.Bytes()
+}
+
+// isPlainASCII reports whether text is ASCII with lines short enough to send
+// without encoding
+func isPlainASCII(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		if len(line) > 998 {
+			return false
+		}
+		for i := 0; i < len(line); i++ {
+			if line[i] >= 0x80 || line[i] == '\r' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// templateFiles holds the message templates. The first line of a template is
+// "Subject: ..." and the rest is the text, both rendered with text/template.
+//
+//go:embed templates/*.txt
+var templateFiles embed.FS
+
+var templates = template.Must(template.ParseFS(templateFiles, "templates/*.txt"))
+
+// Render renders the message template name, such as "verify_email", to an
+// address
+func Render(name, to string, data interface{}) (Message, error) {
+	var out bytes.Buffer
+	if err := templates.ExecuteTemplate(&out, name+".txt", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s email: %w", name, err)
+	}
+
+	subject, text, _ := strings.Cut(out.String(), "\n")
+	subject, ok := strings.CutPrefix(subject, "Subject: ")
+	if !ok {
+		return Message{}, fmt.Errorf("%s email template must start with a Subject line", name)
+	}
+	return Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject),
+		Text:    strings.TrimLeft(text, "\n"),
+	}, nil
+}
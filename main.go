@@ -3,30 +3,80 @@ package main
 import (
 	"log"
 	"os"
+	"starwars-api/cli"
+	"starwars-api/config"
 	"starwars-api/database"
+	"starwars-api/graph"
 	"starwars-api/handlers"
+	"starwars-api/mailer"
 	"starwars-api/middleware"
+	"starwars-api/models"
 	"starwars-api/services"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	// Set Gin mode based on environment
-	if os.Getenv("GIN_MODE") == "" {
-		gin.SetMode(gin.DebugMode)
+	// Run a subcommand instead of the server when one is given
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1:]))
 	}
 
+	// Load the configuration file named by CONFIG_FILE and environment overrides
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("❌ ", err)
+	}
+	if cfg.File != "" {
+		log.Printf("⚙️  Loaded configuration from %s", cfg.File)
+	}
+
+	gin.SetMode(cfg.Server.Mode)
+	handlers.MaxExpandDepth = cfg.API.ExpandMaxDepth
+	handlers.PublicURL = cfg.Server.PublicURL
+	handlers.GameBalance = cfg.Game
+
 	// Initialize database
-	database.Initialize()
+	database.Initialize(cfg.Database)
+
+	// Initialize services; experience, levels and currencies all go through progression
+	progressionService := services.NewProgressionService(database.DB, cfg.Game)
+	handlers.Progression = progressionService
+	missionService := services.NewMissionService(database.DB, progressionService)
+	fleetService := services.NewFleetService(database.DB, progressionService)
+	battleService := services.NewBattleService(database.DB, progressionService)
+	resourceService := services.NewResourceService(database.DB, progressionService)
+	achievementService := services.NewAchievementService(database.DB, progressionService)
+	timelineService := services.NewTimelineService(database.DB)
+	searchService := services.NewSearchService(database.DB)
+	characterGraphService := services.NewCharacterGraphService(database.DB)
+	starshipStatsService := services.NewStarshipStatsService(database.DB)
+	translationService := services.NewTranslationService(database.DB)
+	catalogAdminService := services.NewCatalogAdminService(database.DB)
+	authService := services.NewAuthService(database.DB, progressionService, cfg.Auth)
+	accessService := services.NewAccessService(database.DB)
+
+	// Level-up rewards beyond credits, and achievements for reaching levels
+	progressionService.OnLevelUp(resourceService.GrantLevelUpMaterials)
+	progressionService.OnLevelUp(fleetService.GrantLevelUpHangarSlots)
+	progressionService.OnLevelUp(achievementService.ReachLevel)
+
+	// Account emails go through the configured mailer, and link to the
+	// configured public URL rather than the host of the request
+	accountMailer, err := mailer.New(cfg.Mail)
+	if err != nil {
+		log.Fatal("❌ Failed to set up the mailer:", err)
+	}
+	verifyEmailURL := cfg.Server.BaseURL() + "/api/v1/auth/verify-email"
+	accountService := services.NewAccountService(database.DB, authService, accountMailer, cfg.Auth, cfg.Mail, verifyEmailURL)
 
-	// Initialize services
-	missionService := services.NewMissionService(database.DB)
-	fleetService := services.NewFleetService(database.DB)
-	battleService := services.NewBattleService(database.DB)
-	resourceService := services.NewResourceService(database.DB)
-	achievementService := services.NewAchievementService(database.DB, resourceService)
+	// Initialize GraphQL schema
+	graphSchema, err := graph.NewSchema(database.DB)
+	if err != nil {
+		log.Fatal("❌ Failed to build GraphQL schema:", err)
+	}
 
 	// Create Gin router
 	router := gin.New()
@@ -34,106 +84,187 @@ func main() {
 	// Setup middleware
 	router.Use(middleware.Logger())
 	router.Use(gin.Recovery())
-	router.Use(middleware.SetupCORS())
+	router.Use(middleware.SetupCORS(cfg.CORS.AllowedOrigins))
+
+	// Setup rate limiting per IP, unless disabled
+	if cfg.RateLimit.Requests > 0 {
+		rateLimiter := middleware.NewRateLimiter(cfg.RateLimit.Requests, time.Duration(cfg.RateLimit.Window))
+		router.Use(middleware.RateLimit(rateLimiter))
+	}
 
-	// Setup rate limiting (100 requests per minute per IP)
-	rateLimiter := middleware.NewRateLimiter(100, time.Minute)
-	router.Use(middleware.RateLimit(rateLimiter))
+	// Catalog responses may be cached for a while, then revalidated
+	catalogCache := middleware.ConditionalGET(func() (uint64, time.Time, error) {
+		return database.CatalogVersion(database.DB)
+	}, time.Duration(cfg.Cache.CatalogMaxAge))
+
+	// Player routes require a player access token
+	playerAuth := middleware.PlayerAuth(authService.Authenticate)
+
+	// Privileged routes require a role, from a player or an API key, and are audited
+	roles := middleware.NewRoleAuth(authService.Authenticate, accessService.AuthenticateAPIKey, accessService.RecordAudit)
+	moderators := roles.Require(models.RoleModerator, models.RoleAdmin)
+	admins := roles.Require(models.RoleAdmin)
+
+	// Catalog and quiz content is served in the negotiated language
+	contentLanguage := middleware.Language(translationService.Languages)
 
 	// Setup API routes with versioning
 	v1 := router.Group("/api/v1")
 	{
+		// Catalog endpoints render as JSON or, SWAPI-style, as Wookiee, and
+		// answer conditional requests from the catalog version
+		catalog := v1.Group("", middleware.ResponseFormat(), contentLanguage, catalogCache)
+
 		// Characters endpoints
-		v1.GET("/people", handlers.GetCharacters)
-		v1.GET("/people/:id", handlers.GetCharacterByID)
+		catalog.GET("/people", handlers.GetCharacters)
+		catalog.GET("/people/:id", handlers.GetCharacterByID)
+		catalog.GET("/people/:id/organizations", handlers.GetCharacterOrganizations)
+		catalog.GET("/people/:id/weapons", handlers.GetCharacterWeapons)
 
 		// Films endpoints
-		v1.GET("/films", handlers.GetFilms)
+		catalog.GET("/films", handlers.GetFilms)
+		catalog.GET("/films/:id/characters", handlers.GetFilmCharacters)
+		catalog.GET("/films/:id/planets", handlers.GetFilmPlanets)
 
 		// Species endpoints
-		v1.GET("/species", handlers.GetSpecies)
+		catalog.GET("/species", handlers.GetSpecies)
 
 		// Starships endpoints
-		v1.GET("/starships", handlers.GetStarships)
+		catalog.GET("/starships", handlers.GetStarships)
+		catalog.GET("/starships/:id/details", handlers.GetStarshipDetails)
+
+		// Vehicles endpoints
+		catalog.GET("/vehicles", handlers.GetVehicles)
+		catalog.GET("/vehicles/:id", handlers.GetVehicleByID)
 
 		// Planets endpoints
-		v1.GET("/planets", handlers.GetPlanets)
-		v1.GET("/planets/:id", handlers.GetPlanetByID)
+		catalog.GET("/planets", handlers.GetPlanets)
+		catalog.GET("/planets/:id", handlers.GetPlanetByID)
+		catalog.GET("/planets/:id/details", handlers.GetPlanetDetails)
+		catalog.GET("/planets/:id/residents", handlers.GetPlanetResidents)
 
 		// Organizations endpoints
-		v1.GET("/organizations", handlers.GetOrganizations)
+		catalog.GET("/organizations", handlers.GetOrganizations)
+		catalog.GET("/organizations/:id/members", handlers.GetOrganizationMembers)
 
 		// Weapons endpoints
-		v1.GET("/weapons", handlers.GetWeapons)
+		catalog.GET("/weapons", handlers.GetWeapons)
+
+		// Events endpoints
+		catalog.GET("/events", handlers.GetEvents)
+		catalog.GET("/events/:id", handlers.GetEventByID)
+
+		// Bulk catalog exports, streamed as CSV or NDJSON
+		v1.GET("/export/:file", catalogCache, handlers.ExportCatalog)
+
+		// Timeline endpoints
+		handlers.RegisterTimelineRoutes(router, timelineService)
+
+		// Cross-entity search endpoints
+		handlers.RegisterSearchRoutes(router, searchService)
+
+		// Character connection graph endpoints
+		handlers.RegisterCharacterGraphRoutes(router, characterGraphService)
+
+		// Starship comparison and ranking endpoints
+		handlers.RegisterStarshipStatsRoutes(router, starshipStatsService)
+
+		// Catalog admin endpoints, for admins
+		handlers.RegisterAdminRoutes(router, catalogAdminService, roles)
+
+		// Translation upload endpoints, for admins
+		handlers.RegisterTranslationRoutes(router, translationService, roles)
+
+		// Configuration view, secrets redacted, for admins
+		handlers.RegisterConfigRoutes(router, cfg, roles)
+
+		// Roles, API keys and the audit log, for admins
+		handlers.RegisterAccessRoutes(router, accessService, roles)
+
+		// Player registration, login, sessions, email verification and password resets
+		handlers.RegisterAuthRoutes(router, authService, accountService, playerAuth)
 
 		// Game endpoints
 		game := v1.Group("/game")
 		{
-			// Player endpoints
-			game.POST("/player/create", handlers.CreatePlayer)
-			game.GET("/player/:id", handlers.GetPlayerProfile)
-			game.PUT("/player/:id", handlers.UpdatePlayerProfile)
-			game.GET("/player/:id/stats", handlers.GetPlayerStats)
-			game.POST("/player/:id/experience", handlers.AddExperience)
-			game.POST("/player/:id/credits", handlers.AddCredits)
+			// Player endpoints, for the player only
+			player := game.Group("/player/:id", playerAuth, middleware.PlayerOwnsParam("id"))
+			{
+				player.GET("", handlers.GetPlayerProfile)
+				player.PUT("", handlers.UpdatePlayerProfile)
+				player.GET("/stats", handlers.GetPlayerStats)
+				player.GET("/ledger", handlers.GetPlayerLedger)
+				player.GET("/level-ups", handlers.GetPlayerLevelUps)
+			}
+			game.GET("/levels", handlers.GetLevels)
+
+			// Granting experience and credits is for moderators
+			game.POST("/player/:id/experience", moderators, handlers.AddExperience)
+			game.POST("/player/:id/credits", moderators, handlers.AddCredits)
 
 			// Game session endpoints
-			game.POST("/session/create", handlers.CreateGameSession)
-			game.PUT("/session/:id/complete", handlers.CompleteGameSession)
+			game.POST("/session/create", playerAuth, handlers.CreateGameSession)
+			game.PUT("/session/:id/complete", playerAuth, handlers.CompleteGameSession)
 
 			// Quiz endpoints
-			quiz := game.Group("/quiz")
+			quiz := game.Group("/quiz", contentLanguage)
 			{
 				quiz.GET("/questions", handlers.GetQuizQuestions)
 				quiz.GET("/categories", handlers.GetQuizCategories)
-				quiz.POST("/session/create", handlers.CreateQuizSession)
-				quiz.POST("/session/:sessionId/answer", handlers.SubmitQuizAnswer)
-				quiz.PUT("/session/:sessionId/complete", handlers.CompleteQuizSession)
+				quiz.POST("/session/create", playerAuth, handlers.CreateQuizSession)
+				quiz.POST("/session/:sessionId/answer", playerAuth, handlers.SubmitQuizAnswer)
+				quiz.PUT("/session/:sessionId/complete", playerAuth, handlers.CompleteQuizSession)
 				quiz.GET("/leaderboard", handlers.GetQuizLeaderboard)
 			}
 		}
 
 		// Mission endpoints
-		handlers.RegisterMissionRoutes(router, missionService)
+		handlers.RegisterMissionRoutes(router, missionService, playerAuth, roles)
 
 		// Fleet endpoints
-		handlers.RegisterFleetRoutes(router, fleetService)
+		handlers.RegisterFleetRoutes(router, fleetService, playerAuth)
 
 		// Battle endpoints
-		handlers.RegisterBattleRoutes(router, battleService)
+		handlers.RegisterBattleRoutes(router, battleService, playerAuth)
 
 		// Resource endpoints
-		handlers.RegisterResourceRoutes(router, resourceService)
+		handlers.RegisterResourceRoutes(router, resourceService, playerAuth, roles)
 
 		// Achievement endpoints
-		handlers.RegisterAchievementRoutes(router, achievementService)
+		handlers.RegisterAchievementRoutes(router, achievementService, playerAuth, roles)
 	}
 
 	// Legacy API routes (for backward compatibility)
 	api := router.Group("/api")
 	{
+		legacy := api.Group("", middleware.ResponseFormat(), contentLanguage, catalogCache)
+
 		// Characters endpoints
-		api.GET("/people", handlers.GetCharacters)
-		api.GET("/people/:id", handlers.GetCharacterByID)
+		legacy.GET("/people", handlers.GetCharacters)
+		legacy.GET("/people/:id", handlers.GetCharacterByID)
 
 		// Films endpoints
-		api.GET("/films", handlers.GetFilms)
+		legacy.GET("/films", handlers.GetFilms)
 
 		// Species endpoints
-		api.GET("/species", handlers.GetSpecies)
+		legacy.GET("/species", handlers.GetSpecies)
 
 		// Starships endpoints
-		api.GET("/starships", handlers.GetStarships)
+		legacy.GET("/starships", handlers.GetStarships)
+
+		// Vehicles endpoints
+		legacy.GET("/vehicles", handlers.GetVehicles)
+		legacy.GET("/vehicles/:id", handlers.GetVehicleByID)
 
 		// Planets endpoints
-		api.GET("/planets", handlers.GetPlanets)
-		api.GET("/planets/:id", handlers.GetPlanetByID)
+		legacy.GET("/planets", handlers.GetPlanets)
+		legacy.GET("/planets/:id", handlers.GetPlanetByID)
 
 		// Organizations endpoints
-		api.GET("/organizations", handlers.GetOrganizations)
+		legacy.GET("/organizations", handlers.GetOrganizations)
 
 		// Weapons endpoints
-		api.GET("/weapons", handlers.GetWeapons)
+		legacy.GET("/weapons", handlers.GetWeapons)
 
 		// Marketplace endpoints
 		marketplace := api.Group("/marketplace")
@@ -145,10 +276,13 @@ func main() {
 			marketplace.POST("/search", handlers.SearchProductsGin)
 			marketplace.GET("/cart", handlers.GetCartGin)
 			marketplace.POST("/cart/add", handlers.AddToCartGin)
-			marketplace.POST("/sync", handlers.SyncWithBrightDataGin)
+			marketplace.POST("/sync", admins, handlers.SyncWithBrightDataGin)
 		}
 	}
 
+	// GraphQL endpoint
+	handlers.RegisterGraphQLRoutes(router, graphSchema)
+
 	// Health check endpoint with detailed information
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -171,9 +305,17 @@ func main() {
 				"films":         "/api/films",
 				"species":       "/api/species",
 				"starships":     "/api/starships",
+				"vehicles":      "/api/vehicles",
 				"planets":       "/api/planets",
 				"organizations": "/api/organizations",
 				"weapons":       "/api/weapons",
+				"events":        "/api/v1/events",
+				"auth":          "/api/v1/auth/login",
+				"timeline":      "/api/v1/timeline",
+				"search":        "/api/v1/search?q=",
+				"graph":         "/api/v1/graph/path?from=&to=",
+				"compare":       "/api/v1/starships/compare?ids=",
+				"graphql":       "/graphql",
 				"health":        "/health",
 			},
 			"features": []string{
@@ -181,21 +323,24 @@ func main() {
 				"9 main saga films",
 				"10+ species",
 				"10+ starships including Death Star",
+				"Vehicles from speeder bikes to AT-STs",
 				"10+ planets",
 				"Organizations (Jedi, Sith, Empire, Rebels)",
 				"Weapons including lightsabers",
+				"Galactic timeline of events and films",
 				"Pagination and search",
-				"SWAPI-compatible format",
+				"GraphQL with batched relation loading",
+				"SWAPI-compatible format, including ?format=wookiee",
+				"Relationship expansion with ?expand=",
+				"HTTP caching with ETag and Last-Modified",
+				"Starship comparisons and rankings",
+				"Translations with Accept-Language or ?lang=",
 			},
 			"documentation": "https://github.com/DimaJoyti/ngrx-starwars",
 		})
 	})
 
-	// Get port from environment or default to 8080
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	port := strconv.Itoa(cfg.Server.Port)
 
 	// Start server
 	log.Printf("🚀 Starting Star Wars API server on port %s", port)
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CatalogVersionFunc reports the catalog's write counter and last write time
+type CatalogVersionFunc func() (uint64, time.Time, error)
+
+// ConditionalGET validates catalog responses against the catalog version.
+// Successful responses carry an ETag, Last-Modified and a public Cache-Control
+// policy; requests whose If-None-Match or, failing that, If-Modified-Since still
+// match get a 304 without the handler running. The ETag covers the request URI
+// and the negotiated format and language, so it must run after ResponseFormat
+// and Language.
+func ConditionalGET(version CatalogVersionFunc, maxAge time.Duration) gin.HandlerFunc {
+	cacheControl := fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		current, modified, err := version()
+		if err != nil {
+			log.Printf("Error reading catalog version: %v", err)
+			c.Next()
+			return
+		}
+
+		hash := fnv.New64a()
+		hash.Write([]byte(c.Request.URL.RequestURI()))
+		hash.Write([]byte{0})
+		hash.Write([]byte(c.GetString(FormatKey)))
+		hash.Write([]byte{0})
+		hash.Write([]byte(c.GetString(LanguageKey)))
+		etag := fmt.Sprintf(`"%d-%x"`, current, hash.Sum64())
+		lastModified := modified.UTC().Truncate(time.Second)
+
+		validators := func(header http.Header) {
+			header.Set("ETag", etag)
+			header.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+			header.Set("Cache-Control", cacheControl)
+		}
+
+		if notModified(c.Request, etag, lastModified) {
+			validators(c.Writer.Header())
+			c.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+
+		c.Writer = &validatorWriter{ResponseWriter: c.Writer, validators: validators}
+		c.Next()
+	}
+}
+
+// notModified evaluates the request's preconditions; If-None-Match takes
+// precedence over If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := r.Header.Get("If-Modified-Since"); since != "" {
+		if t, err := http.ParseTime(since); err == nil {
+			return !lastModified.After(t)
+		}
+	}
+	return false
+}
+
+// validatorWriter adds the cache validators to successful responses only, so
+// that errors are never cached
+type validatorWriter struct {
+	gin.ResponseWriter
+	validators func(http.Header)
+}
+
+func (w *validatorWriter) WriteHeader(code int) {
+	if code == http.StatusOK {
+		w.validators(w.ResponseWriter.Header())
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
@@ -10,16 +10,23 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// SetupCORS configures CORS middleware for the Gin router
-func SetupCORS() gin.HandlerFunc {
-	return cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:4200", "http://127.0.0.1:4200", "https://localhost:4200"},
+// SetupCORS configures CORS middleware for the Gin router. The single origin
+// "*" allows every origin, without credentials.
+func SetupCORS(origins []string) gin.HandlerFunc {
+	config := cors.Config{
+		AllowOrigins:     origins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With"},
 		ExposeHeaders:    []string{"Content-Length", "X-Total-Count"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
-	})
+	}
+	if len(origins) == 1 && origins[0] == "*" {
+		config.AllowOrigins = nil
+		config.AllowAllOrigins = true
+		config.AllowCredentials = false
+	}
+	return cors.New(config)
 }
 
 // Logger middleware for request logging
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Response formats offered by ResponseFormat
+const (
+	FormatJSON    = "json"
+	FormatWookiee = "wookiee"
+)
+
+// FormatKey is the context key holding the negotiated response format
+const FormatKey = "responseFormat"
+
+// WookieeMediaType is the Accept media type that selects the Wookiee format
+const WookieeMediaType = "application/wookiee"
+
+// wookieeAlphabet is SWAPI's Wookiee translation of each lowercase letter
+var wookieeAlphabet = map[byte]string{
+	'a': "ra", 'b': "rh", 'c': "oa", 'd': "wa", 'e': "wo", 'f': "ww", 'g': "rr", 'h': "ac", 'i': "ah",
+	'j': "sh", 'k': "or", 'l': "an", 'm': "sc", 'n': "wh", 'o': "oo", 'p': "ak", 'q': "rq", 'r': "rc",
+	's': "c", 't': "ao", 'u': "hu", 'v': "ho", 'w': "oh", 'x': "k", 'y': "ro", 'z': "uf",
+}
+
+// ResponseFormat negotiates the response format from ?format=json|wookiee or
+// the Accept header and renders Wookiee responses the way SWAPI does. Unknown
+// formats are rejected with 400 and unacceptable Accept headers with 406.
+func ResponseFormat() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept")
+
+		format, status := negotiateFormat(c)
+		if status != http.StatusOK {
+			c.AbortWithStatusJSON(status, gin.H{
+				"error":   http.StatusText(status),
+				"message": `Supported formats are "json" and "wookiee" (application/json or ` + WookieeMediaType + `)`,
+			})
+			return
+		}
+		c.Set(FormatKey, format)
+
+		if format != FormatWookiee {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		c.Writer.Header().Del("Content-Length")
+		c.Writer.Write(EncodeWookiee(writer.body.Bytes()))
+	}
+}
+
+// negotiateFormat picks the response format, returning 200 or the error status
+func negotiateFormat(c *gin.Context) (string, int) {
+	if format, ok := c.GetQuery("format"); ok {
+		switch strings.ToLower(format) {
+		case FormatJSON, "":
+			return FormatJSON, http.StatusOK
+		case FormatWookiee:
+			return FormatWookiee, http.StatusOK
+		default:
+			return "", http.StatusBadRequest
+		}
+	}
+
+	accept := c.GetHeader("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return FormatJSON, http.StatusOK
+	}
+
+	// The most preferred supported media type wins; JSON wins ties
+	best, bestQuality := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			if name, value, found := strings.Cut(strings.TrimSpace(param), "="); found && strings.TrimSpace(name) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		var format string
+		switch mediaType {
+		case "application/json", "application/*", "*/*":
+			format = FormatJSON
+		case WookieeMediaType:
+			format = FormatWookiee
+		default:
+			continue
+		}
+
+		if quality > bestQuality || (quality == bestQuality && format == FormatJSON) {
+			best, bestQuality = format, quality
+		}
+	}
+
+	if best == "" || bestQuality <= 0 {
+		return "", http.StatusNotAcceptable
+	}
+	return best, http.StatusOK
+}
+
+// EncodeWookiee translates a rendered JSON document into Wookiee, letter by
+// letter, keys and literals included, as SWAPI does. Escape sequences are
+// kept intact so that strings stay well formed.
+func EncodeWookiee(data []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(data) * 2)
+
+	for i := 0; i < len(data); i++ {
+		char := data[i]
+		if char == '\\' && i+1 < len(data) {
+			end := i + 2
+			if data[i+1] == 'u' && i+6 <= len(data) {
+				end = i + 6
+			}
+			out.Write(data[i:end])
+			i = end - 1
+			continue
+		}
+
+		if translated, ok := wookieeAlphabet[char]; ok {
+			out.WriteString(translated)
+		} else {
+			out.WriteByte(char)
+		}
+	}
+	return out.Bytes()
+}
+
+// bufferedWriter holds the response body back so it can be re-encoded
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LanguageKey is the context key holding the negotiated content language
+const LanguageKey = "responseLanguage"
+
+// DefaultLanguage is the language of the untranslated content
+const DefaultLanguage = "en"
+
+// AvailableLanguagesFunc returns the languages content is translated into
+type AvailableLanguagesFunc func() ([]string, error)
+
+// Language negotiates the content language from ?lang= or the Accept-Language
+// header among the available translations. Anything else falls back on
+// English, as do untranslated fields.
+func Language(available AvailableLanguagesFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		languages, err := available()
+		if err != nil {
+			log.Printf("Error loading available languages: %v", err)
+		}
+		supported := map[string]bool{DefaultLanguage: true}
+		for _, language := range languages {
+			supported[language] = true
+		}
+
+		language := negotiateLanguage(c, supported)
+		c.Set(LanguageKey, language)
+		c.Header("Content-Language", language)
+		c.Next()
+	}
+}
+
+// negotiateLanguage picks the explicitly requested language if supported, or
+// else the most preferred supported language of Accept-Language
+func negotiateLanguage(c *gin.Context, supported map[string]bool) string {
+	if lang, ok := c.GetQuery("lang"); ok {
+		if language := PrimaryLanguage(lang); supported[language] {
+			return language
+		}
+		return DefaultLanguage
+	}
+
+	// Earlier languages win ties
+	best, bestQuality := DefaultLanguage, 0.0
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		params := strings.Split(part, ";")
+		language := PrimaryLanguage(params[0])
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			if name, value, found := strings.Cut(strings.TrimSpace(param), "="); found && strings.TrimSpace(name) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if supported[language] && quality > bestQuality {
+			best, bestQuality = language, quality
+		}
+	}
+	return best
+}
+
+// PrimaryLanguage reduces a language tag such as "uk-UA" to its lowercase
+// primary language subtag
+func PrimaryLanguage(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(tag)
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"starwars-api/models"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlayerKey and SessionKey are the context keys holding the authenticated
+// player and the ID of its session
+const (
+	PlayerKey  = "player"
+	SessionKey = "playerSession"
+)
+
+// PlayerAuthenticator verifies an access token and returns its player and
+// session ID
+type PlayerAuthenticator func(token string) (*models.Player, string, error)
+
+// PlayerAuth only lets through requests carrying a valid player access token
+// as a bearer token, and puts the player into the context
+func PlayerAuth(authenticate PlayerAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		var player *models.Player
+		var sessionID string
+		var err error
+		if found && token != "" {
+			player, sessionID, err = authenticate(token)
+		}
+		if player == nil || err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="player"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "Log in and send the access token as a bearer token",
+			})
+			return
+		}
+
+		c.Set(PlayerKey, player)
+		c.Set(SessionKey, sessionID)
+		c.Next()
+	}
+}
+
+// CurrentPlayer returns the player authenticated by PlayerAuth, or nil
+func CurrentPlayer(c *gin.Context) *models.Player {
+	player, _ := c.Get(PlayerKey)
+	current, _ := player.(*models.Player)
+	return current
+}
+
+// PlayerOwnsParam only lets the authenticated player through to routes whose
+// path parameter is its own player ID. It must run after PlayerAuth.
+func PlayerOwnsParam(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+			return
+		}
+		if player := CurrentPlayer(c); player == nil || player.ID != uint(id) {
+			AbortForbiddenPlayer(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// AbortForbiddenPlayer answers a request for another player's data
+func AbortForbiddenPlayer(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"error":   "Forbidden",
+		"message": "Players can only access their own data",
+	})
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"starwars-api/models"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PrincipalKey is the context key holding the Principal let through by
+// RoleAuth
+const PrincipalKey = "principal"
+
+// Kinds of principal
+const (
+	PrincipalPlayer = "player"
+	PrincipalAPIKey = "api_key"
+)
+
+// Principal is whoever called a privileged route: a player or the service
+// account of an API key
+type Principal struct {
+	Kind string
+	ID   uint
+	Name string
+	Role string
+}
+
+// APIKeyAuthenticator returns the API key matching key
+type APIKeyAuthenticator func(key string) (*models.APIKey, error)
+
+// AuditRecorder stores an audit entry
+type AuditRecorder func(entry *models.AuditEntry) error
+
+// RoleAuth guards privileged routes by role. It accepts player access tokens
+// and API keys as bearer tokens, and audits every request it sees.
+type RoleAuth struct {
+	players PlayerAuthenticator
+	apiKeys APIKeyAuthenticator
+	audit   AuditRecorder
+}
+
+func NewRoleAuth(players PlayerAuthenticator, apiKeys APIKeyAuthenticator, audit AuditRecorder) *RoleAuth {
+	return &RoleAuth{players: players, apiKeys: apiKeys, audit: audit}
+}
+
+// Require only lets through callers with one of roles
+func (a *RoleAuth) Require(roles ...string) gin.HandlerFunc {
+	required := strings.Join(roles, ",")
+
+	return func(c *gin.Context) {
+		entry := &models.AuditEntry{
+			RequiredRoles: required,
+			Method:        c.Request.Method,
+			Path:          c.Request.URL.Path,
+			ClientIP:      c.ClientIP(),
+		}
+
+		principal := a.authenticate(c)
+		if principal == nil {
+			c.Header("WWW-Authenticate", `Bearer realm="privileged"`)
+			a.deny(c, entry, http.StatusUnauthorized, "Unauthorized",
+				"Send a player access token or an API key as a bearer token", "unauthenticated")
+			return
+		}
+		entry.ActorType = principal.Kind
+		entry.ActorID = &principal.ID
+		entry.ActorName = principal.Name
+		entry.Role = principal.Role
+
+		if !hasRole(principal.Role, roles) {
+			a.deny(c, entry, http.StatusForbidden, "Forbidden",
+				"This route requires the role "+strings.Join(roles, " or "), "role "+principal.Role+" not allowed")
+			return
+		}
+
+		c.Set(PrincipalKey, principal)
+		c.Next()
+
+		entry.Outcome = models.AuditAllowed
+		entry.Status = c.Writer.Status()
+		a.record(entry)
+	}
+}
+
+// authenticate returns the caller of the request, or nil when its bearer
+// token is missing or invalid. Players are also put into the context, as by
+// PlayerAuth.
+func (a *RoleAuth) authenticate(c *gin.Context) *Principal {
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(token, models.APIKeyPrefix) {
+		key, err := a.apiKeys(token)
+		if key == nil || err != nil {
+			return nil
+		}
+		return &Principal{Kind: PrincipalAPIKey, ID: key.ID, Name: key.Name, Role: key.Role}
+	}
+
+	player, sessionID, err := a.players(token)
+	if player == nil || err != nil {
+		return nil
+	}
+	c.Set(PlayerKey, player)
+	c.Set(SessionKey, sessionID)
+	return &Principal{Kind: PrincipalPlayer, ID: player.ID, Name: player.Username, Role: player.Role}
+}
+
+// deny answers and audits a request that may not use the route
+func (a *RoleAuth) deny(c *gin.Context, entry *models.AuditEntry, status int, title, message, reason string) {
+	c.AbortWithStatusJSON(status, gin.H{"error": title, "message": message})
+
+	entry.Outcome = models.AuditDenied
+	entry.Status = status
+	entry.Reason = reason
+	a.record(entry)
+}
+
+// record stores an audit entry; a failure is logged rather than failing a
+// request that has already been answered
+func (a *RoleAuth) record(entry *models.AuditEntry) {
+	if err := a.audit(entry); err != nil {
+		log.Printf("⚠️  %v: %s %s by %s", err, entry.Method, entry.Path, entry.ActorName)
+	}
+}
+
+// CurrentPrincipal returns the caller let through by RoleAuth, or nil
+func CurrentPrincipal(c *gin.Context) *Principal {
+	principal, _ := c.Get(PrincipalKey)
+	current, _ := principal.(*Principal)
+	return current
+}
+
+func hasRole(role string, roles []string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -194,6 +194,6 @@ type AchievementLeaderboard struct {
 	MonthlyPoints int       `json:"monthly_points" gorm:"default:0"`
 
 	// Special achievements
-	FirstToUnlock  []string `json:"first_to_unlock" gorm:"type:json"`   // Achievement IDs where player was first
-	CompletionRate float64  `json:"completion_rate" gorm:"default:0.0"` // Percentage of all achievements unlocked
+	FirstToUnlock  []string `json:"first_to_unlock" gorm:"type:json;serializer:json"` // Achievement IDs where player was first
+	CompletionRate float64  `json:"completion_rate" gorm:"default:0.0"`               // Percentage of all achievements unlocked
 }
@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// PlayerSession is a login of a player. Its refresh token, stored hashed,
+// issues new access tokens until the session expires or is revoked.
+type PlayerSession struct {
+	ID               string     `json:"id" gorm:"primaryKey"`
+	PlayerID         uint       `json:"player_id" gorm:"not null;index"`
+	RefreshTokenHash string     `json:"-" gorm:"not null"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Roles of players and API keys; RolePlayer is the default and grants no
+// privileged routes
+const (
+	RolePlayer    = "player"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Roles lists every role
+var Roles = []string{RolePlayer, RoleModerator, RoleAdmin}
+
+// ValidRole reports whether role is one of Roles
+func ValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyPrefix starts every API key, telling them apart from player tokens
+const APIKeyPrefix = "swk_"
+
+// APIKey authenticates a service account with a role. Only a hash of the key
+// is stored; Prefix identifies it in listings.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Name       string     `json:"name" gorm:"not null"`
+	Prefix     string     `json:"prefix" gorm:"not null"`
+	KeyHash    string     `json:"-" gorm:"uniqueIndex;not null"`
+	Role       string     `json:"role" gorm:"not null"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Outcomes of an audited request
+const (
+	AuditAllowed = "allowed"
+	AuditDenied  = "denied"
+)
+
+// AuditEntry records a request to a privileged route, whether it was let
+// through or denied
+type AuditEntry struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	ActorType     string    `json:"actor_type"` // player, api_key, or empty when unauthenticated
+	ActorID       *uint     `json:"actor_id" gorm:"index"`
+	ActorName     string    `json:"actor_name"`
+	Role          string    `json:"role"`
+	RequiredRoles string    `json:"required_roles"`
+	Method        string    `json:"method"`
+	Path          string    `json:"path"`
+	Status        int       `json:"status"`
+	Outcome       string    `json:"outcome" gorm:"index"`
+	Reason        string    `json:"reason"`
+	ClientIP      string    `json:"client_ip"`
+	CreatedAt     time.Time `json:"created_at" gorm:"index"`
+}
+
+// Purposes of an email token
+const (
+	EmailTokenVerify = "verify_email"
+	EmailTokenReset  = "reset_password"
+)
+
+// EmailToken is a single-use token sent by email, to verify an address or
+// reset a password. Only its hash is stored.
+type EmailToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	PlayerID  uint       `json:"player_id" gorm:"not null;index"`
+	Purpose   string     `json:"purpose" gorm:"not null"`
+	Email     string     `json:"email" gorm:"not null"` // the address the token was sent to
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
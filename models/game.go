@@ -5,17 +5,22 @@ import (
 	"time"
 )
 
-// Player представляє гравця в грі
+// Player представляє гравця в грі. Рівень, досвід і валюти лише для читання:
+// їх змінює ProgressionService разом із записом у журналі (LedgerEntry).
 type Player struct {
-	ID         uint      `json:"id" gorm:"primaryKey"`
-	Username   string    `json:"username" gorm:"uniqueIndex;not null"`
-	Email      string    `json:"email" gorm:"index"`
-	Level      int       `json:"level" gorm:"default:1"`
-	Experience int       `json:"experience" gorm:"default:0"`
-	Credits    int       `json:"credits" gorm:"default:100"`
-	Avatar     string    `json:"avatar"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Username        string     `json:"username" gorm:"uniqueIndex;not null"`
+	Email           string     `json:"email" gorm:"index"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	PasswordHash    string     `json:"-"` // bcrypt
+	Role            string     `json:"role" gorm:"not null;default:player"`
+	Level           int        `json:"level" gorm:"->;default:1"`
+	Experience      int        `json:"experience" gorm:"->;default:0"`
+	Credits         int        `json:"credits" gorm:"->;default:100"`
+	Crystals        int        `json:"crystals" gorm:"->;default:0"`
+	Avatar          string     `json:"avatar"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 // PlayerStats представляє статистику гравця
@@ -42,7 +47,7 @@ type QuizQuestion struct {
 	Category      string `json:"category" gorm:"not null"` // characters, planets, starships, etc.
 	Question      string `json:"question" gorm:"not null"`
 	CorrectAnswer string `json:"correct_answer" gorm:"not null"`
-	WrongAnswers  string `json:"wrong_answers" gorm:"type:json"` // JSON array
+	WrongAnswers  string `json:"wrong_answers" gorm:"type:text"` // JSON array
 	Difficulty    int    `json:"difficulty" gorm:"default:1"`    // 1-easy, 2-medium, 3-hard
 	Points        int    `json:"points" gorm:"default:10"`
 	Hint          string `json:"hint"`
@@ -74,7 +79,7 @@ type GameSession struct {
 	PlayerID    uint       `json:"player_id" gorm:"not null"`
 	GameType    string     `json:"game_type" gorm:"not null"` // quiz, battle, exploration
 	Score       int        `json:"score" gorm:"default:0"`
-	Data        string     `json:"data" gorm:"type:json"` // JSON data specific to game type
+	Data        string     `json:"data" gorm:"type:text"` // JSON data specific to game type
 	StartedAt   time.Time  `json:"started_at"`
 	CompletedAt *time.Time `json:"completed_at"`
 	Player      Player     `json:"player" gorm:"foreignKey:PlayerID"`
@@ -90,8 +95,8 @@ type GameCard struct {
 	ImageURL         string `json:"image_url"`
 	Rarity           string `json:"rarity" gorm:"default:'common'"` // common, rare, epic, legendary
 	PowerLevel       int    `json:"power_level" gorm:"default:50"`
-	SpecialAbilities string `json:"special_abilities" gorm:"type:json"` // JSON array
-	Stats            string `json:"stats" gorm:"type:json"`             // JSON object with attack, defense, etc.
+	SpecialAbilities string `json:"special_abilities" gorm:"type:text"` // JSON array
+	Stats            string `json:"stats" gorm:"type:text"`             // JSON object with attack, defense, etc.
 }
 
 // GetSpecialAbilitiesArray повертає спеціальні здібності як масив
@@ -169,7 +174,7 @@ type Artifact struct {
 	Description string `json:"description"`
 	Type        string `json:"type" gorm:"not null"` // weapon, technology, force, knowledge
 	Rarity      string `json:"rarity" gorm:"default:'common'"`
-	Effect      string `json:"effect" gorm:"type:json"` // JSON object with effect details
+	Effect      string `json:"effect" gorm:"type:text"` // JSON object with effect details
 	ImageURL    string `json:"image_url"`
 }
 
@@ -21,17 +21,17 @@ type Mission struct {
 	Era        string   `json:"era"` // prequel, original, sequel, high_republic, old_republic
 	Planet     string   `json:"planet"`
 	Faction    string   `json:"faction"` // rebel, empire, republic, separatist, neutral
-	Characters []string `json:"characters" gorm:"type:json"`
+	Characters []string `json:"characters" gorm:"type:json;serializer:json"`
 
 	// Prerequisites
-	RequiredMissions []int    `json:"required_missions" gorm:"type:json"`
+	RequiredMissions []int    `json:"required_missions" gorm:"type:json;serializer:json"`
 	RequiredLevel    int      `json:"required_level"`
-	RequiredItems    []string `json:"required_items" gorm:"type:json"`
+	RequiredItems    []string `json:"required_items" gorm:"type:json;serializer:json"`
 
 	// Rewards
 	ExperienceReward int      `json:"experience_reward"`
 	CreditsReward    int      `json:"credits_reward"`
-	ItemRewards      []string `json:"item_rewards" gorm:"type:json"`
+	ItemRewards      []string `json:"item_rewards" gorm:"type:json;serializer:json"`
 
 	// Mission state
 	IsActive      bool `json:"is_active"`
@@ -105,7 +105,7 @@ type MissionProgress struct {
 	// Rewards received
 	ExperienceEarned int      `json:"experience_earned"`
 	CreditsEarned    int      `json:"credits_earned"`
-	ItemsEarned      []string `json:"items_earned" gorm:"type:json"`
+	ItemsEarned      []string `json:"items_earned" gorm:"type:json;serializer:json"`
 
 	// Replay data
 	PlayCount  int `json:"play_count"`
@@ -132,13 +132,13 @@ type Environment3D struct {
 
 	// Weather and effects
 	WeatherType     string   `json:"weather_type"`
-	ParticleEffects []string `json:"particle_effects" gorm:"type:json"`
+	ParticleEffects []string `json:"particle_effects" gorm:"type:json;serializer:json"`
 	FogDensity      float64  `json:"fog_density"` // Fog density 0.0-1.0
 	FogColor        string   `json:"fog_color"`   // Hex color for fog
 
 	// Audio environment
 	MusicTrack   string   `json:"music_track"`
-	SoundEffects []string `json:"sound_effects" gorm:"type:json"`
+	SoundEffects []string `json:"sound_effects" gorm:"type:json;serializer:json"`
 	AmbientSound string   `json:"ambient_sound"` // Path to ambient audio file
 }
 
@@ -154,12 +154,12 @@ type MissionTemplate struct {
 	ID           int                    `json:"id" gorm:"primaryKey"`
 	Name         string                 `json:"name"`
 	Type         string                 `json:"type"`
-	TemplateData map[string]interface{} `json:"template_data" gorm:"type:json"`
+	TemplateData map[string]interface{} `json:"template_data" gorm:"type:json;serializer:json"`
 
 	// Bright Data source
 	SourceType    string                 `json:"source_type"` // wookieepedia, starwars_com, fan_site
 	SourceURL     string                 `json:"source_url"`
-	ScrapingRules map[string]interface{} `json:"scraping_rules" gorm:"type:json"`
+	ScrapingRules map[string]interface{} `json:"scraping_rules" gorm:"type:json;serializer:json"`
 
 	IsActive  bool      `json:"is_active"`
 	CreatedAt time.Time `json:"created_at"`
@@ -172,7 +172,7 @@ type MissionEvent struct {
 	MissionID int                    `json:"mission_id"`
 	PlayerID  int                    `json:"player_id"`
 	EventType string                 `json:"event_type"` // objective_completed, death, item_collected, enemy_defeated
-	EventData map[string]interface{} `json:"event_data" gorm:"type:json"`
+	EventData map[string]interface{} `json:"event_data" gorm:"type:json;serializer:json"`
 	Timestamp time.Time              `json:"timestamp"`
 
 	CreatedAt time.Time `json:"created_at"`
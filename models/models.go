@@ -19,6 +19,10 @@ type Character struct {
 	CreatedAt time.Time `json:"created"`
 	UpdatedAt time.Time `json:"edited"`
 
+	// Numeric values parsed from the free-text fields, null when unknown
+	HeightValue *float64 `json:"-"`
+	MassValue   *float64 `json:"-"`
+
 	// Many-to-many relationships
 	Films     []Film     `json:"films" gorm:"many2many:character_films;"`
 	Species   []Species  `json:"species" gorm:"many2many:character_species;"`
@@ -89,11 +93,16 @@ type Starship struct {
 	CreatedAt            time.Time `json:"created"`
 	UpdatedAt            time.Time `json:"updated"`
 
+	// Numeric values parsed from the free-text fields, null when unknown
+	CostInCreditsValue *float64 `json:"-"`
+	LengthValue        *float64 `json:"-"`
+	CargoCapacityValue *float64 `json:"-"`
+
 	// Enhanced data from Bright Data MCP (Wookieepedia scraping)
 	TechnicalSpecs *StarshipTechnicalSpecs `json:"technical_specs" gorm:"embedded"`
 	PhysicsConfig  *StarshipPhysicsConfig  `json:"physics_config" gorm:"embedded"`
 	Model3DConfig  *Model3DConfig          `json:"model_3d_config" gorm:"embedded"`
-	GameplayStats  *GameplayStats          `json:"gameplay_stats" gorm:"embedded"`
+	GameplayStats  *GameplayStats          `json:"gameplay_stats" gorm:"embedded;embeddedPrefix:gameplay_"` // prefixed, as both blocks have an agility
 	WeaponSystems  []WeaponSystem          `json:"weapon_systems" gorm:"foreignKey:StarshipID"`
 
 	// Many-to-many relationships
@@ -140,6 +149,10 @@ type Planet struct {
 	CreatedAt      time.Time `json:"created"`
 	UpdatedAt      time.Time `json:"edited"`
 
+	// Numeric values parsed from the free-text fields, null when unknown
+	DiameterValue   *float64 `json:"-"`
+	PopulationValue *float64 `json:"-"`
+
 	// Enhanced data from Bright Data MCP (Wookieepedia scraping)
 	PlanetSpecs   *PlanetSpecs    `json:"planet_specs" gorm:"embedded"`
 	Environment3D *Environment3D  `json:"environment_3d" gorm:"embedded"`
@@ -207,6 +220,19 @@ type Event struct {
 	Films        []Film      `json:"films" gorm:"many2many:film_events;"`
 }
 
+// Translation holds a field of a catalog entity or quiz question in another
+// language than English
+type Translation struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	EntityType string    `json:"entity_type" gorm:"not null;uniqueIndex:idx_translation_key,priority:1"` // people, films, ..., quiz_questions
+	Language   string    `json:"language" gorm:"not null;uniqueIndex:idx_translation_key,priority:2"`    // e.g. uk
+	EntityID   uint      `json:"entity_id" gorm:"not null;uniqueIndex:idx_translation_key,priority:3"`
+	Field      string    `json:"field" gorm:"not null;uniqueIndex:idx_translation_key,priority:4"` // API field name, e.g. opening_crawl
+	Value      string    `json:"value" gorm:"type:text;not null"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
 // PaginatedResponse represents a paginated API response
 type PaginatedResponse struct {
 	Count    int64       `json:"count"`
@@ -293,8 +319,8 @@ type GameplayStats struct {
 	MaxLevel    int    `json:"max_level"`    // Maximum upgrade level
 
 	// Special abilities
-	SpecialAbilities []string `json:"special_abilities"` // List of special abilities
-	Faction          string   `json:"faction"`           // "rebel", "empire", "neutral"
+	SpecialAbilities []string `json:"special_abilities" gorm:"serializer:json"` // List of special abilities
+	Faction          string   `json:"faction"`                                  // "rebel", "empire", "neutral"
 }
 
 // WeaponSystem represents a starship's weapon system
@@ -339,19 +365,19 @@ type PlanetSpecs struct {
 // PlanetGameplay contains game-specific planet data
 type PlanetGameplay struct {
 	// Exploration mechanics
-	ExplorationDifficulty int      `json:"exploration_difficulty"` // 1-10 difficulty rating
-	ResourceTypes         []string `json:"resource_types"`         // ["crystals", "metals", "energy"]
-	ResourceAbundance     string   `json:"resource_abundance"`     // "scarce", "moderate", "abundant"
+	ExplorationDifficulty int      `json:"exploration_difficulty"`                // 1-10 difficulty rating
+	ResourceTypes         []string `json:"resource_types" gorm:"serializer:json"` // ["crystals", "metals", "energy"]
+	ResourceAbundance     string   `json:"resource_abundance"`                    // "scarce", "moderate", "abundant"
 
 	// Hazards and challenges
-	EnvironmentalHazards []string `json:"environmental_hazards"` // ["sandstorm", "extreme_cold", "radiation"]
-	HostileCreatures     []string `json:"hostile_creatures"`     // ["tusken_raiders", "wampa", "sarlacc"]
-	ImperialPresence     string   `json:"imperial_presence"`     // "none", "light", "moderate", "heavy"
+	EnvironmentalHazards []string `json:"environmental_hazards" gorm:"serializer:json"` // ["sandstorm", "extreme_cold", "radiation"]
+	HostileCreatures     []string `json:"hostile_creatures" gorm:"serializer:json"`     // ["tusken_raiders", "wampa", "sarlacc"]
+	ImperialPresence     string   `json:"imperial_presence"`                            // "none", "light", "moderate", "heavy"
 
 	// Missions and quests
-	AvailableMissions  []string `json:"available_missions"`  // Mission types available
-	UnlockRequirements []string `json:"unlock_requirements"` // Requirements to access planet
-	CompletionRewards  []string `json:"completion_rewards"`  // Rewards for planet completion
+	AvailableMissions  []string `json:"available_missions" gorm:"serializer:json"`  // Mission types available
+	UnlockRequirements []string `json:"unlock_requirements" gorm:"serializer:json"` // Requirements to access planet
+	CompletionRewards  []string `json:"completion_rewards" gorm:"serializer:json"`  // Rewards for planet completion
 
 	// Strategic value
 	StrategicImportance int    `json:"strategic_importance"` // 1-10 importance rating
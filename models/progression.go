@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Currencies of the progression ledger. Experience is kept like a currency
+// that is never spent.
+const (
+	CurrencyExperience = "experience"
+	CurrencyCredits    = "credits"
+	CurrencyCrystals   = "crystals"
+)
+
+// Sources of ledger entries
+const (
+	SourceRegistration   = "registration"
+	SourceOpeningBalance = "opening_balance"
+	SourceAdmin          = "admin"
+	SourceLevelUp        = "level_up"
+	SourceQuiz           = "quiz"
+	SourceMission        = "mission"
+	SourceBattle         = "battle"
+	SourceAchievement    = "achievement"
+	SourceGeneration     = "generation"
+	SourceConversion     = "conversion"
+	SourceUpgrade        = "upgrade"
+	SourcePurchase       = "purchase"
+)
+
+// LedgerEntry is one change of a player's experience or of one of its
+// currencies. The balances on Player are only changed together with an
+// entry, so the ledger is their full history.
+type LedgerEntry struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	PlayerID          uint      `json:"player_id" gorm:"not null;index"`
+	Currency          string    `json:"currency" gorm:"not null"`
+	Amount            int       `json:"amount"`  // positive for gains, negative for spending
+	Balance           int       `json:"balance"` // balance of the currency after the change
+	Source            string    `json:"source" gorm:"not null"`
+	Description       string    `json:"description"`
+	RelatedEntityType string    `json:"related_entity_type"` // quiz_session, mission, battle, achievement...
+	RelatedEntityID   string    `json:"related_entity_id"`
+	CreatedAt         time.Time `json:"created_at" gorm:"index"`
+}
+
+// LevelUp is a level reached by a player and the rewards that came with it
+type LevelUp struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	PlayerID       uint      `json:"player_id" gorm:"not null;uniqueIndex:idx_level_ups_player_level"`
+	Level          int       `json:"level" gorm:"not null;uniqueIndex:idx_level_ups_player_level"`
+	Experience     int       `json:"experience"` // experience of the player after the change that reached the level
+	Source         string    `json:"source"`     // source of that change, one of the Source constants
+	Credits        int       `json:"credits"`
+	Durasteel      int       `json:"durasteel"`
+	Transparisteel int       `json:"transparisteel"`
+	Tibanna        int       `json:"tibanna"`
+	Kyber          int       `json:"kyber"`
+	HangarSlots    int       `json:"hangar_slots"`
+	UnlockedShips  []string  `json:"unlocked_ships" gorm:"serializer:json"` // names of the ship templates that require the level
+	CreatedAt      time.Time `json:"created_at" gorm:"index"`
+}
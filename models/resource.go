@@ -15,10 +15,10 @@ type PlayerResources struct {
 	// Player reference
 	PlayerID uint `json:"player_id" gorm:"not null;uniqueIndex"`
 
-	// Primary currencies
-	Credits    int `json:"credits" gorm:"default:1000"` // Main currency
-	Crystals   int `json:"crystals" gorm:"default:0"`   // Premium currency
-	Experience int `json:"experience" gorm:"default:0"` // Player experience
+	// Primary currencies, read from the player's balances
+	Credits    int `json:"credits" gorm:"-"`    // Main currency
+	Crystals   int `json:"crystals" gorm:"-"`   // Premium currency
+	Experience int `json:"experience" gorm:"-"` // Player experience
 
 	// Materials for ship construction/upgrades
 	Durasteel      int `json:"durasteel" gorm:"default:0"`      // Basic hull material
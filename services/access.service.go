@@ -0,0 +1,160 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"starwars-api/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRole     = fmt.Errorf("role must be one of %v", models.Roles)
+	ErrPlayerNotFound  = errors.New("player not found")
+	ErrAPIKeyNotFound  = errors.New("API key not found")
+	ErrAPIKeyNameEmpty = errors.New("API key name is required")
+)
+
+// AccessService manages player roles, service account API keys and the audit
+// log of privileged routes
+type AccessService struct {
+	db *gorm.DB
+}
+
+func NewAccessService(db *gorm.DB) *AccessService {
+	return &AccessService{db: db}
+}
+
+// SetPlayerRole changes the role of a player
+func (s *AccessService) SetPlayerRole(playerID uint, role string) (*models.Player, error) {
+	if !models.ValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+
+	var player models.Player
+	if err := s.db.First(&player, playerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlayerNotFound
+		}
+		return nil, fmt.Errorf("failed to load player: %w", err)
+	}
+
+	player.Role = role
+	if err := s.db.Model(&player).Update("role", role).Error; err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+	return &player, nil
+}
+
+// FindPlayerID returns the ID of the player with a username
+func (s *AccessService) FindPlayerID(username string) (uint, error) {
+	var player models.Player
+	if err := s.db.Select("id").Where("username = ?", username).First(&player).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrPlayerNotFound
+		}
+		return 0, fmt.Errorf("failed to load player: %w", err)
+	}
+	return player.ID, nil
+}
+
+// CreateAPIKey creates an API key for a service account and returns it along
+// with the key itself, which is not stored and cannot be shown again
+func (s *AccessService) CreateAPIKey(name, role string) (*models.APIKey, string, error) {
+	if name == "" {
+		return nil, "", ErrAPIKeyNameEmpty
+	}
+	if !models.ValidRole(role) {
+		return nil, "", ErrInvalidRole
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := models.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	apiKey := models.APIKey{
+		Name:    name,
+		Prefix:  key[:len(models.APIKeyPrefix)+6],
+		KeyHash: hashToken(key),
+		Role:    role,
+	}
+	if err := s.db.Create(&apiKey).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to create API key: %w", err)
+	}
+	return &apiKey, key, nil
+}
+
+// ListAPIKeys returns every API key, revoked ones included
+func (s *AccessService) ListAPIKeys() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := s.db.Order("id").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey stops an API key from authenticating
+func (s *AccessService) RevokeAPIKey(id uint) error {
+	result := s.db.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke API key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// AuthenticateAPIKey returns the unrevoked API key matching key
+func (s *AccessService) AuthenticateAPIKey(key string) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	err := s.db.Where("key_hash = ? AND revoked_at IS NULL", hashToken(key)).First(&apiKey).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to load API key: %w", err)
+	}
+
+	now := time.Now()
+	apiKey.LastUsedAt = &now
+	if err := s.db.Model(&apiKey).UpdateColumn("last_used_at", now).Error; err != nil {
+		return nil, fmt.Errorf("failed to update API key: %w", err)
+	}
+	return &apiKey, nil
+}
+
+// RecordAudit appends an entry to the audit log
+func (s *AccessService) RecordAudit(entry *models.AuditEntry) error {
+	if err := s.db.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEntries returns a page of the audit log, newest first, optionally
+// only entries with an outcome
+func (s *AccessService) ListAuditEntries(outcome string, limit, offset int) ([]models.AuditEntry, int64, error) {
+	query := s.db.Model(&models.AuditEntry{})
+	if outcome != "" {
+		query = query.Where("outcome = ?", outcome)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	var entries []models.AuditEntry
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"starwars-api/config"
+	"starwars-api/mailer"
+	"starwars-api/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNoEmail              = errors.New("player has no email address")
+	ErrInvalidEmail         = errors.New("email address is invalid")
+	ErrEmailAlreadyVerified = errors.New("email address is already verified")
+)
+
+// EmailRateLimitError is returned when an email of a kind was sent to a
+// player too recently to send another
+type EmailRateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *EmailRateLimitError) Error() string {
+	return fmt.Sprintf("an email was sent recently; try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// AccountService verifies player email addresses and resets forgotten
+// passwords with tokens sent by email
+type AccountService struct {
+	db               *gorm.DB
+	authService      *AuthService
+	mailer           mailer.Mailer
+	verificationTTL  time.Duration
+	resetTTL         time.Duration
+	resendInterval   time.Duration
+	resetPasswordURL string
+	verifyEmailURL   string
+}
+
+func NewAccountService(db *gorm.DB, authService *AuthService, m mailer.Mailer, auth config.AuthConfig, mailConfig config.MailConfig, verifyEmailURL string) *AccountService {
+	return &AccountService{
+		db:               db,
+		authService:      authService,
+		mailer:           m,
+		verificationTTL:  time.Duration(auth.EmailVerificationTTL),
+		resetTTL:         time.Duration(auth.PasswordResetTTL),
+		resendInterval:   time.Duration(auth.EmailResendInterval),
+		resetPasswordURL: mailConfig.ResetPasswordURL,
+		verifyEmailURL:   verifyEmailURL,
+	}
+}
+
+// SendVerification emails a player a link that verifies its email address
+func (s *AccountService) SendVerification(player *models.Player) error {
+	if player.Email == "" {
+		return ErrNoEmail
+	}
+	if player.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, err := s.issueToken(player, models.EmailTokenVerify, s.verificationTTL)
+	if err != nil {
+		return err
+	}
+	return s.send("verify_email", player, withToken(s.verifyEmailURL, token), s.verificationTTL)
+}
+
+// VerifyEmail marks the address a verification token was sent to as verified,
+// unless the player has changed its address since
+func (s *AccountService) VerifyEmail(token string) (*models.Player, error) {
+	var player models.Player
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		emailToken, err := s.useToken(tx, token, models.EmailTokenVerify)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.First(&player, emailToken.PlayerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("failed to load player: %w", err)
+		}
+		if !strings.EqualFold(player.Email, emailToken.Email) {
+			return ErrInvalidToken
+		}
+
+		now := time.Now()
+		player.EmailVerifiedAt = &now
+		if err := tx.Model(&player).Update("email_verified_at", now).Error; err != nil {
+			return fmt.Errorf("failed to verify email: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &player, nil
+}
+
+// RequestPasswordReset emails a reset link to every player with this verified
+// address. It reports no error for unknown addresses or recently sent emails,
+// so that callers cannot learn which addresses have accounts.
+func (s *AccountService) RequestPasswordReset(email string) error {
+	var players []models.Player
+	err := s.db.Where("LOWER(email) = ? AND email_verified_at IS NOT NULL", strings.ToLower(email)).
+		Find(&players).Error
+	if err != nil {
+		return fmt.Errorf("failed to load players: %w", err)
+	}
+
+	for i := range players {
+		token, err := s.issueToken(&players[i], models.EmailTokenReset, s.resetTTL)
+		var rateLimited *EmailRateLimitError
+		if errors.As(err, &rateLimited) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.send("reset_password", &players[i], withToken(s.resetPasswordURL, token), s.resetTTL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token, ending every session
+// of the player
+func (s *AccountService) ResetPassword(token, password string) error {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return ErrInvalidPassword
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		emailToken, err := s.useToken(tx, token, models.EmailTokenReset)
+		if err != nil {
+			return err
+		}
+		return s.authService.SetPassword(tx, emailToken.PlayerID, password)
+	})
+}
+
+// issueToken creates a token of a purpose for the player's address and
+// replaces the unused ones, unless one was issued within the resend interval
+func (s *AccountService) issueToken(player *models.Player, purpose string, ttl time.Duration) (string, error) {
+	if _, err := mail.ParseAddress(player.Email); err != nil {
+		return "", ErrInvalidEmail
+	}
+
+	var last models.EmailToken
+	err := s.db.Where("player_id = ? AND purpose = ?", player.ID, purpose).Order("created_at DESC").First(&last).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", fmt.Errorf("failed to load email tokens: %w", err)
+	}
+	if err == nil {
+		if wait := s.resendInterval - time.Since(last.CreatedAt); wait > 0 {
+			return "", &EmailRateLimitError{RetryAfter: wait}
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate email token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("player_id = ? AND purpose = ? AND used_at IS NULL", player.ID, purpose).
+			Delete(&models.EmailToken{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to replace email tokens: %w", err)
+		}
+		emailToken := models.EmailToken{
+			PlayerID:  player.ID,
+			Purpose:   purpose,
+			Email:     player.Email,
+			TokenHash: hashToken(token),
+			ExpiresAt: time.Now().Add(ttl),
+		}
+		if err := tx.Create(&emailToken).Error; err != nil {
+			return fmt.Errorf("failed to create email token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// useToken marks an unused, unexpired token of a purpose as used
+func (s *AccountService) useToken(tx *gorm.DB, token, purpose string) (*models.EmailToken, error) {
+	var emailToken models.EmailToken
+	err := tx.Where("token_hash = ? AND purpose = ?", hashToken(token), purpose).First(&emailToken).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to load email token: %w", err)
+	}
+	if emailToken.UsedAt != nil || time.Now().After(emailToken.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	// Only one of two concurrent uses of a token updates it
+	result := tx.Model(&emailToken).Where("used_at IS NULL").Update("used_at", time.Now())
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to use email token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidToken
+	}
+	return &emailToken, nil
+}
+
+// send renders an email template with a link and sends it to the player
+func (s *AccountService) send(template string, player *models.Player, link string, ttl time.Duration) error {
+	message, err := mailer.Render(template, player.Email, map[string]interface{}{
+		"Username":  player.Username,
+		"Email":     player.Email,
+		"Link":      link,
+		"ExpiresIn": humanDuration(ttl),
+	})
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(message)
+}
+
+// withToken adds a token to the query of a link
+func withToken(link, token string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return link + "?token=" + url.QueryEscape(token)
+	}
+	query := parsed.Query()
+	query.Set("token", token)
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// humanDuration writes a token lifetime in whole hours or minutes
+func humanDuration(d time.Duration) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return "1 " + unit
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	if d >= time.Hour && d%time.Hour == 0 {
+		return plural(int(d/time.Hour), "hour")
+	}
+	return plural(max(int(d/time.Minute), 1), "minute")
+}
@@ -10,14 +10,14 @@ import (
 )
 
 type AchievementService struct {
-	db              *gorm.DB
-	resourceService *ResourceService
+	db          *gorm.DB
+	progression *ProgressionService
 }
 
-func NewAchievementService(db *gorm.DB, resourceService *ResourceService) *AchievementService {
+func NewAchievementService(db *gorm.DB, progression *ProgressionService) *AchievementService {
 	return &AchievementService{
-		db:              db,
-		resourceService: resourceService,
+		db:          db,
+		progression: progression,
 	}
 }
 
@@ -80,9 +80,31 @@ func (s *AchievementService) UpdateProgress(playerID uint, achievementName strin
 		return fmt.Errorf("achievement not found: %w", err)
 	}
 
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.updateProgress(tx, playerID, &achievement, progressValue)
+	})
+}
+
+// ReachLevel advances the player_level achievements on a level_up event
+func (s *AchievementService) ReachLevel(tx *gorm.DB, event LevelUpEvent) error {
+	var achievements []models.Achievement
+	if err := tx.Where("condition = ? AND is_active = ?", "player_level", true).Find(&achievements).Error; err != nil {
+		return fmt.Errorf("failed to get level achievements: %w", err)
+	}
+
+	for i := range achievements {
+		if err := s.updateProgress(tx, event.LevelUp.PlayerID, &achievements[i], event.LevelUp.Level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateProgress updates a player's progress on an achievement in tx
+func (s *AchievementService) updateProgress(tx *gorm.DB, playerID uint, achievement *models.Achievement, progressValue int) error {
 	// Get player achievement progress
 	var playerAchievement models.PlayerAchievement
-	err := s.db.Where("player_id = ? AND achievement_id = ?", playerID, achievement.ID).First(&playerAchievement).Error
+	err := tx.Where("player_id = ? AND achievement_id = ?", playerID, achievement.ID).First(&playerAchievement).Error
 
 	if err == gorm.ErrRecordNotFound {
 		// Create new progress record
@@ -98,7 +120,7 @@ func (s *AchievementService) UpdateProgress(playerID uint, achievementName strin
 			RewardsClaimed:  false,
 		}
 
-		if err := s.db.Create(&playerAchievement).Error; err != nil {
+		if err := tx.Create(&playerAchievement).Error; err != nil {
 			return fmt.Errorf("failed to create player achievement: %w", err)
 		}
 	} else if err != nil {
@@ -143,6 +165,7 @@ func (s *AchievementService) UpdateProgress(playerID uint, achievementName strin
 
 	// Check if achievement is unlocked
 	wasUnlocked := playerAchievement.IsUnlocked
+	timesUnlocked := playerAchievement.TimesToUnlock
 	if playerAchievement.CurrentProgress >= playerAchievement.TargetProgress {
 		if !wasUnlocked || achievement.IsRepeatable {
 			playerAchievement.IsUnlocked = true
@@ -153,11 +176,6 @@ func (s *AchievementService) UpdateProgress(playerID uint, achievementName strin
 				playerAchievement.UnlockedAt = &now
 			}
 
-			// Award rewards
-			if err := s.awardAchievementRewards(playerID, &achievement); err != nil {
-				return fmt.Errorf("failed to award achievement rewards: %w", err)
-			}
-
 			// Reset progress for repeatable achievements
 			if achievement.IsRepeatable && wasUnlocked {
 				playerAchievement.CurrentProgress = 0
@@ -168,10 +186,18 @@ func (s *AchievementService) UpdateProgress(playerID uint, achievementName strin
 	}
 
 	// Save progress
-	if err := s.db.Save(&playerAchievement).Error; err != nil {
+	if err := tx.Save(&playerAchievement).Error; err != nil {
 		return fmt.Errorf("failed to save player achievement: %w", err)
 	}
 
+	// Award rewards once the progress is saved, as their experience may level
+	// the player up and advance achievements again
+	if playerAchievement.TimesToUnlock > timesUnlocked {
+		if err := s.awardAchievementRewards(tx, playerID, achievement); err != nil {
+			return fmt.Errorf("failed to award achievement rewards: %w", err)
+		}
+	}
+
 	// Create progress record for detailed tracking
 	progressRecord := models.AchievementProgress{
 		PlayerAchievementID: playerAchievement.ID,
@@ -181,7 +207,7 @@ func (s *AchievementService) UpdateProgress(playerID uint, achievementName strin
 		Source:              "game_action",
 	}
 
-	if err := s.db.Create(&progressRecord).Error; err != nil {
+	if err := tx.Create(&progressRecord).Error; err != nil {
 		return fmt.Errorf("failed to create progress record: %w", err)
 	}
 
@@ -189,18 +215,18 @@ func (s *AchievementService) UpdateProgress(playerID uint, achievementName strin
 }
 
 // awardAchievementRewards awards rewards for completing an achievement
-func (s *AchievementService) awardAchievementRewards(playerID uint, achievement *models.Achievement) error {
+func (s *AchievementService) awardAchievementRewards(tx *gorm.DB, playerID uint, achievement *models.Achievement) error {
 	// Award basic rewards
 	if achievement.CreditsReward > 0 || achievement.CrystalsReward > 0 || achievement.ExperienceReward > 0 {
-		err := s.resourceService.AddResources(
-			playerID,
-			achievement.CreditsReward,    // credits
-			achievement.CrystalsReward,   // crystals
-			achievement.ExperienceReward, // experience
-			0, 0, 0, 0, 0, 0, 0, 0,       // other resources
-			"achievement",
-			fmt.Sprintf("Achievement unlocked: %s", achievement.Title),
-		)
+		_, err := s.progression.Apply(tx, playerID, Change{
+			Experience:        achievement.ExperienceReward,
+			Credits:           achievement.CreditsReward,
+			Crystals:          achievement.CrystalsReward,
+			Source:            models.SourceAchievement,
+			Description:       fmt.Sprintf("Achievement unlocked: %s", achievement.Title),
+			RelatedEntityType: "achievement",
+			RelatedEntityID:   fmt.Sprint(achievement.ID),
+		})
 		if err != nil {
 			return fmt.Errorf("failed to award basic rewards: %w", err)
 		}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"starwars-api/config"
+	"starwars-api/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Password length limits; bcrypt ignores everything past 72 bytes, so longer
+// passwords are rejected rather than silently truncated
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+var (
+	ErrUsernameTaken      = errors.New("username is already taken")
+	ErrInvalidPassword    = fmt.Errorf("password must be %d to %d bytes long", MinPasswordLength, MaxPasswordLength)
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+)
+
+// TokenPair is issued on login and refresh: a short-lived signed access token
+// and the refresh token of the session
+type TokenPair struct {
+	AccessToken  string         `json:"access_token"`
+	RefreshToken string         `json:"refresh_token"`
+	TokenType    string         `json:"token_type"`
+	ExpiresIn    int            `json:"expires_in"` // seconds until the access token expires
+	Player       *models.Player `json:"player"`
+}
+
+// accessClaims are the claims of an access token; the subject is the player ID
+type accessClaims struct {
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+type AuthService struct {
+	db          *gorm.DB
+	progression *ProgressionService
+	secret      []byte
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+	bcryptCost  int
+
+	// dummyHash is compared against when a username does not exist, so that
+	// failed logins take as long whether or not the player exists
+	dummyHash []byte
+}
+
+func NewAuthService(db *gorm.DB, progression *ProgressionService, cfg config.AuthConfig) *AuthService {
+	secret := []byte(cfg.TokenSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatal("❌ Failed to generate a token secret:", err)
+		}
+		log.Println("⚠️  No auth.token_secret configured; using a random one, so tokens will not survive a restart")
+	}
+
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("not a password"), cfg.BcryptCost)
+
+	return &AuthService{
+		db:          db,
+		progression: progression,
+		secret:      secret,
+		accessTTL:   time.Duration(cfg.AccessTokenTTL),
+		refreshTTL:  time.Duration(cfg.RefreshTokenTTL),
+		bcryptCost:  cfg.BcryptCost,
+		dummyHash:   dummyHash,
+	}
+}
+
+// Register creates a player with a hashed password, along with its stats and
+// starting balances
+func (s *AuthService) Register(player *models.Player, password string) error {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return ErrInvalidPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.bcryptCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	player.PasswordHash = string(hash)
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Player{}).Where("username = ?", player.Username).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check username: %w", err)
+		}
+		if count > 0 {
+			return ErrUsernameTaken
+		}
+
+		if err := tx.Create(player).Error; err != nil {
+			return fmt.Errorf("failed to create player: %w", err)
+		}
+		if err := tx.Create(&models.PlayerStats{PlayerID: player.ID}).Error; err != nil {
+			return fmt.Errorf("failed to create player stats: %w", err)
+		}
+		return s.progression.Start(tx, player.ID)
+	})
+}
+
+// SetPassword replaces a player's password and ends all of its sessions
+func (s *AuthService) SetPassword(tx *gorm.DB, playerID uint, password string) error {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return ErrInvalidPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.bcryptCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := tx.Model(&models.Player{}).Where("id = ?", playerID).Update("password_hash", string(hash)).Error; err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	err = tx.Model(&models.PlayerSession{}).
+		Where("player_id = ? AND revoked_at IS NULL", playerID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+// Login checks a player's password and starts a session
+func (s *AuthService) Login(username, password string) (*TokenPair, error) {
+	var player models.Player
+	err := s.db.Where("username = ?", username).First(&player).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load player: %w", err)
+	}
+
+	hash := []byte(player.PasswordHash)
+	if len(hash) == 0 {
+		// Unknown players and players created before passwords existed cannot log in
+		hash = s.dummyHash
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil || player.PasswordHash == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	session := models.PlayerSession{ID: uuid.New().String(), PlayerID: player.ID}
+	refreshToken, err := s.rotateRefreshToken(&session)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Create(&session).Error; err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return s.issue(&player, &session, refreshToken)
+}
+
+// Refresh exchanges a refresh token for new tokens. Refresh tokens are single
+// use: presenting one that was already exchanged revokes its session, since
+// one of the two holders must have stolen it.
+func (s *AuthService) Refresh(refreshToken string) (*TokenPair, error) {
+	sessionID, _, ok := strings.Cut(refreshToken, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	var session models.PlayerSession
+	if err := s.db.Where("id = ?", sessionID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(refreshToken)), []byte(session.RefreshTokenHash)) != 1 {
+		if err := s.Logout(session.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidToken
+	}
+
+	var player models.Player
+	if err := s.db.First(&player, session.PlayerID).Error; err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	newRefreshToken, err := s.rotateRefreshToken(&session)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Save(&session).Error; err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	return s.issue(&player, &session, newRefreshToken)
+}
+
+// Logout revokes a session, invalidating its access and refresh tokens
+func (s *AuthService) Logout(sessionID string) error {
+	err := s.db.Model(&models.PlayerSession{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// Authenticate verifies an access token and returns its player and session ID
+func (s *AuthService) Authenticate(accessToken string) (*models.Player, string, error) {
+	claims := &accessClaims{}
+	_, err := jwt.ParseWithClaims(accessToken, claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, "", ErrInvalidToken
+	}
+	playerID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil {
+		return nil, "", ErrInvalidToken
+	}
+
+	// Logging out takes effect immediately rather than when the token expires
+	var session models.PlayerSession
+	err = s.db.Where("id = ? AND player_id = ?", claims.SessionID, playerID).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrInvalidToken
+		}
+		return nil, "", fmt.Errorf("failed to load session: %w", err)
+	}
+	if session.RevokedAt != nil {
+		return nil, "", ErrInvalidToken
+	}
+
+	var player models.Player
+	if err := s.db.First(&player, playerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrInvalidToken
+		}
+		return nil, "", fmt.Errorf("failed to load player: %w", err)
+	}
+	return &player, session.ID, nil
+}
+
+// rotateRefreshToken gives the session a new refresh token and expiry and
+// returns the token; only its hash is stored
+func (s *AuthService) rotateRefreshToken(session *models.PlayerSession) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	token := session.ID + "." + base64.RawURLEncoding.EncodeToString(secret)
+	session.RefreshTokenHash = hashToken(token)
+	session.ExpiresAt = time.Now().Add(s.refreshTTL)
+	return token, nil
+}
+
+// issue signs an access token for the session
+func (s *AuthService) issue(player *models.Player, session *models.PlayerSession, refreshToken string) (*TokenPair, error) {
+	now := time.Now()
+	claims := accessClaims{
+		SessionID: session.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(player.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
+		},
+	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.accessTTL.Seconds()),
+		Player:       player,
+	}, nil
+}
+
+// hashToken hashes a high-entropy token for storage
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"errors"
+	"starwars-api/config"
+	"starwars-api/database"
+	"starwars-api/models"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// openTestDB opens a migrated in-memory SQLite database of its own for a test
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := database.Open(config.DatabaseConfig{
+		Driver:       database.DriverSQLite,
+		URL:          "file:" + name + "?mode=memory&cache=shared",
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+		LogLevel:     "silent",
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if _, err := database.MigrateUp(db); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func newTestAuthService(t *testing.T, db *gorm.DB) *AuthService {
+	t.Helper()
+	cfg := config.Default().Auth
+	cfg.TokenSecret = testSecret
+	cfg.BcryptCost = bcrypt.MinCost
+	return NewAuthService(db, NewProgressionService(db, config.Default().Game), cfg)
+}
+
+func TestAuthServiceRefresh(t *testing.T) {
+	tests := []struct {
+		name string
+		// token returns the refresh token to present, given the service and
+		// the tokens of a fresh login
+		token   func(t *testing.T, s *AuthService, db *gorm.DB, login *TokenPair) string
+		wantErr error
+		// revoked is whether the session of the login must be revoked afterwards
+		revoked bool
+	}{
+		{
+			name:  "valid token",
+			token: func(t *testing.T, s *AuthService, db *gorm.DB, login *TokenPair) string { return login.RefreshToken },
+		},
+		{
+			name: "rotated token",
+			token: func(t *testing.T, s *AuthService, db *gorm.DB, login *TokenPair) string {
+				pair, err := s.Refresh(login.RefreshToken)
+				if err != nil {
+					t.Fatalf("Refresh: %v", err)
+				}
+				return pair.RefreshToken
+			},
+		},
+		{
+			name:    "malformed token",
+			token:   func(t *testing.T, s *AuthService, db *gorm.DB, login *TokenPair) string { return "not-a-token" },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "empty token",
+			token:   func(t *testing.T, s *AuthService, db *gorm.DB, login *TokenPair) string { return "" },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "unknown session",
+			token: func(t *testing.T, s *AuthService, db *gorm.DB, login *TokenPair) string {
+				return "00000000-0000-0000-0000-000000000000." + strings.SplitN(login.RefreshToken, ".", 2)[1]
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "logged out",
+			token: func(t *testing.T, s *AuthService, db *gorm.DB, login *TokenPair) string {
+				if err := s.Logout(sessionOf(login)); err != nil {
+					t.Fatalf("Logout: %v", err)
+				}
+				return login.RefreshToken
+			},
+			wantErr: ErrInvalidToken,
+			revoked: true,
+		},
+		{
+			name: "expired session",
+			token: func(t *testing.T, s *AuthService, db *gorm.DB, login *TokenPair) string {
+				err := db.Model(&models.PlayerSession{}).Where("id = ?", sessionOf(login)).
+					Update("expires_at", time.Now().Add(-time.Minute)).Error
+				if err != nil {
+					t.Fatal(err)
+				}
+				return login.RefreshToken
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "forged secret",
+			token: func(t *testing.T, s *AuthService, db *gorm.DB, login *TokenPair) string {
+				return sessionOf(login) + ".forged"
+			},
+			wantErr: ErrInvalidToken,
+			revoked: true,
+		},
+		{
+			name: "reused token",
+			token: func(t *testing.T, s *AuthService, db *gorm.DB, login *TokenPair) string {
+				if _, err := s.Refresh(login.RefreshToken); err != nil {
+					t.Fatalf("Refresh: %v", err)
+				}
+				return login.RefreshToken
+			},
+			wantErr: ErrInvalidToken,
+			revoked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			s := newTestAuthService(t, db)
+			if err := s.Register(&models.Player{Username: "luke"}, "Password123!"); err != nil {
+				t.Fatalf("Register: %v", err)
+			}
+			login, err := s.Login("luke", "Password123!")
+			if err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+
+			token := tt.token(t, s, db, login)
+			pair, err := s.Refresh(token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Refresh() error = %v, want %v", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Refresh() error = %v", err)
+				}
+				if pair.RefreshToken == token {
+					t.Error("Refresh() returned the refresh token it was given")
+				}
+				if sessionOf(pair) != sessionOf(login) {
+					t.Errorf("Refresh() moved to session %s, want %s", sessionOf(pair), sessionOf(login))
+				}
+				player, sessionID, err := s.Authenticate(pair.AccessToken)
+				if err != nil {
+					t.Fatalf("Authenticate(new access token): %v", err)
+				}
+				if player.Username != "luke" || sessionID != sessionOf(login) {
+					t.Errorf("Authenticate() = %s in session %s, want luke in session %s", player.Username, sessionID, sessionOf(login))
+				}
+			}
+
+			var session models.PlayerSession
+			if err := db.First(&session, "id = ?", sessionOf(login)).Error; err != nil {
+				t.Fatal(err)
+			}
+			if revoked := session.RevokedAt != nil; revoked != tt.revoked {
+				t.Errorf("session revoked = %t, want %t", revoked, tt.revoked)
+			}
+			if tt.revoked {
+				if _, _, err := s.Authenticate(login.AccessToken); !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("Authenticate(access token of revoked session) error = %v, want %v", err, ErrInvalidToken)
+				}
+			}
+		})
+	}
+}
+
+// sessionOf returns the session ID a refresh token belongs to
+func sessionOf(pair *TokenPair) string {
+	sessionID, _, _ := strings.Cut(pair.RefreshToken, ".")
+	return sessionID
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"starwars-api/models"
@@ -9,12 +10,22 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrNotParticipantShip is returned for actions with a ship outside the
+	// fleet of the acting participant
+	ErrNotParticipantShip = errors.New("source ship is not in the participant's fleet")
+	// ErrTargetNotInBattle is returned for attacks on a ship outside the
+	// fleets of the other participants of the battle
+	ErrTargetNotInBattle = errors.New("target ship is not in an opposing fleet of the battle")
+)
+
 type BattleService struct {
-	db *gorm.DB
+	db          *gorm.DB
+	progression *ProgressionService
 }
 
-func NewBattleService(db *gorm.DB) *BattleService {
-	return &BattleService{db: db}
+func NewBattleService(db *gorm.DB, progression *ProgressionService) *BattleService {
+	return &BattleService{db: db, progression: progression}
 }
 
 // CreateBattle creates a new battle between fleets
@@ -138,10 +149,18 @@ func (s *BattleService) ExecuteAction(battleID, participantID, sourceShipID uint
 		return nil, fmt.Errorf("battle is not active")
 	}
 
-	// Get source ship
+	var participant models.BattleParticipant
+	if err := s.db.Where("id = ? AND battle_id = ?", participantID, battleID).First(&participant).Error; err != nil {
+		return nil, fmt.Errorf("participant not found: %w", err)
+	}
+
+	// Participants act with the ships of their own fleet
 	var sourceShip models.Ship
-	if err := s.db.First(&sourceShip, sourceShipID).Error; err != nil {
-		return nil, fmt.Errorf("source ship not found: %w", err)
+	if err := s.db.Where("fleet_id = ?", participant.FleetID).First(&sourceShip, sourceShipID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotParticipantShip
+		}
+		return nil, fmt.Errorf("failed to load source ship: %w", err)
 	}
 
 	// Create battle action
@@ -162,9 +181,15 @@ func (s *BattleService) ExecuteAction(battleID, participantID, sourceShipID uint
 			return nil, fmt.Errorf("target ship required for attack action")
 		}
 
+		// Attacks target the ships of the other participants
+		opposingFleets := s.db.Model(&models.BattleParticipant{}).Select("fleet_id").
+			Where("battle_id = ? AND id <> ? AND fleet_id <> ?", battleID, participant.ID, participant.FleetID)
 		var targetShip models.Ship
-		if err := s.db.First(&targetShip, *targetShipID).Error; err != nil {
-			return nil, fmt.Errorf("target ship not found: %w", err)
+		if err := s.db.Where("fleet_id IN (?)", opposingFleets).First(&targetShip, *targetShipID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrTargetNotInBattle
+			}
+			return nil, fmt.Errorf("failed to load target ship: %w", err)
 		}
 
 		damage := s.calculateDamage(&sourceShip, &targetShip)
@@ -326,6 +351,11 @@ func (s *BattleService) EndBattle(battleID uint, winnerID *uint) error {
 		return err
 	}
 
+	// Rewards are paid once, to a player of the battle
+	if winnerID != nil && !hasParticipantPlayer(battle.Participants, *winnerID) {
+		return fmt.Errorf("winner is not a player of the battle")
+	}
+
 	now := time.Now()
 	battle.Status = "completed"
 	battle.CompletedAt = &now
@@ -335,10 +365,6 @@ func (s *BattleService) EndBattle(battleID uint, winnerID *uint) error {
 		battle.Duration = int(now.Sub(*battle.StartedAt).Seconds())
 	}
 
-	if err := s.db.Save(&battle).Error; err != nil {
-		return fmt.Errorf("failed to update battle: %w", err)
-	}
-
 	// Create battle result
 	resultType := "draw"
 	if winnerID != nil {
@@ -356,14 +382,49 @@ func (s *BattleService) EndBattle(battleID uint, winnerID *uint) error {
 		OverallRating:     s.calculateBattleRating(&battle),
 	}
 
-	if err := s.db.Create(&result).Error; err != nil {
-		return fmt.Errorf("failed to create battle result: %w", err)
-	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// Only the first of concurrent calls completes the battle, and so pays
+		// its rewards
+		update := tx.Model(&models.Battle{}).Where("id = ? AND status <> ?", battleID, "completed").Updates(map[string]interface{}{
+			"status":       battle.Status,
+			"completed_at": battle.CompletedAt,
+			"winner_id":    battle.WinnerID,
+			"duration":     battle.Duration,
+		})
+		if update.Error != nil {
+			return fmt.Errorf("failed to update battle: %w", update.Error)
+		}
+		if update.RowsAffected != 1 {
+			return fmt.Errorf("battle already ended")
+		}
+		if err := tx.Create(&result).Error; err != nil {
+			return fmt.Errorf("failed to create battle result: %w", err)
+		}
 
-	// Award rewards to winner (integrate with resource service)
-	// For now, we'll skip the reward distribution
+		// Award rewards to the winning player; AI winners get nothing
+		if winnerID == nil {
+			return nil
+		}
+		_, err := s.progression.Apply(tx, *winnerID, Change{
+			Experience:        battle.ExperienceReward,
+			Credits:           battle.CreditsReward,
+			Source:            models.SourceBattle,
+			Description:       fmt.Sprintf("Won battle %d", battle.ID),
+			RelatedEntityType: "battle",
+			RelatedEntityID:   fmt.Sprint(battle.ID),
+		})
+		return err
+	})
+}
 
-	return nil
+// hasParticipantPlayer reports whether a player is one of participants
+func hasParticipantPlayer(participants []models.BattleParticipant, playerID uint) bool {
+	for _, participant := range participants {
+		if participant.PlayerID != nil && *participant.PlayerID == playerID {
+			return true
+		}
+	}
+	return false
 }
 
 // getActiveShipsForTeam returns active ships for a team
@@ -482,6 +543,31 @@ func (s *BattleService) GetBattle(battleID uint) (*models.Battle, error) {
 	return &battle, err
 }
 
+// FleetOwnedBy reports whether a fleet belongs to a player
+func (s *BattleService) FleetOwnedBy(fleetID, playerID uint) (bool, error) {
+	var count int64
+	err := s.db.Model(&models.Fleet{}).Where("id = ? AND player_id = ?", fleetID, playerID).Count(&count).Error
+	return count > 0, err
+}
+
+// IsParticipant reports whether a player fights in a battle
+func (s *BattleService) IsParticipant(battleID, playerID uint) (bool, error) {
+	var count int64
+	err := s.db.Model(&models.BattleParticipant{}).
+		Where("battle_id = ? AND player_id = ?", battleID, playerID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ParticipantOwnedBy reports whether a battle participant is a player's
+func (s *BattleService) ParticipantOwnedBy(battleID, participantID, playerID uint) (bool, error) {
+	var count int64
+	err := s.db.Model(&models.BattleParticipant{}).
+		Where("id = ? AND battle_id = ? AND player_id = ?", participantID, battleID, playerID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // GetPlayerBattles returns battles for a player
 func (s *BattleService) GetPlayerBattles(playerID uint) ([]models.Battle, error) {
 	var battles []models.Battle
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"starwars-api/database"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrDuplicateURL is returned when a catalog entity would reuse another entity's URL
+var ErrDuplicateURL = errors.New("url is already used by another entity")
+
+// ErrSnapshotUnsupported is returned by Snapshot for databases other than SQLite
+var ErrSnapshotUnsupported = errors.New("snapshots are only supported for SQLite databases")
+
+// UnresolvedURLsError lists relation URLs that match no catalog entity
+type UnresolvedURLsError struct {
+	Relation string
+	URLs     []string
+}
+
+func (e *UnresolvedURLsError) Error() string {
+	return fmt.Sprintf("unknown %s: %s", e.Relation, strings.Join(e.URLs, ", "))
+}
+
+type CatalogAdminService struct {
+	db *gorm.DB
+}
+
+func NewCatalogAdminService(db *gorm.DB) *CatalogAdminService {
+	return &CatalogAdminService{db: db}
+}
+
+// Get loads a catalog entity by ID into row, a pointer to a catalog model,
+// with the given associations preloaded
+func (s *CatalogAdminService) Get(row interface{}, id uint, preloads ...string) error {
+	query := s.db
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	return query.First(row, id).Error
+}
+
+// Create inserts a catalog entity and links the relations given by URL;
+// relations maps association names to the URLs of their entities
+func (s *CatalogAdminService) Create(row interface{}, relations map[string][]string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		url := reflect.ValueOf(row).Elem().FieldByName("URL").String()
+		if err := checkURLAvailable(tx, row, url, 0); err != nil {
+			return err
+		}
+
+		if err := tx.Omit(clause.Associations).Create(row).Error; err != nil {
+			return fmt.Errorf("failed to create entity: %w", err)
+		}
+		return replaceAssociations(tx, row, relations)
+	})
+}
+
+// Update changes the given fields of a catalog entity, keyed by model field
+// name, and replaces the listed relations. The edited timestamp is bumped even
+// when only relations change.
+func (s *CatalogAdminService) Update(row interface{}, id uint, columns map[string]interface{}, relations map[string][]string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(row, id).Error; err != nil {
+			return err
+		}
+
+		if url, ok := columns["URL"].(string); ok {
+			if err := checkURLAvailable(tx, row, url, id); err != nil {
+				return err
+			}
+		}
+
+		updates := make(map[string]interface{}, len(columns)+1)
+		for column, value := range columns {
+			updates[column] = value
+		}
+		updates["updated_at"] = time.Now()
+
+		if err := tx.Model(row).Omit(clause.Associations).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update entity: %w", err)
+		}
+		return replaceAssociations(tx, row, relations)
+	})
+}
+
+// Delete removes a catalog entity together with its relation links and the
+// rows it owns
+func (s *CatalogAdminService) Delete(row interface{}, id uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(row, id).Error; err != nil {
+			return err
+		}
+
+		// Links to other entities live in many2many join tables
+		stmt := &gorm.Statement{DB: tx}
+		if err := stmt.Parse(row); err != nil {
+			return err
+		}
+		for _, relationship := range stmt.Schema.Relationships.Many2Many {
+			if err := tx.Model(row).Association(relationship.Name).Clear(); err != nil {
+				return fmt.Errorf("failed to unlink %s: %w", relationship.Name, err)
+			}
+		}
+
+		// Children that are catalog entities of their own, like the residents
+		// of a planet, lose their link; the others, like the weapon systems of
+		// a starship, are deleted with it
+		for _, relationships := range [][]*schema.Relationship{stmt.Schema.Relationships.HasOne, stmt.Schema.Relationships.HasMany} {
+			for _, relationship := range relationships {
+				association := tx.Model(row).Association(relationship.Name)
+				if relationship.FieldSchema.LookUpField("URL") == nil {
+					association = association.Unscoped()
+				}
+				if err := association.Clear(); err != nil {
+					return fmt.Errorf("failed to remove %s: %w", relationship.Name, err)
+				}
+			}
+		}
+
+		if err := tx.Delete(row).Error; err != nil {
+			return fmt.Errorf("failed to delete entity: %w", err)
+		}
+		return deleteTranslations(tx, stmt.Schema.Table, id)
+	})
+}
+
+// Snapshot writes a consistent copy of the whole database to a new SQLite
+// file at path, without blocking readers or writers for longer than the copy
+func (s *CatalogAdminService) Snapshot(path string) error {
+	if !database.IsSQLite(s.db) {
+		return ErrSnapshotUnsupported
+	}
+	if err := s.db.Exec("VACUUM INTO ?", path).Error; err != nil {
+		return fmt.Errorf("failed to snapshot database: %w", err)
+	}
+	return nil
+}
+
+// checkURLAvailable fails with ErrDuplicateURL if another row of the table uses the URL
+func checkURLAvailable(tx *gorm.DB, row interface{}, url string, id uint) error {
+	var count int64
+	query := tx.Model(row).Where("url = ?", url)
+	if id != 0 {
+		query = query.Where("id <> ?", id)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicateURL
+	}
+	return nil
+}
+
+// replaceAssociations points each listed association of row at the entities
+// with the given URLs; every URL must exist
+func replaceAssociations(tx *gorm.DB, row interface{}, relations map[string][]string) error {
+	names := make([]string, 0, len(relations))
+	for name := range relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		urls := relations[name]
+		field := reflect.ValueOf(row).Elem().FieldByName(name)
+
+		targets := reflect.New(field.Type())
+		if len(urls) > 0 {
+			if err := tx.Where("url IN ?", urls).Find(targets.Interface()).Error; err != nil {
+				return err
+			}
+		}
+
+		found := make(map[string]bool, targets.Elem().Len())
+		for i := 0; i < targets.Elem().Len(); i++ {
+			found[targets.Elem().Index(i).FieldByName("URL").String()] = true
+		}
+		var missing []string
+		for _, url := range urls {
+			if !found[url] {
+				missing = append(missing, url)
+			}
+		}
+		if len(missing) > 0 {
+			return &UnresolvedURLsError{Relation: name, URLs: missing}
+		}
+
+		if err := tx.Model(row).Association(name).Replace(targets.Interface()); err != nil {
+			return fmt.Errorf("failed to update %s: %w", name, err)
+		}
+	}
+	return nil
+}
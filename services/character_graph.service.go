@@ -0,0 +1,265 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"starwars-api/database"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoConnection is returned when two characters share no chain of relations
+var ErrNoConnection = errors.New("characters are not connected")
+
+// characterNodeType is the node type of characters in the graph
+const characterNodeType = "people"
+
+// graphRelation is a join table linking characters to entities they can share
+type graphRelation struct {
+	Type       string // node type of the linked entities
+	JoinTable  string
+	Column     string // join table column holding the entity ID
+	Table      string
+	NameColumn string
+}
+
+// graphRelations are the relations that connect characters, in path preference order
+var graphRelations = []graphRelation{
+	{Type: "films", JoinTable: "character_films", Column: "film_id", Table: "films", NameColumn: "title"},
+	{Type: "starships", JoinTable: "character_starships", Column: "starship_id", Table: "starships", NameColumn: "name"},
+	{Type: "vehicles", JoinTable: "character_vehicles", Column: "vehicle_id", Table: "vehicles", NameColumn: "name"},
+	{Type: "organizations", JoinTable: "character_organizations", Column: "organization_id", Table: "organizations", NameColumn: "name"},
+	{Type: "weapons", JoinTable: "character_weapons", Column: "weapon_id", Table: "weapons", NameColumn: "name"},
+	{Type: "events", JoinTable: "character_events", Column: "event_id", Table: "events", NameColumn: "name"},
+}
+
+// GraphNode is a character or an entity linking characters
+type GraphNode struct {
+	Type string `json:"type"`
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// CharacterPath is the shortest chain of shared entities between two characters
+type CharacterPath struct {
+	From    GraphNode   `json:"from"`
+	To      GraphNode   `json:"to"`
+	Degrees int         `json:"degrees"`
+	Path    []GraphNode `json:"path"`
+}
+
+// CharacterConnection is a character sharing entities with another one. Each
+// shared entity weighs 1/(n-1) for the n characters linked to it, so sharing
+// a starship with one other pilot counts more than sharing a crowded film.
+type CharacterConnection struct {
+	Character GraphNode      `json:"character"`
+	Count     int            `json:"count"`
+	Weight    float64        `json:"weight"`
+	Shared    map[string]int `json:"shared"`
+}
+
+// graphKey identifies a node of the graph
+type graphKey struct {
+	Type string
+	ID   uint
+}
+
+// characterGraph is the bipartite graph of characters and the entities they share
+type characterGraph struct {
+	version uint64
+	nodes   map[graphKey]GraphNode
+	links   map[graphKey][]graphKey
+}
+
+// CharacterGraphService answers connection queries from an in-memory graph
+// that is rebuilt whenever the catalog changes
+type CharacterGraphService struct {
+	db    *gorm.DB
+	mu    sync.Mutex
+	graph *characterGraph
+}
+
+func NewCharacterGraphService(db *gorm.DB) *CharacterGraphService {
+	return &CharacterGraphService{db: db}
+}
+
+// Path finds the shortest chain of shared entities between two characters,
+// e.g. Luke Skywalker → film → Palpatine
+func (s *CharacterGraphService) Path(fromID, toID uint) (*CharacterPath, error) {
+	graph, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := graphKey{characterNodeType, fromID}, graphKey{characterNodeType, toID}
+	if _, ok := graph.nodes[from]; !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if _, ok := graph.nodes[to]; !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	// Breadth-first search, remembering where each node was reached from
+	previous := map[graphKey]graphKey{from: from}
+	queue := []graphKey{from}
+	for len(queue) > 0 && queue[0] != to {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range graph.links[current] {
+			if _, seen := previous[next]; !seen {
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	if _, reached := previous[to]; !reached {
+		return nil, ErrNoConnection
+	}
+
+	var path []GraphNode
+	for key := to; ; key = previous[key] {
+		path = append([]GraphNode{graph.nodes[key]}, path...)
+		if key == from {
+			break
+		}
+	}
+
+	return &CharacterPath{
+		From:    graph.nodes[from],
+		To:      graph.nodes[to],
+		Degrees: len(path) / 2,
+		Path:    path,
+	}, nil
+}
+
+// Connections returns the characters sharing entities with a character,
+// strongest connections first
+func (s *CharacterGraphService) Connections(id uint, limit int) ([]CharacterConnection, error) {
+	graph, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+
+	character := graphKey{characterNodeType, id}
+	if _, ok := graph.nodes[character]; !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	connections := make(map[graphKey]*CharacterConnection)
+	for _, shared := range graph.links[character] {
+		others := graph.links[shared]
+		for _, other := range others {
+			if other == character {
+				continue
+			}
+
+			connection, ok := connections[other]
+			if !ok {
+				connection = &CharacterConnection{Character: graph.nodes[other], Shared: make(map[string]int)}
+				connections[other] = connection
+			}
+			connection.Count++
+			connection.Weight += 1 / float64(len(others)-1)
+			connection.Shared[shared.Type]++
+		}
+	}
+
+	result := make([]CharacterConnection, 0, len(connections))
+	for _, connection := range connections {
+		connection.Weight = math.Round(connection.Weight*1000) / 1000
+		result = append(result, *connection)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Weight != result[j].Weight {
+			return result[i].Weight > result[j].Weight
+		}
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Character.ID < result[j].Character.ID
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// current returns the graph for the current catalog version, rebuilding it if
+// the catalog changed since it was built
+func (s *CharacterGraphService) current() (*characterGraph, error) {
+	version, _, err := database.CatalogVersion(s.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog version: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.graph == nil || s.graph.version != version {
+		graph, err := s.build(version)
+		if err != nil {
+			return nil, err
+		}
+		s.graph = graph
+	}
+	return s.graph, nil
+}
+
+// build loads every character, linked entity and link into a new graph
+func (s *CharacterGraphService) build(version uint64) (*characterGraph, error) {
+	graph := &characterGraph{
+		version: version,
+		nodes:   make(map[graphKey]GraphNode),
+		links:   make(map[graphKey][]graphKey),
+	}
+
+	var characters []GraphNode
+	if err := s.db.Table("characters").Select("id, name, url").Order("id").Scan(&characters).Error; err != nil {
+		return nil, fmt.Errorf("failed to load characters: %w", err)
+	}
+	for _, character := range characters {
+		character.Type = characterNodeType
+		graph.nodes[graphKey{characterNodeType, character.ID}] = character
+	}
+
+	// Links are added in relation order, then ID order, so that paths are stable
+	for _, relation := range graphRelations {
+		var entities []GraphNode
+		err := s.db.Table(relation.Table).Select("id, " + relation.NameColumn + " AS name, url").Order("id").Scan(&entities).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", relation.Type, err)
+		}
+		for _, entity := range entities {
+			entity.Type = relation.Type
+			graph.nodes[graphKey{relation.Type, entity.ID}] = entity
+		}
+
+		var links []struct {
+			CharacterID uint
+			EntityID    uint
+		}
+		err = s.db.Table(relation.JoinTable).Select("character_id, " + relation.Column + " AS entity_id").
+			Order("character_id, " + relation.Column).Scan(&links).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s links: %w", relation.Type, err)
+		}
+		for _, link := range links {
+			character, entity := graphKey{characterNodeType, link.CharacterID}, graphKey{relation.Type, link.EntityID}
+			if _, ok := graph.nodes[character]; !ok {
+				continue
+			}
+			if _, ok := graph.nodes[entity]; !ok {
+				continue
+			}
+			graph.links[character] = append(graph.links[character], entity)
+			graph.links[entity] = append(graph.links[entity], character)
+		}
+	}
+
+	return graph, nil
+}
@@ -9,11 +9,12 @@ import (
 )
 
 type FleetService struct {
-	db *gorm.DB
+	db          *gorm.DB
+	progression *ProgressionService
 }
 
-func NewFleetService(db *gorm.DB) *FleetService {
-	return &FleetService{db: db}
+func NewFleetService(db *gorm.DB, progression *ProgressionService) *FleetService {
+	return &FleetService{db: db, progression: progression}
 }
 
 // GetPlayerFleet returns the player's main fleet
@@ -167,9 +168,25 @@ func (s *FleetService) PurchaseShip(playerID uint, templateID uint) (*models.Shi
 	if err := s.db.First(&template, templateID).Error; err != nil {
 		return nil, fmt.Errorf("ship template not found: %w", err)
 	}
+	if !template.IsAvailable {
+		return nil, fmt.Errorf("ship %s is not available", template.Name)
+	}
 
-	// Check if player has enough credits (this would integrate with resource service)
-	// For now, we'll skip the credit check
+	// Ships are unlocked by the player's level and need room in the hangar
+	var player models.Player
+	if err := s.db.Select("id", "level").First(&player, playerID).Error; err != nil {
+		return nil, fmt.Errorf("player not found: %w", err)
+	}
+	if player.Level < template.RequiredLevel {
+		return nil, fmt.Errorf("ship %s requires level %d", template.Name, template.RequiredLevel)
+	}
+	hangar, err := s.GetPlayerHangar(playerID)
+	if err != nil {
+		return nil, err
+	}
+	if hangar.CurrentShips >= hangar.MaxShips {
+		return nil, fmt.Errorf("hangar is full (%d ships)", hangar.MaxShips)
+	}
 
 	// Create ship from template
 	ship := models.Ship{
@@ -198,8 +215,22 @@ func (s *FleetService) PurchaseShip(playerID uint, templateID uint) (*models.Shi
 		Location:        "hangar",
 	}
 
-	if err := s.db.Create(&ship).Error; err != nil {
-		return nil, fmt.Errorf("failed to create ship: %w", err)
+	// The ship is paid for in the transaction that creates it
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&ship).Error; err != nil {
+			return fmt.Errorf("failed to create ship: %w", err)
+		}
+		_, err := s.progression.Apply(tx, playerID, Change{
+			Credits:           -template.Cost,
+			Source:            models.SourcePurchase,
+			Description:       fmt.Sprintf("Bought %s", template.Name),
+			RelatedEntityType: "ship",
+			RelatedEntityID:   fmt.Sprint(ship.ID),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &ship, nil
@@ -309,16 +340,7 @@ func (s *FleetService) GetPlayerHangar(playerID uint) (*models.Hangar, error) {
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			// Create default hangar
-			hangar = models.Hangar{
-				PlayerID:     playerID,
-				Name:         "Main Hangar",
-				Location:     "base",
-				MaxShips:     10,
-				CurrentShips: 0,
-				Level:        1,
-				MaxLevel:     10,
-				UpgradeCost:  5000,
-			}
+			hangar = defaultHangar(playerID)
 
 			if err := s.db.Create(&hangar).Error; err != nil {
 				return nil, fmt.Errorf("failed to create default hangar: %w", err)
@@ -337,6 +359,40 @@ func (s *FleetService) GetPlayerHangar(playerID uint) (*models.Hangar, error) {
 	return &hangar, nil
 }
 
+// defaultHangar returns the hangar a player starts with
+func defaultHangar(playerID uint) models.Hangar {
+	return models.Hangar{
+		PlayerID:     playerID,
+		Name:         "Main Hangar",
+		Location:     "base",
+		MaxShips:     10,
+		CurrentShips: 0,
+		Level:        1,
+		MaxLevel:     10,
+		UpgradeCost:  5000,
+	}
+}
+
+// GrantLevelUpHangarSlots adds the hangar slots of a level_up event to the
+// player's hangar
+func (s *FleetService) GrantLevelUpHangarSlots(tx *gorm.DB, event LevelUpEvent) error {
+	levelUp := event.LevelUp
+	if levelUp.HangarSlots == 0 {
+		return nil
+	}
+
+	var hangar models.Hangar
+	err := tx.Where("player_id = ?", levelUp.PlayerID).Attrs(defaultHangar(levelUp.PlayerID)).FirstOrCreate(&hangar).Error
+	if err != nil {
+		return fmt.Errorf("failed to load player hangar: %w", err)
+	}
+
+	if err := tx.Model(&hangar).Update("max_ships", gorm.Expr("max_ships + ?", levelUp.HangarSlots)).Error; err != nil {
+		return fmt.Errorf("failed to add hangar slots: %w", err)
+	}
+	return nil
+}
+
 // UpgradeHangar increases hangar capacity
 func (s *FleetService) UpgradeHangar(playerID uint) (*models.Hangar, error) {
 	hangar, err := s.GetPlayerHangar(playerID)
@@ -13,12 +13,14 @@ import (
 type MissionService struct {
 	db                *gorm.DB
 	brightDataService *BrightDataService
+	progression       *ProgressionService
 }
 
-func NewMissionService(db *gorm.DB) *MissionService {
+func NewMissionService(db *gorm.DB, progression *ProgressionService) *MissionService {
 	return &MissionService{
 		db:                db,
 		brightDataService: NewBrightDataService(db),
+		progression:       progression,
 	}
 }
 
@@ -160,6 +162,11 @@ func (s *MissionService) CompleteMission(playerID, missionID int, rating int) (*
 		return nil, fmt.Errorf("mission not found: %w", err)
 	}
 
+	// Rewards are only paid again for repeatable missions
+	if progress.Status == "completed" && !mission.IsRepeatable {
+		return nil, fmt.Errorf("mission already completed")
+	}
+
 	// Update progress
 	now := time.Now()
 	progress.Status = "completed"
@@ -187,12 +194,25 @@ func (s *MissionService) CompleteMission(playerID, missionID int, rating int) (*
 	progress.CreditsEarned = mission.CreditsReward
 	progress.ItemsEarned = mission.ItemRewards
 
-	if err := s.db.Save(&progress).Error; err != nil {
-		return nil, fmt.Errorf("failed to update mission progress: %w", err)
-	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&progress).Error; err != nil {
+			return fmt.Errorf("failed to update mission progress: %w", err)
+		}
 
-	// Award rewards to player (this would integrate with player service)
-	// s.awardRewardsToPlayer(playerID, progress)
+		// Award rewards to player
+		_, err := s.progression.Apply(tx, uint(playerID), Change{
+			Experience:        mission.ExperienceReward,
+			Credits:           mission.CreditsReward,
+			Source:            models.SourceMission,
+			Description:       fmt.Sprintf("Mission completed: %s", mission.Name),
+			RelatedEntityType: "mission",
+			RelatedEntityID:   fmt.Sprint(mission.ID),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return &progress, nil
 }
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"starwars-api/config"
+	"starwars-api/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNegativeExperience is returned for changes taking experience away, which
+// would leave the level above what the experience earns
+var ErrNegativeExperience = errors.New("experience cannot be taken away")
+
+// InsufficientFundsError is returned when a change would take a balance
+// below zero
+type InsufficientFundsError struct {
+	Currency string
+	Have     int
+	Need     int
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("insufficient %s: have %d, need %d", e.Currency, e.Have, e.Need)
+}
+
+// Change is a change of a player's experience and currencies. Positive
+// amounts are gains, negative amounts are spending.
+type Change struct {
+	Experience        int
+	Credits           int
+	Crystals          int
+	Source            string // one of the models.Source constants
+	Description       string
+	RelatedEntityType string
+	RelatedEntityID   string
+}
+
+// Balance is a player's level, experience and currencies
+type Balance struct {
+	PlayerID     uint `json:"player_id"`
+	Level        int  `json:"level"`
+	Experience   int  `json:"experience"`
+	Credits      int  `json:"credits"`
+	Crystals     int  `json:"crystals"`
+	LevelsGained int  `json:"levels_gained,omitempty"` // levels gained by the change that returned the balance
+}
+
+// LevelUpEvent is the level_up event, published for every level a player
+// reaches
+type LevelUpEvent struct {
+	LevelUp *models.LevelUp // the history record, with the rewards of the level
+	Balance Balance         // the player's balances after the change
+}
+
+// LevelUpHandler reacts to a level_up event in the transaction of the change
+// that caused it; an error undoes the change
+type LevelUpHandler func(tx *gorm.DB, event LevelUpEvent) error
+
+// ProgressionService owns the experience, level and currencies of players.
+// It is the only writer of those balances and records every change in the
+// ledger; everything else reads them.
+type ProgressionService struct {
+	db              *gorm.DB
+	balance         config.GameConfig
+	levelUpHandlers []LevelUpHandler
+}
+
+func NewProgressionService(db *gorm.DB, balance config.GameConfig) *ProgressionService {
+	return &ProgressionService{db: db, balance: balance}
+}
+
+// OnLevelUp registers a handler of level_up events. Handlers are registered
+// at startup and run in the order they were registered.
+func (s *ProgressionService) OnLevelUp(handler LevelUpHandler) {
+	s.levelUpHandlers = append(s.levelUpHandlers, handler)
+}
+
+// Start gives a newly created player its starting balances
+func (s *ProgressionService) Start(tx *gorm.DB, playerID uint) error {
+	if err := s.updateBalances(tx, &Balance{PlayerID: playerID, Level: 1, Credits: s.balance.StartingCredits}); err != nil {
+		return fmt.Errorf("failed to set starting balances: %w", err)
+	}
+
+	if s.balance.StartingCredits == 0 {
+		return nil
+	}
+	entry := models.LedgerEntry{
+		PlayerID:    playerID,
+		Currency:    models.CurrencyCredits,
+		Amount:      s.balance.StartingCredits,
+		Balance:     s.balance.StartingCredits,
+		Source:      models.SourceRegistration,
+		Description: "Starting credits",
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to record starting credits: %w", err)
+	}
+	return nil
+}
+
+// GetBalance returns the balances of a player
+func (s *ProgressionService) GetBalance(playerID uint) (*Balance, error) {
+	player, err := s.loadPlayer(s.db, playerID)
+	if err != nil {
+		return nil, err
+	}
+	return balanceOf(player), nil
+}
+
+// Apply changes the balances of a player in a transaction of tx, levelling it
+// up when its experience reaches the next level. Experience can only be
+// gained, and nothing changes if a balance would go below zero.
+func (s *ProgressionService) Apply(tx *gorm.DB, playerID uint, change Change) (*Balance, error) {
+	if change.Experience < 0 {
+		return nil, ErrNegativeExperience
+	}
+
+	var balance *Balance
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		var err error
+		balance, err = s.apply(tx, playerID, change)
+		return err
+	})
+	return balance, err
+}
+
+func (s *ProgressionService) apply(tx *gorm.DB, playerID uint, change Change) (*Balance, error) {
+	player, err := s.loadPlayer(tx.Clauses(clause.Locking{Strength: "UPDATE"}), playerID)
+	if err != nil {
+		return nil, err
+	}
+	before := balanceOf(player)
+
+	after := *before
+	after.Experience += change.Experience
+	after.Credits += change.Credits
+	after.Crystals += change.Crystals
+	for _, check := range []struct {
+		currency      string
+		have, balance int
+	}{
+		{models.CurrencyExperience, before.Experience, after.Experience},
+		{models.CurrencyCredits, before.Credits, after.Credits},
+		{models.CurrencyCrystals, before.Crystals, after.Crystals},
+	} {
+		if check.balance < 0 {
+			return nil, &InsufficientFundsError{Currency: check.currency, Have: check.have, Need: check.have - check.balance}
+		}
+	}
+
+	entries := ledgerEntries(playerID, change, &after)
+
+	// The level follows experience and never goes down. Every level reached is
+	// recorded with its rewards, of which the credits are paid here.
+	var levelUps []models.LevelUp
+	for level := after.Level + 1; level <= s.levelFor(after.Experience); level++ {
+		levelUp, err := s.levelUp(tx, playerID, level, after.Experience, change.Source)
+		if err != nil {
+			return nil, err
+		}
+		after.Level = level
+		if levelUp.Credits > 0 {
+			after.Credits += levelUp.Credits
+			entries = append(entries, models.LedgerEntry{
+				PlayerID:          playerID,
+				Currency:          models.CurrencyCredits,
+				Amount:            levelUp.Credits,
+				Balance:           after.Credits,
+				Source:            models.SourceLevelUp,
+				Description:       fmt.Sprintf("Reached level %d", level),
+				RelatedEntityType: "level_up",
+				RelatedEntityID:   fmt.Sprint(levelUp.ID),
+			})
+		}
+		levelUps = append(levelUps, *levelUp)
+	}
+	after.LevelsGained = after.Level - before.Level
+
+	if len(entries) == 0 && after.LevelsGained == 0 {
+		return &after, nil
+	}
+
+	if err := s.updateBalances(tx, &after); err != nil {
+		return nil, fmt.Errorf("failed to update balances: %w", err)
+	}
+	if len(entries) > 0 {
+		if err := tx.Create(&entries).Error; err != nil {
+			return nil, fmt.Errorf("failed to record ledger entries: %w", err)
+		}
+	}
+	if len(levelUps) == 0 {
+		return &after, nil
+	}
+
+	for i := range levelUps {
+		event := LevelUpEvent{LevelUp: &levelUps[i], Balance: after}
+		for _, handler := range s.levelUpHandlers {
+			if err := handler(tx, event); err != nil {
+				return nil, fmt.Errorf("failed to handle level %d: %w", levelUps[i].Level, err)
+			}
+		}
+	}
+
+	// Handlers may have changed the balances, e.g. with achievement rewards
+	player, err = s.loadPlayer(tx, playerID)
+	if err != nil {
+		return nil, err
+	}
+	balance := balanceOf(player)
+	balance.LevelsGained = balance.Level - before.Level
+	return balance, nil
+}
+
+// levelUp records a level reached by a player with the rewards of the level
+func (s *ProgressionService) levelUp(tx *gorm.DB, playerID uint, level, experience int, source string) (*models.LevelUp, error) {
+	rewards := s.balance.Levels[level-1]
+	levelUp := models.LevelUp{
+		PlayerID:       playerID,
+		Level:          level,
+		Experience:     experience,
+		Source:         source,
+		Credits:        rewards.Credits,
+		Durasteel:      rewards.Materials.Durasteel,
+		Transparisteel: rewards.Materials.Transparisteel,
+		Tibanna:        rewards.Materials.Tibanna,
+		Kyber:          rewards.Materials.Kyber,
+		HangarSlots:    rewards.HangarSlots,
+		UnlockedShips:  []string{},
+	}
+
+	err := tx.Model(&models.ShipTemplate{}).Where("is_available = ? AND required_level = ?", true, level).
+		Order("name").Pluck("name", &levelUp.UnlockedShips).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ships unlocked at level %d: %w", level, err)
+	}
+
+	if err := tx.Create(&levelUp).Error; err != nil {
+		return nil, fmt.Errorf("failed to record level %d: %w", level, err)
+	}
+	return &levelUp, nil
+}
+
+// ListLedger returns the ledger of a player, newest first, optionally of one
+// currency
+func (s *ProgressionService) ListLedger(playerID uint, currency string, limit, offset int) ([]models.LedgerEntry, int64, error) {
+	query := s.db.Model(&models.LedgerEntry{}).Where("player_id = ?", playerID)
+	if currency != "" {
+		query = query.Where("currency = ?", currency)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count ledger entries: %w", err)
+	}
+
+	var entries []models.LedgerEntry
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list ledger entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+// ListLevelUps returns the levels a player has reached, newest first
+func (s *ProgressionService) ListLevelUps(playerID uint, limit, offset int) ([]models.LevelUp, int64, error) {
+	query := s.db.Model(&models.LevelUp{}).Where("player_id = ?", playerID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count level-ups: %w", err)
+	}
+
+	var levelUps []models.LevelUp
+	if err := query.Order("level DESC").Limit(limit).Offset(offset).Find(&levelUps).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list level-ups: %w", err)
+	}
+	return levelUps, total, nil
+}
+
+// Levels returns the leveling curve
+func (s *ProgressionService) Levels() []config.LevelConfig {
+	return s.balance.Levels
+}
+
+// levelFor returns the highest level of the curve reached with an amount of
+// experience
+func (s *ProgressionService) levelFor(experience int) int {
+	level := 1
+	for level < len(s.balance.Levels) && s.balance.Levels[level].Experience <= experience {
+		level++
+	}
+	return level
+}
+
+// updateBalances stores the balances of a player. The fields are read-only
+// on models.Player, so they are written to the table directly.
+func (s *ProgressionService) updateBalances(tx *gorm.DB, balance *Balance) error {
+	return tx.Table("players").Where("id = ?", balance.PlayerID).Updates(map[string]interface{}{
+		"level":      balance.Level,
+		"experience": balance.Experience,
+		"credits":    balance.Credits,
+		"crystals":   balance.Crystals,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+func (s *ProgressionService) loadPlayer(tx *gorm.DB, playerID uint) (*models.Player, error) {
+	var player models.Player
+	if err := tx.Select("id", "level", "experience", "credits", "crystals").First(&player, playerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlayerNotFound
+		}
+		return nil, fmt.Errorf("failed to load player: %w", err)
+	}
+	return &player, nil
+}
+
+func balanceOf(player *models.Player) *Balance {
+	return &Balance{
+		PlayerID:   player.ID,
+		Level:      player.Level,
+		Experience: player.Experience,
+		Credits:    player.Credits,
+		Crystals:   player.Crystals,
+	}
+}
+
+// ledgerEntries returns an entry for every currency a change touches
+func ledgerEntries(playerID uint, change Change, after *Balance) []models.LedgerEntry {
+	var entries []models.LedgerEntry
+	for _, amount := range []struct {
+		currency        string
+		amount, balance int
+	}{
+		{models.CurrencyExperience, change.Experience, after.Experience},
+		{models.CurrencyCredits, change.Credits, after.Credits},
+		{models.CurrencyCrystals, change.Crystals, after.Crystals},
+	} {
+		if amount.amount == 0 {
+			continue
+		}
+		entries = append(entries, models.LedgerEntry{
+			PlayerID:          playerID,
+			Currency:          amount.currency,
+			Amount:            amount.amount,
+			Balance:           amount.balance,
+			Source:            change.Source,
+			Description:       change.Description,
+			RelatedEntityType: change.RelatedEntityType,
+			RelatedEntityID:   change.RelatedEntityID,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"starwars-api/config"
+	"starwars-api/models"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// testBalance is a small level table, so that the tests do not change when
+// the default one is rebalanced
+func testBalance() config.GameConfig {
+	balance := config.Default().Game
+	balance.StartingCredits = 100
+	balance.Levels = []config.LevelConfig{
+		{Level: 1},
+		{Level: 2, Experience: 100, Credits: 50},
+		{Level: 3, Experience: 300, Credits: 50, Materials: config.MaterialsConfig{Kyber: 1}},
+	}
+	return balance
+}
+
+func TestProgressionServiceApply(t *testing.T) {
+	errHandler := errors.New("hangar is full")
+	started := Balance{Level: 1, Credits: 100}
+	registration := "credits registration +100 = 100"
+
+	tests := []struct {
+		name     string
+		change   Change
+		handler  func(s *ProgressionService) LevelUpHandler
+		unknown  bool // apply the change to a player that does not exist
+		wantErr  error
+		want     Balance  // balances after the change, also when it fails
+		levelUps []int    // levels recorded
+		ledger   []string // ledger after the change, oldest first
+	}{
+		{
+			name:   "experience without a level up",
+			change: Change{Experience: 50, Source: models.SourceQuiz},
+			want:   Balance{Level: 1, Experience: 50, Credits: 100},
+			ledger: []string{registration, "experience quiz +50 = 50"},
+		},
+		{
+			name:     "reaching the next level",
+			change:   Change{Experience: 100, Source: models.SourceMission},
+			want:     Balance{Level: 2, Experience: 100, Credits: 150, LevelsGained: 1},
+			levelUps: []int{2},
+			ledger:   []string{registration, "experience mission +100 = 100", "credits level_up +50 = 150"},
+		},
+		{
+			name:     "several levels at once",
+			change:   Change{Experience: 350, Credits: 10, Source: models.SourceBattle},
+			want:     Balance{Level: 3, Experience: 350, Credits: 210, LevelsGained: 2},
+			levelUps: []int{2, 3},
+			ledger: []string{
+				registration,
+				"experience battle +350 = 350",
+				"credits battle +10 = 110",
+				"credits level_up +50 = 160",
+				"credits level_up +50 = 210",
+			},
+		},
+		{
+			name:   "spending",
+			change: Change{Credits: -60, Source: models.SourceUpgrade},
+			want:   Balance{Level: 1, Credits: 40},
+			ledger: []string{registration, "credits upgrade -60 = 40"},
+		},
+		{
+			name:   "no change",
+			change: Change{Source: models.SourceAdmin},
+			want:   started,
+			ledger: []string{registration},
+		},
+		{
+			name:    "insufficient funds",
+			change:  Change{Experience: 100, Credits: -101, Source: models.SourceUpgrade},
+			wantErr: &InsufficientFundsError{Currency: models.CurrencyCredits, Have: 100, Need: 101},
+			want:    started,
+			ledger:  []string{registration},
+		},
+		{
+			name:    "insufficient crystals",
+			change:  Change{Crystals: -1, Source: models.SourceConversion},
+			wantErr: &InsufficientFundsError{Currency: models.CurrencyCrystals, Have: 0, Need: 1},
+			want:    started,
+			ledger:  []string{registration},
+		},
+		{
+			name:    "negative experience",
+			change:  Change{Experience: -10, Source: models.SourceAdmin},
+			wantErr: ErrNegativeExperience,
+			want:    started,
+			ledger:  []string{registration},
+		},
+		{
+			name:    "unknown player",
+			change:  Change{Experience: 10, Source: models.SourceQuiz},
+			unknown: true,
+			wantErr: ErrPlayerNotFound,
+			want:    started,
+			ledger:  []string{registration},
+		},
+		{
+			name:   "failing level up handler",
+			change: Change{Experience: 100, Source: models.SourceQuiz},
+			handler: func(s *ProgressionService) LevelUpHandler {
+				return func(tx *gorm.DB, event LevelUpEvent) error { return errHandler }
+			},
+			wantErr: errHandler,
+			want:    started,
+			ledger:  []string{registration},
+		},
+		{
+			name:   "level up handler changing balances",
+			change: Change{Experience: 300, Source: models.SourceQuiz},
+			handler: func(s *ProgressionService) LevelUpHandler {
+				return func(tx *gorm.DB, event LevelUpEvent) error {
+					_, err := s.Apply(tx, event.LevelUp.PlayerID, Change{Crystals: 1, Source: models.SourceAchievement})
+					return err
+				}
+			},
+			want:     Balance{Level: 3, Experience: 300, Credits: 200, Crystals: 2, LevelsGained: 2},
+			levelUps: []int{2, 3},
+			ledger: []string{
+				registration,
+				"experience quiz +300 = 300",
+				"credits level_up +50 = 150",
+				"credits level_up +50 = 200",
+				"crystals achievement +1 = 1",
+				"crystals achievement +1 = 2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			s := NewProgressionService(db, testBalance())
+			if tt.handler != nil {
+				s.OnLevelUp(tt.handler(s))
+			}
+
+			player := models.Player{Username: "luke"}
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Create(&player).Error; err != nil {
+					return err
+				}
+				return s.Start(tx, player.ID)
+			})
+			if err != nil {
+				t.Fatalf("failed to create player: %v", err)
+			}
+			want := tt.want
+			want.PlayerID = player.ID
+
+			playerID := player.ID
+			if tt.unknown {
+				playerID++
+			}
+			got, err := s.Apply(db, playerID, tt.change)
+			if tt.wantErr != nil {
+				if !sameError(err, tt.wantErr) {
+					t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Apply() error = %v", err)
+				}
+				if *got != want {
+					t.Errorf("Apply() = %+v, want %+v", *got, want)
+				}
+			}
+
+			stored, err := s.GetBalance(player.ID)
+			if err != nil {
+				t.Fatalf("GetBalance: %v", err)
+			}
+			want.LevelsGained = 0
+			if *stored != want {
+				t.Errorf("stored balance = %+v, want %+v", *stored, want)
+			}
+
+			var levels []int
+			if err := db.Model(&models.LevelUp{}).Order("level").Pluck("level", &levels).Error; err != nil {
+				t.Fatal(err)
+			}
+			if len(levels) != len(tt.levelUps) || (len(levels) > 0 && !reflect.DeepEqual(levels, tt.levelUps)) {
+				t.Errorf("level ups = %v, want %v", levels, tt.levelUps)
+			}
+
+			var entries []models.LedgerEntry
+			if err := db.Order("id").Find(&entries).Error; err != nil {
+				t.Fatal(err)
+			}
+			var ledger []string
+			for _, entry := range entries {
+				ledger = append(ledger, fmt.Sprintf("%s %s %+d = %d", entry.Currency, entry.Source, entry.Amount, entry.Balance))
+			}
+			if !reflect.DeepEqual(ledger, tt.ledger) {
+				t.Errorf("ledger = %q, want %q", ledger, tt.ledger)
+			}
+		})
+	}
+}
+
+// sameError reports whether err is want, or an InsufficientFundsError equal
+// to it
+func sameError(err, want error) bool {
+	if errors.Is(err, want) {
+		return true
+	}
+	var funds *InsufficientFundsError
+	return errors.As(err, &funds) && reflect.DeepEqual(funds, want)
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"starwars-api/models"
 	"time"
@@ -8,12 +9,16 @@ import (
 	"gorm.io/gorm"
 )
 
+// ResourceService manages the materials, energy and fuel of players. Their
+// credits, crystals and experience belong to the ProgressionService and are
+// only shown on PlayerResources.
 type ResourceService struct {
-	db *gorm.DB
+	db          *gorm.DB
+	progression *ProgressionService
 }
 
-func NewResourceService(db *gorm.DB) *ResourceService {
-	return &ResourceService{db: db}
+func NewResourceService(db *gorm.DB, progression *ProgressionService) *ResourceService {
+	return &ResourceService{db: db, progression: progression}
 }
 
 // GetPlayerResources returns the player's resources
@@ -30,18 +35,33 @@ func (s *ResourceService) GetPlayerResources(playerID uint) (*models.PlayerResou
 	}
 
 	// Generate resources since last check
-	s.generateResources(&resources)
+	if err := s.generateResources(&resources); err != nil {
+		return nil, err
+	}
 
+	if err := s.showBalances(&resources); err != nil {
+		return nil, err
+	}
 	return &resources, nil
 }
 
 // CreateDefaultResources creates default resources for a new player
 func (s *ResourceService) CreateDefaultResources(playerID uint) (*models.PlayerResources, error) {
-	resources := models.PlayerResources{
+	resources := defaultResources(playerID)
+	if err := s.db.Create(&resources).Error; err != nil {
+		return nil, fmt.Errorf("failed to create default resources: %w", err)
+	}
+
+	if err := s.showBalances(&resources); err != nil {
+		return nil, err
+	}
+	return &resources, nil
+}
+
+// defaultResources returns the resources a player starts with
+func defaultResources(playerID uint) models.PlayerResources {
+	return models.PlayerResources{
 		PlayerID:       playerID,
-		Credits:        1000,
-		Crystals:       0,
-		Experience:     0,
 		Durasteel:      50,
 		Transparisteel: 10,
 		Tibanna:        20,
@@ -58,26 +78,82 @@ func (s *ResourceService) CreateDefaultResources(playerID uint) (*models.PlayerR
 		FuelPerHour:    20,
 		LastGeneration: time.Now(),
 	}
+}
 
-	if err := s.db.Create(&resources).Error; err != nil {
-		return nil, fmt.Errorf("failed to create default resources: %w", err)
+// GrantLevelUpMaterials adds the materials of a level_up event to the
+// player's resources
+func (s *ResourceService) GrantLevelUpMaterials(tx *gorm.DB, event LevelUpEvent) error {
+	levelUp := event.LevelUp
+	if levelUp.Durasteel == 0 && levelUp.Transparisteel == 0 && levelUp.Tibanna == 0 && levelUp.Kyber == 0 {
+		return nil
 	}
 
-	return &resources, nil
+	var resources models.PlayerResources
+	err := tx.Where("player_id = ?", levelUp.PlayerID).Attrs(defaultResources(levelUp.PlayerID)).FirstOrCreate(&resources).Error
+	if err != nil {
+		return fmt.Errorf("failed to load player resources: %w", err)
+	}
+
+	err = tx.Model(&resources).Updates(map[string]interface{}{
+		"durasteel":      gorm.Expr("durasteel + ?", levelUp.Durasteel),
+		"transparisteel": gorm.Expr("transparisteel + ?", levelUp.Transparisteel),
+		"tibanna":        gorm.Expr("tibanna + ?", levelUp.Tibanna),
+		"kyber":          gorm.Expr("kyber + ?", levelUp.Kyber),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to add level-up materials: %w", err)
+	}
+
+	transaction := models.ResourceTransaction{
+		PlayerID:             levelUp.PlayerID,
+		TransactionType:      "earn",
+		Source:               models.SourceLevelUp,
+		Description:          fmt.Sprintf("Reached level %d", levelUp.Level),
+		DurasteelChange:      levelUp.Durasteel,
+		TransparisteelChange: levelUp.Transparisteel,
+		TibannaChange:        levelUp.Tibanna,
+		KyberChange:          levelUp.Kyber,
+		RelatedEntityType:    "level_up",
+		RelatedEntityID:      &levelUp.ID,
+		IsSuccessful:         true,
+		CreditsAfter:         event.Balance.Credits,
+		CrystalsAfter:        event.Balance.Crystals,
+		ExperienceAfter:      event.Balance.Experience,
+	}
+	if err := tx.Create(&transaction).Error; err != nil {
+		return fmt.Errorf("failed to create transaction record: %w", err)
+	}
+	return nil
+}
+
+// showBalances copies the player's credits, crystals and experience onto its
+// resources
+func (s *ResourceService) showBalances(resources *models.PlayerResources) error {
+	balance, err := s.progression.GetBalance(resources.PlayerID)
+	if err != nil {
+		return err
+	}
+	setBalances(resources, balance)
+	return nil
+}
+
+func setBalances(resources *models.PlayerResources, balance *Balance) {
+	resources.Credits = balance.Credits
+	resources.Crystals = balance.Crystals
+	resources.Experience = balance.Experience
 }
 
 // generateResources generates resources based on time passed
-func (s *ResourceService) generateResources(resources *models.PlayerResources) {
+func (s *ResourceService) generateResources(resources *models.PlayerResources) error {
 	now := time.Now()
 	hoursPassed := now.Sub(resources.LastGeneration).Hours()
 
 	if hoursPassed < 0.1 { // Less than 6 minutes
-		return
+		return nil
 	}
 
 	// Generate credits
 	creditsGenerated := int(hoursPassed * float64(resources.CreditsPerHour))
-	resources.Credits += creditsGenerated
 
 	// Generate energy
 	energyGenerated := int(hoursPassed * float64(resources.EnergyPerHour))
@@ -94,7 +170,20 @@ func (s *ResourceService) generateResources(resources *models.PlayerResources) {
 	}
 
 	resources.LastGeneration = now
-	s.db.Save(resources)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		_, err := s.progression.Apply(tx, resources.PlayerID, Change{
+			Credits:     creditsGenerated,
+			Source:      models.SourceGeneration,
+			Description: fmt.Sprintf("Generated over %.1f hours", hoursPassed),
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(resources).Error; err != nil {
+			return fmt.Errorf("failed to save generated resources: %w", err)
+		}
+		return nil
+	})
 }
 
 // AddResources adds resources to a player's account
@@ -104,15 +193,7 @@ func (s *ResourceService) AddResources(playerID uint, credits, crystals, experie
 		return err
 	}
 
-	// Store original values for transaction record (not used but kept for future enhancement)
-	_ = resources.Credits
-	_ = resources.Crystals
-	_ = resources.Experience
-
 	// Add resources
-	resources.Credits += credits
-	resources.Crystals += crystals
-	resources.Experience += experience
 	resources.Durasteel += durasteel
 	resources.Transparisteel += transparisteel
 	resources.Tibanna += tibanna
@@ -131,39 +212,55 @@ func (s *ResourceService) AddResources(playerID uint, credits, crystals, experie
 		resources.Fuel = resources.MaxFuel
 	}
 
-	// Save resources
-	if err := s.db.Save(resources).Error; err != nil {
-		return fmt.Errorf("failed to add resources: %w", err)
-	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// Save resources first, as the experience may level the player up and
+		// add level-up materials
+		if err := tx.Save(resources).Error; err != nil {
+			return fmt.Errorf("failed to add resources: %w", err)
+		}
 
-	// Create transaction record
-	transaction := models.ResourceTransaction{
-		PlayerID:             playerID,
-		TransactionType:      "earn",
-		Source:               source,
-		Description:          description,
-		CreditsChange:        credits,
-		CrystalsChange:       crystals,
-		ExperienceChange:     experience,
-		DurasteelChange:      durasteel,
-		TransparisteelChange: transparisteel,
-		TibannaChange:        tibanna,
-		KyberChange:          kyber,
-		EnergyChange:         energy,
-		FuelChange:           fuel,
-		ReputationChange:     reputation,
-		InfluenceChange:      influence,
-		IsSuccessful:         true,
-		CreditsAfter:         resources.Credits,
-		CrystalsAfter:        resources.Crystals,
-		ExperienceAfter:      resources.Experience,
-	}
+		// Credits, crystals and experience go through the progression ledger
+		balance, err := s.progression.Apply(tx, playerID, Change{
+			Experience:  experience,
+			Credits:     credits,
+			Crystals:    crystals,
+			Source:      source,
+			Description: description,
+		})
+		if err != nil {
+			return err
+		}
+		setBalances(resources, balance)
+
+		// Create transaction record
+		transaction := models.ResourceTransaction{
+			PlayerID:             playerID,
+			TransactionType:      "earn",
+			Source:               source,
+			Description:          description,
+			CreditsChange:        credits,
+			CrystalsChange:       crystals,
+			ExperienceChange:     experience,
+			DurasteelChange:      durasteel,
+			TransparisteelChange: transparisteel,
+			TibannaChange:        tibanna,
+			KyberChange:          kyber,
+			EnergyChange:         energy,
+			FuelChange:           fuel,
+			ReputationChange:     reputation,
+			InfluenceChange:      influence,
+			IsSuccessful:         true,
+			CreditsAfter:         resources.Credits,
+			CrystalsAfter:        resources.Crystals,
+			ExperienceAfter:      resources.Experience,
+		}
 
-	if err := s.db.Create(&transaction).Error; err != nil {
-		return fmt.Errorf("failed to create transaction record: %w", err)
-	}
+		if err := tx.Create(&transaction).Error; err != nil {
+			return fmt.Errorf("failed to create transaction record: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // SpendResources deducts resources from a player's account
@@ -173,13 +270,8 @@ func (s *ResourceService) SpendResources(playerID uint, credits, crystals, duras
 		return err
 	}
 
-	// Check if player has enough resources
-	if resources.Credits < credits {
-		return fmt.Errorf("insufficient credits: have %d, need %d", resources.Credits, credits)
-	}
-	if resources.Crystals < crystals {
-		return fmt.Errorf("insufficient crystals: have %d, need %d", resources.Crystals, crystals)
-	}
+	// Check if player has enough resources; credits and crystals are checked
+	// by the progression service
 	if resources.Durasteel < durasteel {
 		return fmt.Errorf("insufficient durasteel: have %d, need %d", resources.Durasteel, durasteel)
 	}
@@ -199,14 +291,7 @@ func (s *ResourceService) SpendResources(playerID uint, credits, crystals, duras
 		return fmt.Errorf("insufficient fuel: have %d, need %d", resources.Fuel, fuel)
 	}
 
-	// Store original values for transaction record (not used but kept for future enhancement)
-	_ = resources.Credits
-	_ = resources.Crystals
-	_ = resources.Experience
-
 	// Deduct resources
-	resources.Credits -= credits
-	resources.Crystals -= crystals
 	resources.Durasteel -= durasteel
 	resources.Transparisteel -= transparisteel
 	resources.Tibanna -= tibanna
@@ -214,36 +299,49 @@ func (s *ResourceService) SpendResources(playerID uint, credits, crystals, duras
 	resources.Energy -= energy
 	resources.Fuel -= fuel
 
-	// Save resources
-	if err := s.db.Save(resources).Error; err != nil {
-		return fmt.Errorf("failed to spend resources: %w", err)
-	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		balance, err := s.progression.Apply(tx, playerID, Change{
+			Credits:     -credits,
+			Crystals:    -crystals,
+			Source:      source,
+			Description: description,
+		})
+		if err != nil {
+			return err
+		}
+		setBalances(resources, balance)
 
-	// Create transaction record
-	transaction := models.ResourceTransaction{
-		PlayerID:             playerID,
-		TransactionType:      "spend",
-		Source:               source,
-		Description:          description,
-		CreditsChange:        -credits,
-		CrystalsChange:       -crystals,
-		DurasteelChange:      -durasteel,
-		TransparisteelChange: -transparisteel,
-		TibannaChange:        -tibanna,
-		KyberChange:          -kyber,
-		EnergyChange:         -energy,
-		FuelChange:           -fuel,
-		IsSuccessful:         true,
-		CreditsAfter:         resources.Credits,
-		CrystalsAfter:        resources.Crystals,
-		ExperienceAfter:      resources.Experience,
-	}
+		// Save resources
+		if err := tx.Save(resources).Error; err != nil {
+			return fmt.Errorf("failed to spend resources: %w", err)
+		}
 
-	if err := s.db.Create(&transaction).Error; err != nil {
-		return fmt.Errorf("failed to create transaction record: %w", err)
-	}
+		// Create transaction record
+		transaction := models.ResourceTransaction{
+			PlayerID:             playerID,
+			TransactionType:      "spend",
+			Source:               source,
+			Description:          description,
+			CreditsChange:        -credits,
+			CrystalsChange:       -crystals,
+			DurasteelChange:      -durasteel,
+			TransparisteelChange: -transparisteel,
+			TibannaChange:        -tibanna,
+			KyberChange:          -kyber,
+			EnergyChange:         -energy,
+			FuelChange:           -fuel,
+			IsSuccessful:         true,
+			CreditsAfter:         resources.Credits,
+			CrystalsAfter:        resources.Crystals,
+			ExperienceAfter:      resources.Experience,
+		}
 
-	return nil
+		if err := tx.Create(&transaction).Error; err != nil {
+			return fmt.Errorf("failed to create transaction record: %w", err)
+		}
+
+		return nil
+	})
 }
 
 // GetResourceTransactions returns transaction history for a player
@@ -280,16 +378,9 @@ func (s *ResourceService) ConvertResources(playerID uint, conversionID uint) err
 		return err
 	}
 
-	// Check if player has enough source resources
+	// Check if player has enough source resources; credits and crystals are
+	// checked by the progression service
 	switch conversion.FromResourceType {
-	case "credits":
-		if resources.Credits < conversion.FromAmount {
-			return fmt.Errorf("insufficient credits for conversion")
-		}
-	case "crystals":
-		if resources.Crystals < conversion.FromAmount {
-			return fmt.Errorf("insufficient crystals for conversion")
-		}
 	case "durasteel":
 		if resources.Durasteel < conversion.FromAmount {
 			return fmt.Errorf("insufficient durasteel for conversion")
@@ -297,21 +388,22 @@ func (s *ResourceService) ConvertResources(playerID uint, conversionID uint) err
 		// Add other resource types as needed
 	}
 
-	// Perform conversion
+	// Perform conversion, collecting the change of credits and crystals
+	var currencies Change
 	switch conversion.FromResourceType {
 	case "credits":
-		resources.Credits -= conversion.FromAmount
+		currencies.Credits -= conversion.FromAmount
 	case "crystals":
-		resources.Crystals -= conversion.FromAmount
+		currencies.Crystals -= conversion.FromAmount
 	case "durasteel":
 		resources.Durasteel -= conversion.FromAmount
 	}
 
 	switch conversion.ToResourceType {
 	case "credits":
-		resources.Credits += conversion.ToAmount
+		currencies.Credits += conversion.ToAmount
 	case "crystals":
-		resources.Crystals += conversion.ToAmount
+		currencies.Crystals += conversion.ToAmount
 	case "durasteel":
 		resources.Durasteel += conversion.ToAmount
 	case "transparisteel":
@@ -323,64 +415,69 @@ func (s *ResourceService) ConvertResources(playerID uint, conversionID uint) err
 	}
 
 	// Pay conversion fee
-	if conversion.ConversionFee > 0 {
-		if resources.Credits < conversion.ConversionFee {
-			return fmt.Errorf("insufficient credits for conversion fee")
-		}
-		resources.Credits -= conversion.ConversionFee
-	}
+	currencies.Credits -= conversion.ConversionFee
 
-	// Save resources
-	if err := s.db.Save(resources).Error; err != nil {
-		return fmt.Errorf("failed to save converted resources: %w", err)
-	}
-
-	// Create transaction record
 	description := fmt.Sprintf("Converted %d %s to %d %s",
 		conversion.FromAmount, conversion.FromResourceType,
 		conversion.ToAmount, conversion.ToResourceType)
+	currencies.Source = models.SourceConversion
+	currencies.Description = description
+	currencies.RelatedEntityType = "resource_conversion"
+	currencies.RelatedEntityID = fmt.Sprint(conversion.ID)
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		balance, err := s.progression.Apply(tx, playerID, currencies)
+		if err != nil {
+			var insufficient *InsufficientFundsError
+			if errors.As(err, &insufficient) {
+				return fmt.Errorf("insufficient %s for conversion", insufficient.Currency)
+			}
+			return err
+		}
+		setBalances(resources, balance)
 
-	transaction := models.ResourceTransaction{
-		PlayerID:        playerID,
-		TransactionType: "convert",
-		Source:          "conversion",
-		Description:     description,
-		IsSuccessful:    true,
-		CreditsAfter:    resources.Credits,
-		CrystalsAfter:   resources.Crystals,
-		ExperienceAfter: resources.Experience,
-	}
+		// Save resources
+		if err := tx.Save(resources).Error; err != nil {
+			return fmt.Errorf("failed to save converted resources: %w", err)
+		}
 
-	// Set the appropriate change fields
-	switch conversion.FromResourceType {
-	case "credits":
-		transaction.CreditsChange = -conversion.FromAmount
-	case "crystals":
-		transaction.CrystalsChange = -conversion.FromAmount
-	case "durasteel":
-		transaction.DurasteelChange = -conversion.FromAmount
-	}
+		// Create transaction record
+		transaction := models.ResourceTransaction{
+			PlayerID:        playerID,
+			TransactionType: "convert",
+			Source:          "conversion",
+			Description:     description,
+			IsSuccessful:    true,
+			CreditsChange:   currencies.Credits,
+			CrystalsChange:  currencies.Crystals,
+			CreditsAfter:    resources.Credits,
+			CrystalsAfter:   resources.Crystals,
+			ExperienceAfter: resources.Experience,
+		}
 
-	switch conversion.ToResourceType {
-	case "credits":
-		transaction.CreditsChange += conversion.ToAmount
-	case "crystals":
-		transaction.CrystalsChange += conversion.ToAmount
-	case "durasteel":
-		transaction.DurasteelChange += conversion.ToAmount
-	case "transparisteel":
-		transaction.TransparisteelChange += conversion.ToAmount
-	case "tibanna":
-		transaction.TibannaChange += conversion.ToAmount
-	case "kyber":
-		transaction.KyberChange += conversion.ToAmount
-	}
+		// Set the appropriate change fields
+		switch conversion.FromResourceType {
+		case "durasteel":
+			transaction.DurasteelChange = -conversion.FromAmount
+		}
 
-	if err := s.db.Create(&transaction).Error; err != nil {
-		return fmt.Errorf("failed to create conversion transaction: %w", err)
-	}
+		switch conversion.ToResourceType {
+		case "durasteel":
+			transaction.DurasteelChange += conversion.ToAmount
+		case "transparisteel":
+			transaction.TransparisteelChange += conversion.ToAmount
+		case "tibanna":
+			transaction.TibannaChange += conversion.ToAmount
+		case "kyber":
+			transaction.KyberChange += conversion.ToAmount
+		}
 
-	return nil
+		if err := tx.Create(&transaction).Error; err != nil {
+			return fmt.Errorf("failed to create conversion transaction: %w", err)
+		}
+
+		return nil
+	})
 }
 
 // GetAvailableConversions returns available resource conversions
@@ -397,13 +494,6 @@ func (s *ResourceService) UpgradeResourceGeneration(playerID uint, resourceType
 		return err
 	}
 
-	if resources.Credits < upgradeCost {
-		return fmt.Errorf("insufficient credits for upgrade: have %d, need %d", resources.Credits, upgradeCost)
-	}
-
-	// Deduct upgrade cost
-	resources.Credits -= upgradeCost
-
 	// Increase generation rate
 	switch resourceType {
 	case "credits":
@@ -416,27 +506,45 @@ func (s *ResourceService) UpgradeResourceGeneration(playerID uint, resourceType
 		return fmt.Errorf("invalid resource type for upgrade: %s", resourceType)
 	}
 
-	if err := s.db.Save(resources).Error; err != nil {
-		return fmt.Errorf("failed to upgrade resource generation: %w", err)
-	}
-
-	// Create transaction record
 	description := fmt.Sprintf("Upgraded %s generation rate", resourceType)
-	transaction := models.ResourceTransaction{
-		PlayerID:        playerID,
-		TransactionType: "spend",
-		Source:          "upgrade",
-		Description:     description,
-		CreditsChange:   -upgradeCost,
-		IsSuccessful:    true,
-		CreditsAfter:    resources.Credits,
-		CrystalsAfter:   resources.Crystals,
-		ExperienceAfter: resources.Experience,
-	}
 
-	if err := s.db.Create(&transaction).Error; err != nil {
-		return fmt.Errorf("failed to create upgrade transaction: %w", err)
-	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// Deduct upgrade cost
+		balance, err := s.progression.Apply(tx, playerID, Change{
+			Credits:     -upgradeCost,
+			Source:      models.SourceUpgrade,
+			Description: description,
+		})
+		if err != nil {
+			var insufficient *InsufficientFundsError
+			if errors.As(err, &insufficient) {
+				return fmt.Errorf("insufficient credits for upgrade: have %d, need %d", insufficient.Have, insufficient.Need)
+			}
+			return err
+		}
+		setBalances(resources, balance)
 
-	return nil
+		if err := tx.Save(resources).Error; err != nil {
+			return fmt.Errorf("failed to upgrade resource generation: %w", err)
+		}
+
+		// Create transaction record
+		transaction := models.ResourceTransaction{
+			PlayerID:        playerID,
+			TransactionType: "spend",
+			Source:          "upgrade",
+			Description:     description,
+			CreditsChange:   -upgradeCost,
+			IsSuccessful:    true,
+			CreditsAfter:    resources.Credits,
+			CrystalsAfter:   resources.Crystals,
+			ExperienceAfter: resources.Experience,
+		}
+
+		if err := tx.Create(&transaction).Error; err != nil {
+			return fmt.Errorf("failed to create upgrade transaction: %w", err)
+		}
+
+		return nil
+	})
 }
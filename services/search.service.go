@@ -0,0 +1,201 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"starwars-api/database"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// searchTermPattern extracts the words of a free-text query
+var searchTermPattern = regexp.MustCompile(`[\pL\pN]+`)
+
+// snippetRadius is the number of characters kept around a match in LIKE snippets
+const snippetRadius = 60
+
+// SearchResult is a single ranked match from the catalog search index
+type SearchResult struct {
+	Type    string  `json:"type"`
+	ID      uint    `json:"id"`
+	URL     string  `json:"url"`
+	Name    string  `json:"name"`
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
+
+type SearchService struct {
+	db *gorm.DB
+}
+
+func NewSearchService(db *gorm.DB) *SearchService {
+	return &SearchService{db: db}
+}
+
+// Search returns catalog entities matching every word of the query, best matches first.
+// Names weigh ten times more than descriptions; lower ranks are better.
+func (s *SearchService) Search(query string, types []string, limit, offset int) ([]SearchResult, int64, error) {
+	terms := searchTermPattern.FindAllString(strings.ToLower(query), -1)
+	if len(terms) == 0 {
+		return []SearchResult{}, 0, nil
+	}
+
+	if database.FullTextSearchEnabled() {
+		return s.searchFTS(terms, types, limit, offset)
+	}
+	return s.searchLike(terms, types, limit, offset)
+}
+
+// searchFTS runs a prefix match for every term against the FTS5 index
+func (s *SearchService) searchFTS(terms []string, types []string, limit, offset int) ([]SearchResult, int64, error) {
+	phrases := make([]string, len(terms))
+	for i, term := range terms {
+		phrases[i] = `"` + term + `"*`
+	}
+	match := strings.Join(phrases, " AND ")
+
+	query := s.db.Table(database.SearchTable).Where(database.SearchTable+" MATCH ?", match)
+	if len(types) > 0 {
+		query = query.Where("entity_type IN ?", types)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	results := []SearchResult{}
+	err := query.Select("entity_type AS type, entity_id AS id, url, title AS name, " +
+		"snippet(" + database.SearchTable + ", 4, '<mark>', '</mark>', '…', 16) AS snippet, " +
+		"bm25(" + database.SearchTable + ", 0, 0, 0, 10.0, 1.0) AS rank").
+		Order("rank").Limit(limit).Offset(offset).Scan(&results).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search: %w", err)
+	}
+
+	return results, total, nil
+}
+
+// LIKE search weights: a name equal to the query beats names containing a
+// term as a word, which beat names containing it within a word, and then
+// descriptions likewise
+const (
+	likeWeightExactName   = 100
+	likeWeightNameWord    = 10
+	likeWeightNamePartial = 5
+	likeWeightBodyWord    = 2
+	likeWeightBodyPartial = 1
+)
+
+// searchLike is the fallback used on PostgreSQL and when SQLite is built
+// without FTS5. It ranks by the weights above, negated so that lower ranks are
+// better as with FTS5.
+func (s *SearchService) searchLike(terms []string, types []string, limit, offset int) ([]SearchResult, int64, error) {
+	query := s.db.Table(database.SearchTable)
+	for _, term := range terms {
+		pattern := "%" + term + "%"
+		query = query.Where("(LOWER(title) LIKE ? OR LOWER(body) LIKE ?)", pattern, pattern)
+	}
+	if len(types) > 0 {
+		query = query.Where("entity_type IN ?", types)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	score, args := likeScore(terms)
+	var rows []struct {
+		EntityType string
+		EntityID   uint
+		URL        string
+		Title      string
+		Body       string
+		Rank       float64
+	}
+	err := query.Select("entity_type, entity_id, url, title, body, -("+score+") AS rank", args...).
+		Order("rank").Order("title").Limit(limit).Offset(offset).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search: %w", err)
+	}
+
+	results := make([]SearchResult, len(rows))
+	for i, row := range rows {
+		snippet := likeSnippet(row.Body, terms)
+		if !strings.Contains(snippet, "<mark>") {
+			// Matched by name only, e.g. most people: show the name
+			snippet = likeSnippet(row.Title, terms)
+		}
+		results[i] = SearchResult{
+			Type:    row.EntityType,
+			ID:      row.EntityID,
+			URL:     row.URL,
+			Name:    row.Title,
+			Snippet: snippet,
+			Rank:    row.Rank,
+		}
+	}
+
+	return results, total, nil
+}
+
+// likeScore returns the SQL expression scoring a search document for terms,
+// with its arguments. Words are matched between spaces.
+func likeScore(terms []string) (string, []interface{}) {
+	parts := []string{fmt.Sprintf("CASE WHEN LOWER(title) = ? THEN %d ELSE 0 END", likeWeightExactName)}
+	args := []interface{}{strings.Join(terms, " ")}
+	for _, term := range terms {
+		word, partial := "% "+term+" %", "%"+term+"%"
+		parts = append(parts,
+			fmt.Sprintf("CASE WHEN ' ' || LOWER(title) || ' ' LIKE ? THEN %d WHEN LOWER(title) LIKE ? THEN %d ELSE 0 END",
+				likeWeightNameWord, likeWeightNamePartial),
+			fmt.Sprintf("CASE WHEN ' ' || LOWER(body) || ' ' LIKE ? THEN %d WHEN LOWER(body) LIKE ? THEN %d ELSE 0 END",
+				likeWeightBodyWord, likeWeightBodyPartial))
+		args = append(args, word, partial, word, partial)
+	}
+	return strings.Join(parts, " + "), args
+}
+
+// likeSnippet cuts the text around the first matching term and highlights it
+func likeSnippet(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		lower = text // byte offsets only line up when lowercasing keeps the length
+	}
+	for _, term := range terms {
+		index := strings.Index(lower, term)
+		if index < 0 {
+			continue
+		}
+
+		start, end := index-snippetRadius, index+len(term)+snippetRadius
+		prefix, suffix := "…", "…"
+		if start <= 0 {
+			start, prefix = 0, ""
+		}
+		if end >= len(text) {
+			end, suffix = len(text), ""
+		}
+		for start > 0 && !utf8.RuneStart(text[start]) {
+			start--
+		}
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+
+		return prefix + text[start:index] + "<mark>" + text[index:index+len(term)] + "</mark>" +
+			text[index+len(term):end] + suffix
+	}
+
+	if len(text) > 2*snippetRadius {
+		cut := 2 * snippetRadius
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		return text[:cut] + "…"
+	}
+	return text
+}
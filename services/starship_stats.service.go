@@ -0,0 +1,327 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"starwars-api/database"
+	"starwars-api/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnknownStat is returned when a starship stat is not defined
+var ErrUnknownStat = errors.New("unknown starship stat")
+
+// MissingStarshipsError lists requested starship IDs that do not exist
+type MissingStarshipsError struct {
+	IDs []uint
+}
+
+func (e *MissingStarshipsError) Error() string {
+	ids := make([]string, len(e.IDs))
+	for i, id := range e.IDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return "unknown starships: " + strings.Join(ids, ", ")
+}
+
+// starshipStat is a comparable starship statistic
+type starshipStat struct {
+	Key            string
+	HigherIsBetter bool
+	value          func(ship models.Starship) (float64, bool)
+}
+
+// starshipStats are the compared and ranked stats. Technical specs fall back on
+// the SWAPI fields for starships without Bright Data enhancements.
+var starshipStats = []starshipStat{
+	{"max_speed_kmh", true, func(ship models.Starship) (float64, bool) {
+		if ship.TechnicalSpecs != nil && ship.TechnicalSpecs.MaxSpeedKmh > 0 {
+			return float64(ship.TechnicalSpecs.MaxSpeedKmh), true
+		}
+		return swapiNumber(ship.MaxAtmospheringSpeed)
+	}},
+	{"hyperdrive_class", false, func(ship models.Starship) (float64, bool) {
+		if ship.TechnicalSpecs != nil && ship.TechnicalSpecs.HyperdriveClass > 0 {
+			return ship.TechnicalSpecs.HyperdriveClass, true
+		}
+		// A class of 0 means the starship has no hyperdrive
+		if class, ok := swapiNumber(ship.HyperdriveRating); ok && class > 0 {
+			return class, true
+		}
+		return 0, false
+	}},
+	{"mglt_rating", true, func(ship models.Starship) (float64, bool) {
+		if ship.TechnicalSpecs != nil && ship.TechnicalSpecs.MGLTRating > 0 {
+			return float64(ship.TechnicalSpecs.MGLTRating), true
+		}
+		return swapiNumber(ship.MGLT)
+	}},
+	{"length_meters", true, func(ship models.Starship) (float64, bool) {
+		if ship.TechnicalSpecs != nil && ship.TechnicalSpecs.LengthMeters > 0 {
+			return ship.TechnicalSpecs.LengthMeters, true
+		}
+		return optionalNumber(ship.LengthValue)
+	}},
+	{"cargo_tons", true, func(ship models.Starship) (float64, bool) {
+		if ship.TechnicalSpecs != nil && ship.TechnicalSpecs.CargoTons > 0 {
+			return ship.TechnicalSpecs.CargoTons, true
+		}
+		// SWAPI cargo capacities are in kilograms
+		if kilograms, ok := optionalNumber(ship.CargoCapacityValue); ok {
+			return kilograms / 1000, true
+		}
+		return 0, false
+	}},
+	{"shield_strength", true, technicalSpec(func(specs *models.StarshipTechnicalSpecs) int { return specs.ShieldStrength })},
+	{"hull_integrity", true, technicalSpec(func(specs *models.StarshipTechnicalSpecs) int { return specs.HullIntegrity })},
+	{"power_output", true, technicalSpec(func(specs *models.StarshipTechnicalSpecs) int { return specs.PowerOutput })},
+	{"sensor_range", true, technicalSpec(func(specs *models.StarshipTechnicalSpecs) int { return specs.SensorRange })},
+	{"attack_power", true, gameplayStat(func(stats *models.GameplayStats) int { return stats.AttackPower })},
+	{"defense", true, gameplayStat(func(stats *models.GameplayStats) int { return stats.Defense })},
+	{"speed", true, gameplayStat(func(stats *models.GameplayStats) int { return stats.Speed })},
+	{"agility", true, gameplayStat(func(stats *models.GameplayStats) int { return stats.Agility })},
+	{"accuracy", true, gameplayStat(func(stats *models.GameplayStats) int { return stats.Accuracy })},
+	{"weapon_dps", true, func(ship models.Starship) (float64, bool) {
+		if len(ship.WeaponSystems) == 0 {
+			return 0, false
+		}
+		// Rates of fire are in shots per minute
+		dps := 0.0
+		for _, weapon := range ship.WeaponSystems {
+			dps += float64(weapon.Damage*weapon.RateOfFire) / 60
+		}
+		return roundStat(dps), true
+	}},
+}
+
+// StarshipSummary identifies a compared or ranked starship
+type StarshipSummary struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Model string `json:"model"`
+	URL   string `json:"url"`
+}
+
+// ComparedStat holds one stat of every compared starship, in the order of the
+// comparison's starships. Scores are normalized to 0-100 within the comparison,
+// 100 being the best; values and scores are null where the stat is unknown.
+type ComparedStat struct {
+	Stat           string     `json:"stat"`
+	HigherIsBetter bool       `json:"higher_is_better"`
+	Values         []*float64 `json:"values"`
+	Scores         []*float64 `json:"scores"`
+	Winners        []uint     `json:"winners"`
+}
+
+// StarshipComparison is a side-by-side comparison of starships
+type StarshipComparison struct {
+	Starships []StarshipSummary `json:"starships"`
+	Stats     []ComparedStat    `json:"stats"`
+	Overall   []*float64        `json:"overall"` // mean score of each starship
+}
+
+// StarshipRanking is a starship's place in a stat ranking
+type StarshipRanking struct {
+	Rank     int             `json:"rank"`
+	Starship StarshipSummary `json:"starship"`
+	Value    float64         `json:"value"`
+}
+
+type StarshipStatsService struct {
+	db *gorm.DB
+}
+
+func NewStarshipStatsService(db *gorm.DB) *StarshipStatsService {
+	return &StarshipStatsService{db: db}
+}
+
+// StarshipStatKeys returns the stats that can be compared and ranked
+func StarshipStatKeys() []string {
+	keys := make([]string, len(starshipStats))
+	for i, stat := range starshipStats {
+		keys[i] = stat.Key
+	}
+	return keys
+}
+
+// Compare lines up the stats of the given starships, in the given order
+func (s *StarshipStatsService) Compare(ids []uint) (*StarshipComparison, error) {
+	var ships []models.Starship
+	if err := s.db.Preload("WeaponSystems").Where("id IN ?", ids).Find(&ships).Error; err != nil {
+		return nil, fmt.Errorf("failed to load starships: %w", err)
+	}
+
+	byID := make(map[uint]models.Starship, len(ships))
+	for _, ship := range ships {
+		byID[ship.ID] = ship
+	}
+
+	ordered := make([]models.Starship, 0, len(ids))
+	var missing []uint
+	for _, id := range ids {
+		ship, ok := byID[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		ordered = append(ordered, ship)
+	}
+	if len(missing) > 0 {
+		return nil, &MissingStarshipsError{IDs: missing}
+	}
+
+	comparison := &StarshipComparison{
+		Starships: make([]StarshipSummary, len(ordered)),
+		Stats:     make([]ComparedStat, 0, len(starshipStats)),
+		Overall:   make([]*float64, len(ordered)),
+	}
+	for i, ship := range ordered {
+		comparison.Starships[i] = summarizeStarship(ship)
+	}
+
+	scoreSums := make([]float64, len(ordered))
+	scoreCounts := make([]int, len(ordered))
+
+	for _, stat := range starshipStats {
+		compared := ComparedStat{
+			Stat:           stat.Key,
+			HigherIsBetter: stat.HigherIsBetter,
+			Values:         make([]*float64, len(ordered)),
+			Scores:         make([]*float64, len(ordered)),
+			Winners:        []uint{},
+		}
+
+		low, high, known := math.Inf(1), math.Inf(-1), 0
+		for i, ship := range ordered {
+			if value, ok := stat.value(ship); ok {
+				compared.Values[i] = &value
+				low, high = math.Min(low, value), math.Max(high, value)
+				known++
+			}
+		}
+		if known == 0 {
+			comparison.Stats = append(comparison.Stats, compared)
+			continue
+		}
+
+		for i, value := range compared.Values {
+			if value == nil {
+				continue
+			}
+
+			score := 100.0
+			if high > low {
+				score = (*value - low) / (high - low) * 100
+				if !stat.HigherIsBetter {
+					score = 100 - score
+				}
+			}
+			score = roundStat(score)
+			compared.Scores[i] = &score
+			scoreSums[i] += score
+			scoreCounts[i]++
+
+			if score == 100 {
+				compared.Winners = append(compared.Winners, ordered[i].ID)
+			}
+		}
+		comparison.Stats = append(comparison.Stats, compared)
+	}
+
+	for i := range ordered {
+		if scoreCounts[i] > 0 {
+			overall := roundStat(scoreSums[i] / float64(scoreCounts[i]))
+			comparison.Overall[i] = &overall
+		}
+	}
+	return comparison, nil
+}
+
+// Rankings ranks every starship with a known value of the stat, best first
+func (s *StarshipStatsService) Rankings(by string, limit int) ([]StarshipRanking, error) {
+	var stat *starshipStat
+	for i := range starshipStats {
+		if starshipStats[i].Key == by {
+			stat = &starshipStats[i]
+		}
+	}
+	if stat == nil {
+		return nil, ErrUnknownStat
+	}
+
+	var ships []models.Starship
+	if err := s.db.Preload("WeaponSystems").Order("id").Find(&ships).Error; err != nil {
+		return nil, fmt.Errorf("failed to load starships: %w", err)
+	}
+
+	rankings := make([]StarshipRanking, 0, len(ships))
+	for _, ship := range ships {
+		if value, ok := stat.value(ship); ok {
+			rankings = append(rankings, StarshipRanking{Starship: summarizeStarship(ship), Value: value})
+		}
+	}
+
+	sort.SliceStable(rankings, func(i, j int) bool {
+		if stat.HigherIsBetter {
+			return rankings[i].Value > rankings[j].Value
+		}
+		return rankings[i].Value < rankings[j].Value
+	})
+
+	// Equal values share a rank
+	for i := range rankings {
+		rankings[i].Rank = i + 1
+		if i > 0 && rankings[i].Value == rankings[i-1].Value {
+			rankings[i].Rank = rankings[i-1].Rank
+		}
+	}
+
+	if len(rankings) > limit {
+		rankings = rankings[:limit]
+	}
+	return rankings, nil
+}
+
+func summarizeStarship(ship models.Starship) StarshipSummary {
+	return StarshipSummary{ID: ship.ID, Name: ship.Name, Model: ship.Model, URL: ship.URL}
+}
+
+// technicalSpec reads a Bright Data technical spec, unknown when zero
+func technicalSpec(field func(specs *models.StarshipTechnicalSpecs) int) func(models.Starship) (float64, bool) {
+	return func(ship models.Starship) (float64, bool) {
+		if ship.TechnicalSpecs == nil || field(ship.TechnicalSpecs) == 0 {
+			return 0, false
+		}
+		return float64(field(ship.TechnicalSpecs)), true
+	}
+}
+
+// gameplayStat reads a Bright Data gameplay stat, unknown when zero
+func gameplayStat(field func(stats *models.GameplayStats) int) func(models.Starship) (float64, bool) {
+	return func(ship models.Starship) (float64, bool) {
+		if ship.GameplayStats == nil || field(ship.GameplayStats) == 0 {
+			return 0, false
+		}
+		return float64(field(ship.GameplayStats)), true
+	}
+}
+
+// swapiNumber parses a free-text SWAPI quantity
+func swapiNumber(text string) (float64, bool) {
+	return optionalNumber(database.ParseSWAPINumber(text))
+}
+
+func optionalNumber(value *float64) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	return *value, true
+}
+
+func roundStat(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"starwars-api/database"
+	"starwars-api/models"
+	"testing"
+)
+
+func TestStarshipStatsAgility(t *testing.T) {
+	db := openTestDB(t)
+	if err := database.SeedAllBrightDataEnhancements(db); err != nil {
+		t.Fatalf("failed to seed starships: %v", err)
+	}
+	s := NewStarshipStatsService(db)
+
+	// The physics configuration and the gameplay stats both have an agility,
+	// which must not overwrite each other
+	tests := []struct {
+		name     string
+		physics  float64
+		gameplay int
+	}{
+		{"Imperial Star Destroyer", 1.0, 10},
+		{"Millennium Falcon", 7.5, 75},
+		{"X-wing", 9.0, 90},
+		{"TIE Fighter", 9.5, 95},
+	}
+
+	ids := make([]uint, len(tests))
+	for i, tt := range tests {
+		var ship models.Starship
+		if err := db.Where("name = ?", tt.name).First(&ship).Error; err != nil {
+			t.Fatalf("failed to load %s: %v", tt.name, err)
+		}
+		ids[i] = ship.ID
+		if ship.PhysicsConfig == nil || ship.PhysicsConfig.Agility != tt.physics {
+			t.Errorf("%s physics agility = %+v, want %v", tt.name, ship.PhysicsConfig, tt.physics)
+		}
+		if ship.GameplayStats == nil || ship.GameplayStats.Agility != tt.gameplay {
+			t.Errorf("%s gameplay agility = %+v, want %d", tt.name, ship.GameplayStats, tt.gameplay)
+		}
+	}
+
+	comparison, err := s.Compare(ids)
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	var agility *ComparedStat
+	for i := range comparison.Stats {
+		if comparison.Stats[i].Stat == "agility" {
+			agility = &comparison.Stats[i]
+		}
+	}
+	if agility == nil {
+		t.Fatal("Compare() has no agility stat")
+	}
+	for i, tt := range tests {
+		if value := agility.Values[i]; value == nil || *value != float64(tt.gameplay) {
+			t.Errorf("compared agility of %s = %v, want %d", tt.name, value, tt.gameplay)
+		}
+	}
+	if len(agility.Winners) != 1 || agility.Winners[0] != ids[3] {
+		t.Errorf("agility winners = %v, want [%d]", agility.Winners, ids[3])
+	}
+
+	rankings, err := s.Rankings("agility", 10)
+	if err != nil {
+		t.Fatalf("Rankings: %v", err)
+	}
+	if len(rankings) != len(tests) || rankings[0].Starship.Name != "TIE Fighter" || rankings[0].Value != 95 {
+		t.Errorf("Rankings(agility) = %+v, want the four seeded starships led by the TIE Fighter at 95", rankings)
+	}
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"starwars-api/models"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// galacticDatePattern matches dates such as "19BBY", "25,000 BBY" or "c. 3.5 ABY"
+var galacticDatePattern = regexp.MustCompile(`(?i)(\d[\d,]*(?:\.\d+)?)\s*(BBY|ABY)`)
+
+// ParseGalacticYear converts a BBY/ABY date into a signed year relative to the
+// Battle of Yavin: years before it are negative, years after it are positive
+func ParseGalacticYear(date string) (float64, error) {
+	match := galacticDatePattern.FindStringSubmatch(date)
+	if match == nil {
+		return 0, fmt.Errorf("unrecognized galactic date: %q", date)
+	}
+
+	year, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid galactic year %q: %w", match[1], err)
+	}
+
+	if strings.EqualFold(match[2], "BBY") && year != 0 {
+		year = -year
+	}
+
+	return year, nil
+}
+
+// FormatGalacticYear converts a signed year back into BBY/ABY notation, writing
+// the Battle of Yavin itself as 0 BBY
+func FormatGalacticYear(year float64) string {
+	suffix := "ABY"
+	if year <= 0 {
+		suffix = "BBY"
+		year = -year
+	}
+	if year == math.Trunc(year) {
+		return fmt.Sprintf("%d %s", int64(year), suffix)
+	}
+	return fmt.Sprintf("%g %s", year, suffix)
+}
+
+// TimelineFilter narrows the timeline to a subset of entries
+type TimelineFilter struct {
+	Era          string
+	Type         string
+	From         *float64
+	To           *float64
+	IncludeFilms bool
+}
+
+// TimelineParticipant is a character taking part in a timeline entry
+type TimelineParticipant struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// TimelineEntry is a single event or film placed on the galactic timeline
+type TimelineEntry struct {
+	Kind         string                `json:"kind"` // event, film
+	Year         float64               `json:"year"`
+	Date         string                `json:"date"`
+	Name         string                `json:"name"`
+	URL          string                `json:"url"`
+	Type         string                `json:"type,omitempty"`
+	Era          string                `json:"era"`
+	Location     string                `json:"location,omitempty"`
+	Description  string                `json:"description,omitempty"`
+	Participants []TimelineParticipant `json:"participants"`
+	Films        []string              `json:"films,omitempty"`
+	Events       []string              `json:"events,omitempty"`
+}
+
+// TimelineEra describes the span of an era based on the events within it
+type TimelineEra struct {
+	Name       string  `json:"name"`
+	StartYear  float64 `json:"start_year"`
+	EndYear    float64 `json:"end_year"`
+	StartDate  string  `json:"start_date"`
+	EndDate    string  `json:"end_date"`
+	EventCount int     `json:"event_count"`
+}
+
+// Timeline is the chronologically ordered view of events, films and eras
+type Timeline struct {
+	Eras    []TimelineEra   `json:"eras"`
+	Entries []TimelineEntry `json:"entries"`
+	Count   int             `json:"count"`
+}
+
+type TimelineService struct {
+	db *gorm.DB
+}
+
+func NewTimelineService(db *gorm.DB) *TimelineService {
+	return &TimelineService{db: db}
+}
+
+// GetTimeline builds the galactic timeline in chronological order
+func (s *TimelineService) GetTimeline(filter TimelineFilter) (*Timeline, error) {
+	var events []models.Event
+	if err := s.db.Preload("Participants").Preload("Films").Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	eras := make(map[string]*TimelineEra)
+	filmYears := make(map[uint]float64)
+	filmEras := make(map[uint]string)
+	filmEvents := make(map[uint][]string)
+
+	var entries []TimelineEntry
+	for _, event := range events {
+		year, err := ParseGalacticYear(event.Date)
+		if err != nil {
+			continue
+		}
+
+		// Eras and film positions are derived from every event so that
+		// filtering the entries does not shift them
+		era, exists := eras[event.Era]
+		if !exists {
+			era = &TimelineEra{Name: event.Era, StartYear: year, EndYear: year}
+			eras[event.Era] = era
+		}
+		era.StartYear = math.Min(era.StartYear, year)
+		era.EndYear = math.Max(era.EndYear, year)
+		era.EventCount++
+
+		filmURLs := make([]string, len(event.Films))
+		for i, film := range event.Films {
+			filmURLs[i] = film.URL
+			filmEvents[film.ID] = append(filmEvents[film.ID], event.URL)
+			if current, seen := filmYears[film.ID]; !seen || year < current {
+				filmYears[film.ID] = year
+				filmEras[film.ID] = event.Era
+			}
+		}
+
+		if filter.Type != "" && !strings.EqualFold(event.Type, filter.Type) {
+			continue
+		}
+		if !filter.matches(event.Era, year) {
+			continue
+		}
+
+		participants := make([]TimelineParticipant, len(event.Participants))
+		for i, participant := range event.Participants {
+			participants[i] = TimelineParticipant{Name: participant.Name, URL: participant.URL}
+		}
+
+		entries = append(entries, TimelineEntry{
+			Kind:         "event",
+			Year:         year,
+			Date:         event.Date,
+			Name:         event.Name,
+			URL:          event.URL,
+			Type:         event.Type,
+			Era:          event.Era,
+			Location:     event.Location,
+			Description:  event.Description,
+			Participants: participants,
+			Films:        filmURLs,
+		})
+	}
+
+	// Films are placed at the earliest event they depict
+	if filter.IncludeFilms && filter.Type == "" && len(filmYears) > 0 {
+		filmIDs := make([]uint, 0, len(filmYears))
+		for id := range filmYears {
+			filmIDs = append(filmIDs, id)
+		}
+
+		var films []models.Film
+		if err := s.db.Preload("Characters").Where("id IN ?", filmIDs).Find(&films).Error; err != nil {
+			return nil, fmt.Errorf("failed to get films: %w", err)
+		}
+
+		for _, film := range films {
+			year := filmYears[film.ID]
+			if !filter.matches(filmEras[film.ID], year) {
+				continue
+			}
+
+			participants := make([]TimelineParticipant, len(film.Characters))
+			for i, character := range film.Characters {
+				participants[i] = TimelineParticipant{Name: character.Name, URL: character.URL}
+			}
+
+			entries = append(entries, TimelineEntry{
+				Kind:         "film",
+				Year:         year,
+				Date:         FormatGalacticYear(year),
+				Name:         film.Title,
+				URL:          film.URL,
+				Era:          filmEras[film.ID],
+				Description:  film.OpeningCrawl,
+				Participants: participants,
+				Events:       filmEvents[film.ID],
+			})
+		}
+	}
+
+	// Films sort before the events they depict when they share a year
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Year != entries[j].Year {
+			return entries[i].Year < entries[j].Year
+		}
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind == "film"
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	timeline := &Timeline{
+		Eras:    make([]TimelineEra, 0, len(eras)),
+		Entries: entries,
+		Count:   len(entries),
+	}
+	if timeline.Entries == nil {
+		timeline.Entries = []TimelineEntry{}
+	}
+
+	for _, era := range eras {
+		if filter.Era != "" && !strings.EqualFold(era.Name, filter.Era) {
+			continue
+		}
+		era.StartDate = FormatGalacticYear(era.StartYear)
+		era.EndDate = FormatGalacticYear(era.EndYear)
+		timeline.Eras = append(timeline.Eras, *era)
+	}
+	sort.Slice(timeline.Eras, func(i, j int) bool {
+		return timeline.Eras[i].StartYear < timeline.Eras[j].StartYear
+	})
+
+	return timeline, nil
+}
+
+// matches reports whether an entry in the given era and year passes the filter
+func (f TimelineFilter) matches(era string, year float64) bool {
+	if f.Era != "" && !strings.EqualFold(era, f.Era) {
+		return false
+	}
+	if f.From != nil && year < *f.From {
+		return false
+	}
+	if f.To != nil && year > *f.To {
+		return false
+	}
+	return true
+}
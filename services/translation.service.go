@@ -0,0 +1,349 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"starwars-api/database"
+	"starwars-api/models"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// SourceLanguage is the language the catalog and quiz are written in
+const SourceLanguage = "en"
+
+// ErrInvalidLanguage is returned for language codes that cannot hold translations
+var ErrInvalidLanguage = errors.New("invalid language")
+
+// languagePattern matches normalized language codes such as "uk" or "fil"
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// translatableEntity lists the translatable fields of an entity type
+type translatableEntity struct {
+	Table  string
+	Fields []string
+	Lists  map[string]bool // fields translated as arrays of strings
+}
+
+// translatableEntities holds the translatable entity types by API name; fields
+// use the names of the API representation
+var translatableEntities = map[string]translatableEntity{
+	"people":        {Table: "characters", Fields: []string{"name"}},
+	"films":         {Table: "films", Fields: []string{"title", "opening_crawl"}},
+	"species":       {Table: "species", Fields: []string{"name", "classification", "designation", "language"}},
+	"starships":     {Table: "starships", Fields: []string{"name", "model", "starship_class"}},
+	"vehicles":      {Table: "vehicles", Fields: []string{"name", "model", "vehicle_class"}},
+	"planets":       {Table: "planets", Fields: []string{"name", "climate", "terrain"}},
+	"organizations": {Table: "organizations", Fields: []string{"name", "type", "description", "leader"}},
+	"weapons":       {Table: "weapons", Fields: []string{"name", "type", "description"}},
+	"events":        {Table: "events", Fields: []string{"name", "location", "description", "outcome"}},
+	"quiz_questions": {
+		Table:  "quiz_questions",
+		Fields: []string{"question", "correct_answer", "wrong_answers", "hint", "explanation"},
+		Lists:  map[string]bool{"wrong_answers": true},
+	},
+}
+
+// TranslationFile is the uploaded form of a language's translations: entity
+// type, then entity ID, then field. Values are strings, or arrays of strings
+// for list fields such as a quiz question's wrong_answers.
+type TranslationFile map[string]map[string]map[string]interface{}
+
+// InvalidTranslationsError lists the problems found in a translation file
+type InvalidTranslationsError struct {
+	Problems []string
+}
+
+func (e *InvalidTranslationsError) Error() string {
+	return "invalid translations: " + strings.Join(e.Problems, "; ")
+}
+
+// LanguageSummary counts the translations of a language
+type LanguageSummary struct {
+	Language     string `json:"language"`
+	Translations int64  `json:"translations"`
+}
+
+type TranslationService struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	version   uint64
+	languages []string
+}
+
+func NewTranslationService(db *gorm.DB) *TranslationService {
+	return &TranslationService{db: db}
+}
+
+// Languages returns the languages with translations, refreshed whenever the
+// catalog version changes
+func (s *TranslationService) Languages() ([]string, error) {
+	version, _, err := database.CatalogVersion(s.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog version: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.languages == nil || s.version != version {
+		var languages []string
+		if err := s.db.Model(&models.Translation{}).Distinct("language").Order("language").Pluck("language", &languages).Error; err != nil {
+			return nil, fmt.Errorf("failed to load languages: %w", err)
+		}
+		s.version, s.languages = version, languages
+	}
+	return s.languages, nil
+}
+
+// Summaries counts the translations of every language
+func (s *TranslationService) Summaries() ([]LanguageSummary, error) {
+	summaries := []LanguageSummary{}
+	err := s.db.Model(&models.Translation{}).
+		Select("language, COUNT(*) AS translations").
+		Group("language").Order("language").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count translations: %w", err)
+	}
+	return summaries, nil
+}
+
+// Lookup returns the translated fields of the given entities, keyed by entity
+// ID and field. Fields without a translation are left out so callers fall back
+// on the English text.
+func (s *TranslationService) Lookup(entityType, language string, ids []uint) (map[uint]map[string]string, error) {
+	translated := make(map[uint]map[string]string)
+	if language == "" || language == SourceLanguage || len(ids) == 0 {
+		return translated, nil
+	}
+
+	var rows []models.Translation
+	err := s.db.Where("entity_type = ? AND language = ? AND entity_id IN ?", entityType, language, ids).Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s translations: %w", entityType, err)
+	}
+
+	for _, row := range rows {
+		if translated[row.EntityID] == nil {
+			translated[row.EntityID] = make(map[string]string)
+		}
+		translated[row.EntityID][row.Field] = row.Value
+	}
+	return translated, nil
+}
+
+// Import replaces every translation of a language with the file's. The whole
+// file is validated first, so a rejected file changes nothing.
+func (s *TranslationService) Import(language string, file TranslationFile) (int, error) {
+	if err := checkLanguage(language); err != nil {
+		return 0, err
+	}
+
+	var rows []models.Translation
+	var problems []string
+
+	entityTypes := make([]string, 0, len(file))
+	for entityType := range file {
+		entityTypes = append(entityTypes, entityType)
+	}
+	sort.Strings(entityTypes)
+
+	for _, entityType := range entityTypes {
+		entity, ok := translatableEntities[entityType]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown entity type %q", entityType))
+			continue
+		}
+
+		ids := make([]uint, 0, len(file[entityType]))
+		for key, fields := range file[entityType] {
+			id, err := strconv.ParseUint(key, 10, 32)
+			if err != nil || id == 0 {
+				problems = append(problems, fmt.Sprintf("%s: invalid ID %q", entityType, key))
+				continue
+			}
+			ids = append(ids, uint(id))
+
+			for field, value := range fields {
+				text, problem := translationValue(entity, field, value)
+				if problem != "" {
+					problems = append(problems, fmt.Sprintf("%s %d: %s", entityType, id, problem))
+					continue
+				}
+				rows = append(rows, models.Translation{
+					EntityType: entityType,
+					Language:   language,
+					EntityID:   uint(id),
+					Field:      field,
+					Value:      text,
+				})
+			}
+		}
+
+		missing, err := s.missingIDs(entity.Table, ids)
+		if err != nil {
+			return 0, err
+		}
+		for _, id := range missing {
+			problems = append(problems, fmt.Sprintf("%s: no entity with ID %d", entityType, id))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return 0, &InvalidTranslationsError{Problems: problems}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("language = ?", language).Delete(&models.Translation{}).Error; err != nil {
+			return fmt.Errorf("failed to clear translations: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(rows, 500).Error; err != nil {
+			return fmt.Errorf("failed to save translations: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// Export returns every translation of a language in the uploaded file format
+func (s *TranslationService) Export(language string) (TranslationFile, error) {
+	if err := checkLanguage(language); err != nil {
+		return nil, err
+	}
+
+	var rows []models.Translation
+	if err := s.db.Where("language = ?", language).Order("entity_type, entity_id, field").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load translations: %w", err)
+	}
+
+	file := make(TranslationFile)
+	for _, row := range rows {
+		if file[row.EntityType] == nil {
+			file[row.EntityType] = make(map[string]map[string]interface{})
+		}
+		id := strconv.FormatUint(uint64(row.EntityID), 10)
+		if file[row.EntityType][id] == nil {
+			file[row.EntityType][id] = make(map[string]interface{})
+		}
+
+		var value interface{} = row.Value
+		if translatableEntities[row.EntityType].Lists[row.Field] {
+			var list []string
+			if err := json.Unmarshal([]byte(row.Value), &list); err == nil {
+				value = list
+			}
+		}
+		file[row.EntityType][id][row.Field] = value
+	}
+	return file, nil
+}
+
+// Delete removes every translation of a language
+func (s *TranslationService) Delete(language string) (int64, error) {
+	if err := checkLanguage(language); err != nil {
+		return 0, err
+	}
+
+	result := s.db.Where("language = ?", language).Delete(&models.Translation{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete translations: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// missingIDs returns the IDs that match no row of the table
+func (s *TranslationService) missingIDs(table string, ids []uint) ([]uint, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var found []uint
+	if err := s.db.Table(table).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, fmt.Errorf("failed to check %s: %w", table, err)
+	}
+
+	exists := make(map[uint]bool, len(found))
+	for _, id := range found {
+		exists[id] = true
+	}
+
+	var missing []uint
+	for _, id := range ids {
+		if !exists[id] {
+			missing = append(missing, id)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+	return missing, nil
+}
+
+// checkLanguage accepts normalized language codes other than the source language
+func checkLanguage(language string) error {
+	if !languagePattern.MatchString(language) || language == SourceLanguage {
+		return ErrInvalidLanguage
+	}
+	return nil
+}
+
+// translationValue validates a translated field and returns the text to
+// store; list fields are stored as JSON arrays
+func translationValue(entity translatableEntity, field string, value interface{}) (string, string) {
+	known := false
+	for _, name := range entity.Fields {
+		known = known || name == field
+	}
+	if !known {
+		return "", fmt.Sprintf("field %q cannot be translated", field)
+	}
+
+	if entity.Lists[field] {
+		items, ok := value.([]interface{})
+		if !ok {
+			return "", fmt.Sprintf("%s must be an array of strings", field)
+		}
+		list := make([]string, len(items))
+		for i, item := range items {
+			text, ok := item.(string)
+			if !ok || strings.TrimSpace(text) == "" {
+				return "", fmt.Sprintf("%s must be an array of strings", field)
+			}
+			list[i] = text
+		}
+		encoded, _ := json.Marshal(list)
+		return string(encoded), ""
+	}
+
+	text, ok := value.(string)
+	if !ok || strings.TrimSpace(text) == "" {
+		return "", fmt.Sprintf("%s must be a non-empty string", field)
+	}
+	return text, ""
+}
+
+// deleteTranslations removes the translations of a deleted entity, given its table
+func deleteTranslations(tx *gorm.DB, table string, id uint) error {
+	for entityType, entity := range translatableEntities {
+		if entity.Table != table {
+			continue
+		}
+		if err := tx.Where("entity_type = ? AND entity_id = ?", entityType, id).Delete(&models.Translation{}).Error; err != nil {
+			return fmt.Errorf("failed to delete translations: %w", err)
+		}
+	}
+	return nil
+}
@@ -16,7 +16,8 @@ trap cleanup EXIT INT TERM
 
 # Start Go API server in background
 echo "📡 Starting Go API server on :8080..."
-go run main.go &
+go run -tags sqlite_fts5 main.go migrate up || exit 1
+go run -tags sqlite_fts5 main.go &
 GO_PID=$!
 
 # Wait a moment for Go server to start